@@ -1,16 +1,15 @@
 package agents
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/strict"
 )
 
 type agentsClient struct {
 	httpClient *http.Client
+	strict     *strict.Recorder
 }
 
 type agentOpts func(*agentsClient)
@@ -27,6 +26,14 @@ func WithHTTPClient(httpClient *http.Client) agentOpts {
 	}
 }
 
+// WithStrictDecoding checks every response against the SDK types and
+// records unknown or missing fields in recorder.
+func WithStrictDecoding(recorder *strict.Recorder) agentOpts {
+	return func(a *agentsClient) {
+		a.strict = recorder
+	}
+}
+
 func NewAgents(opts ...agentOpts) *agentsClient {
 	a := &agentsClient{}
 
@@ -38,56 +45,11 @@ func NewAgents(opts ...agentOpts) *agentsClient {
 	return a
 }
 
-func (a *agentsClient) NewAgent(ctx context.Context, req *NewAgentRequest) (*NewAgentResponse, error) {
-	reqBody, err := json.Marshal(req)
-	fmt.Println(string(reqBody))
-	if err != nil {
-		return nil, err
-	}
-	// TODO: add context to request
-	// TODO: use constants for URL and content type
-	httpResp, err := http.Post("https://api.spacetraders.io/v2/register", "application/json", bytes.NewReader(reqBody))
-	if err != nil {
-		return nil, err
-	}
-
-	defer httpResp.Body.Close()
-	body, err := ioutil.ReadAll(httpResp.Body) // response body is []byte
-
-	resp := &NewAgentResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (a *agentsClient) rest() *rest.Client {
+	return &rest.Client{HTTPClient: a.httpClient, Strict: a.strict}
 }
 
-func (a *agentsClient) GetAgent(ctx context.Context, req *GetAgentRequest) (*GetAgentResponse, error) {
-	// TODO: add context to request
-	// TODO: use constants for URL and content type
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/my/agent", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Add("Content-Type", "application/json")
-	httpReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", req.Token))
-
-	httpResp, err := a.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-
-	defer httpResp.Body.Close()
-	body, err := ioutil.ReadAll(httpResp.Body) // response body is []byte
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetAgentResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-	return resp, nil
+func (a *agentsClient) do(ctx context.Context, req *rest.Request, out any) error {
+	_, err := a.rest().Do(ctx, req, out)
+	return err
 }
@@ -0,0 +1,84 @@
+// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.
+
+package agents
+
+import (
+	"context"
+	"net/http"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/models"
+)
+
+type AgentsClient interface {
+	// Creates a new agent and ties it to a temporary Account.
+	NewAgent(ctx context.Context, req *NewAgentRequest) (*NewAgentResponse, error)
+	// Fetch your agent's details.
+	GetAgent(ctx context.Context, req *GetAgentRequest) (*GetAgentResponse, error)
+}
+
+type NewAgentRequest struct {
+	// Faction The faction you choose determines your headquarters.
+	Faction string `json:"faction"`
+	// Symbol How other agents will see your ships and information.
+	// >= 3 characters <= 14 characters
+	Symbol string `json:"symbol"`
+	// Email Your email address. This is used if you reserved your call sign between resets.
+	Email string `json:"email,omitempty"`
+}
+type NewAgentResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Agent    models.Agent    `json:"agent"`
+		Contract models.Contract `json:"contract"`
+		Faction  models.Faction  `json:"faction"`
+		Ship     models.Ship     `json:"ship"`
+		// Token A Bearer token for accessing secured API endpoints.
+		Token string `json:"token"`
+	} `json:"data"`
+}
+
+type GetAgentRequest struct {
+	Token string
+}
+type GetAgentResponse struct {
+	models.RawResponse
+
+	Agent models.Agent `json:"data"`
+}
+
+// Creates a new agent and ties it to a temporary Account.
+//
+// The agent symbol is a 3-14 character string that will represent your agent. This symbol will prefix the symbol of every ship you own. Agent symbols will be cast to all uppercase characters.
+//
+// A new agent will be granted an authorization token, a contract with their starting faction, a command ship with a jump drive, and one hundred thousand credits.
+func (c *agentsClient) NewAgent(ctx context.Context, req *NewAgentRequest) (*NewAgentResponse, error) {
+	resp := &NewAgentResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "NewAgent",
+		Method:    http.MethodPost,
+		Path:      "/register",
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Fetch your agent's details.
+func (c *agentsClient) GetAgent(ctx context.Context, req *GetAgentRequest) (*GetAgentResponse, error) {
+	resp := &GetAgentResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetAgent",
+		Method:    http.MethodGet,
+		Path:      "/my/agent",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
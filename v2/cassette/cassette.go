@@ -0,0 +1,368 @@
+// Package cassette records HTTP exchanges with the SpaceTraders API to
+// files and plays them back, so decoders can be tested against genuine
+// payloads and a bot's misbehaviour reproduced without the live API.
+//
+// Record once against the real API:
+//
+//	rec := cassette.NewRecorder(nil)
+//	sdk := v2.NewSpaceTradersClient(v2.WithHTTPClient(rec.Client()))
+//	... make calls ...
+//	err := rec.Cassette().Save("testdata/get_ship.json")
+//
+// and replay in tests:
+//
+//	c, err := cassette.Load("testdata/get_ship.json")
+//	sdk := v2.NewSpaceTradersClient(v2.WithHTTPClient(cassette.NewReplayer(c).Client()))
+//
+// Bearer tokens never reach the file: Authorization headers are redacted,
+// and so is every occurrence of a token in a recorded body, including the
+// one the register endpoint hands out. The email given to register is
+// redacted the same way, and responses keep only the headers a client
+// acts on, dropping cookies and the like.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secrets in recorded cassettes.
+const Redacted = "REDACTED"
+
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	// URL is the full request URL, e.g.
+	// "https://api.spacetraders.io/v2/my/ships/SHIP-1".
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Load reads a cassette written by Save.
+func Load(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("cassette: %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the cassette to path as indented JSON.
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// Find returns the first interaction for method and a URL path ending in
+// path, e.g. Find("GET", "/my/ships/SHIP-1"), for tests that decode a
+// recorded body directly.
+func (c *Cassette) Find(method, path string) (Interaction, bool) {
+	for _, in := range c.Interactions {
+		if in.Request.Method == method && strings.HasSuffix(pathOf(in.Request.URL), path) {
+			return in, true
+		}
+	}
+	return Interaction{}, false
+}
+
+// Recorder is an http.RoundTripper that passes requests to the API and
+// keeps a redacted copy of every exchange. It is safe for concurrent use.
+type Recorder struct {
+	base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	secrets      map[string]bool
+}
+
+// NewRecorder records exchanges made through base, or through
+// http.DefaultTransport when base is nil.
+func NewRecorder(base http.RoundTripper) *Recorder {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{base: base, secrets: map[string]bool{}}
+}
+
+// Client returns an *http.Client that records through r, for the SDK's
+// WithHTTPClient options.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != "" {
+		r.secrets[token] = true
+	}
+	if token := issuedToken(respBody); token != "" {
+		r.secrets[token] = true
+	}
+	if email := emailOf(reqBody); email != "" {
+		r.secrets[email] = true
+	}
+	r.interactions = append(r.interactions, Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String(), Header: req.Header.Clone(), Body: string(reqBody)},
+		Response: Response{StatusCode: resp.StatusCode, Header: resp.Header.Clone(), Body: string(respBody)},
+	})
+	return resp, nil
+}
+
+// issuedToken picks the token out of a register response.
+func issuedToken(body []byte) string {
+	var v struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(body, &v) != nil {
+		return ""
+	}
+	return v.Data.Token
+}
+
+// emailOf picks the email out of a register request.
+func emailOf(body []byte) string {
+	var v struct {
+		Email string `json:"email"`
+	}
+	if json.Unmarshal(body, &v) != nil {
+		return ""
+	}
+	return v.Email
+}
+
+// redactEmail replaces the email of a register request with Redacted, so
+// replayed requests match whatever email they carry.
+func redactEmail(body []byte) []byte {
+	if emailOf(body) == "" {
+		return body
+	}
+	var v map[string]any
+	if json.Unmarshal(body, &v) != nil {
+		return body
+	}
+	v["email"] = Redacted
+	b, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+// keptHeaders are the response headers worth recording: what decoding and
+// rate limiting act on. Anything else, Set-Cookie included, is dropped.
+var keptHeaders = []string{"Content-Type", "Retry-After"}
+
+func keepHeaders(h http.Header) http.Header {
+	kept := http.Header{}
+	for k, v := range h {
+		if strings.HasPrefix(k, "X-Ratelimit-") {
+			kept[k] = append([]string(nil), v...)
+		}
+	}
+	for _, k := range keptHeaders {
+		if v := h.Values(k); len(v) > 0 {
+			kept[k] = append([]string(nil), v...)
+		}
+	}
+	return kept
+}
+
+// Cassette returns everything recorded so far with secrets redacted.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Replace longer secrets first in case one contains another.
+	secrets := make([]string, 0, len(r.secrets))
+	for s := range r.secrets {
+		secrets = append(secrets, s)
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	redact := func(s string) string {
+		for _, secret := range secrets {
+			s = strings.ReplaceAll(s, secret, Redacted)
+		}
+		return s
+	}
+
+	c := &Cassette{}
+	for _, in := range r.interactions {
+		in.Request.Header = in.Request.Header.Clone()
+		if in.Request.Header.Get("Authorization") != "" {
+			in.Request.Header.Set("Authorization", "Bearer "+Redacted)
+		}
+		in.Request.URL = redact(in.Request.URL)
+		in.Request.Body = redact(string(redactEmail([]byte(in.Request.Body))))
+		in.Response.Header = keepHeaders(in.Response.Header)
+		in.Response.Body = redact(in.Response.Body)
+		c.Interactions = append(c.Interactions, in)
+	}
+	return c
+}
+
+// ErrNoInteraction is returned, wrapped, when a replayed request has no
+// matching interaction left on the cassette.
+var ErrNoInteraction = errors.New("cassette: no matching interaction")
+
+// Replayer is an http.RoundTripper that answers from a cassette without
+// touching the network. Requests match an interaction on method, URL path
+// and query, and body; the Authorization header and a register request's
+// email are ignored, so any token or email works. Each interaction is
+// played once, in recorded order, which lets a cassette hold the same
+// request answered differently over time. It is safe for concurrent use.
+type Replayer struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+	// Repeat lets the last matching interaction answer again once every
+	// match has been played, for polling loops.
+	Repeat bool
+}
+
+func NewReplayer(c *Cassette) *Replayer {
+	return &Replayer{cassette: c, used: make([]bool, len(c.Interactions))}
+}
+
+// Client returns an *http.Client that replays from r, for the SDK's
+// WithHTTPClient options.
+func (r *Replayer) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, in := range r.cassette.Interactions {
+		if !matches(in.Request, req, body) {
+			continue
+		}
+		last = i
+		if r.used[i] {
+			continue
+		}
+		r.used[i] = true
+		return in.Response.http(req), nil
+	}
+	if r.Repeat && last >= 0 {
+		return r.cassette.Interactions[last].Response.http(req), nil
+	}
+	return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, req.Method, req.URL)
+}
+
+func matches(rec Request, req *http.Request, body []byte) bool {
+	if rec.Method != req.Method || pathOf(rec.URL) != req.URL.RequestURI() {
+		return false
+	}
+	return sameBody(rec.Body, body) || sameBody(rec.Body, redactEmail(body))
+}
+
+// pathOf returns the path and query of a recorded URL.
+func pathOf(u string) string {
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+		if j := strings.Index(u, "/"); j >= 0 {
+			return u[j:]
+		}
+		return "/"
+	}
+	return u
+}
+
+// sameBody compares JSON bodies by value, so key order and whitespace
+// don't matter, and anything else byte for byte.
+func sameBody(recorded string, body []byte) bool {
+	if recorded == string(body) {
+		return true
+	}
+	var a, b any
+	if json.Unmarshal([]byte(recorded), &a) != nil || json.Unmarshal(body, &b) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return bytes.Equal(ja, jb)
+}
+
+func (r Response) http(req *http.Request) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -2,14 +2,14 @@ package contracts
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/strict"
 )
 
 type contractsClient struct {
 	httpClient *http.Client
+	strict     *strict.Recorder
 }
 
 type contractsCientOpt func(*contractsClient)
@@ -26,6 +26,14 @@ func WithHTTPClient(httpClient *http.Client) contractsCientOpt {
 	}
 }
 
+// WithStrictDecoding checks every response against the SDK types and
+// records unknown or missing fields in recorder.
+func WithStrictDecoding(recorder *strict.Recorder) contractsCientOpt {
+	return func(c *contractsClient) {
+		c.strict = recorder
+	}
+}
+
 func NewContracts(opts ...contractsCientOpt) *contractsClient {
 	c := &contractsClient{
 		httpClient: http.DefaultClient,
@@ -40,84 +48,11 @@ func NewContracts(opts ...contractsCientOpt) *contractsClient {
 	return c
 }
 
-// View all contracts.
-func (c *contractsClient) ListContracts(ctx context.Context, req *ListContractsRequest) (*ListContractsResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.spacetraders.io/v2/my/contracts", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", req.Token))
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &ListContractsResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-// View a specific contract.
-func (c *contractsClient) GetContract(ctx context.Context, req *GetContractRequest) (*GetContractResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://api.spacetraders.io/v2/my/contracts/%s", req.ContractID), nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", req.Token))
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetContractResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (c *contractsClient) rest() *rest.Client {
+	return &rest.Client{HTTPClient: c.httpClient, Strict: c.strict}
 }
 
-// Accept a contract.
-func (c *contractsClient) AcceptContract(ctx context.Context, req *AcceptContractRequest) (*AcceptContractResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("https://api.spacetraders.io/v2/my/contracts/%s/accept", req.ContractID), nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", req.Token))
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	resp := &AcceptContractResponse{}
-	err = json.NewDecoder(httpResp.Body).Decode(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (c *contractsClient) do(ctx context.Context, req *rest.Request, out any) error {
+	_, err := c.rest().Do(ctx, req, out)
+	return err
 }
@@ -0,0 +1,302 @@
+package contracts
+
+import (
+	"fmt"
+	"math"
+	"spacetradersgo/v2/markets"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+	"strings"
+	"time"
+)
+
+// Source is where the goods of a delivery come from: a market, or mining.
+type Source struct {
+	Waypoint string
+	Mined    bool
+	// Price is the purchase price per unit; mined goods cost nothing.
+	Price int
+}
+
+// Delivery is the plan for one of a contract's terms.Deliver goods.
+type Delivery struct {
+	Symbol      string
+	Destination string
+	// Units are the units still to deliver.
+	Units  int
+	Source Source
+	// Trips is how many loads the ship carries from Source to Destination.
+	Trips int
+	// Cost is the price of the goods and the fuel to move them.
+	Cost int
+	Fuel int
+	Time time.Duration
+}
+
+// Evaluation is Evaluate's verdict on a contract.
+type Evaluation struct {
+	Contract string
+	Accept   bool
+	// Reasons explains the verdict, one finding per line.
+	Reasons []string
+	// Revenue is OnAccepted, unless the contract is accepted already, plus
+	// OnFulfilled.
+	Revenue    int
+	Cost       int
+	Profit     int
+	Deliveries []Delivery
+	// Time is the estimated time to deliver everything, padded by the
+	// margin, and Finish when that would be.
+	Time   time.Duration
+	Finish time.Time
+}
+
+type mining struct {
+	waypoint string
+	perHour  float64
+}
+
+type evaluator struct {
+	now       time.Time
+	mode      models.ShipNavFlightMode
+	fuelPrice int
+	margin    float64
+	minProfit int
+	mining    map[string]mining
+}
+
+type evaluateOpts func(*evaluator)
+
+// WithNow sets the time the contract is evaluated at. The default is the
+// current time.
+func WithNow(now time.Time) evaluateOpts {
+	return func(e *evaluator) {
+		e.now = now
+	}
+}
+
+// WithEvaluationFlightMode sets the flight mode travel is timed and fuelled
+// for. The default is CRUISE.
+func WithEvaluationFlightMode(mode models.ShipNavFlightMode) evaluateOpts {
+	return func(e *evaluator) {
+		e.mode = mode
+	}
+}
+
+// WithFuelPrice sets the price of FUEL per market unit, as in
+// markets.WithFuelPrice. By default it is the cheapest FUEL among the known
+// prices.
+func WithFuelPrice(credits int) evaluateOpts {
+	return func(e *evaluator) {
+		e.fuelPrice = credits
+	}
+}
+
+// WithMargin pads the estimated time by a fraction, for docking, cooldowns
+// and API latency the estimates leave out. The default is 0.25.
+func WithMargin(fraction float64) evaluateOpts {
+	return func(e *evaluator) {
+		e.margin = fraction
+	}
+}
+
+// WithMinProfit declines contracts making less than credits. The default
+// is 1, any profit at all.
+func WithMinProfit(credits int) evaluateOpts {
+	return func(e *evaluator) {
+		e.minProfit = credits
+	}
+}
+
+// WithMining lets the ship source symbol by mining at waypoint, yielding
+// perHour units an hour including cooldowns.
+func WithMining(symbol, waypoint string, perHour float64) evaluateOpts {
+	return func(e *evaluator) {
+		e.mining[symbol] = mining{waypoint: waypoint, perHour: perHour}
+	}
+}
+
+// Evaluate estimates what it takes ship to fulfil contract, sourcing each
+// good at the cheapest known market, or by mining, that still makes the
+// deadline, and recommends accepting it if it makes a profit in time.
+//
+// Prices are the latest known, e.g. from a markets.History, and waypoints
+// are those of the system the work happens in. Goods are delivered one
+// after another, each in as many trips as the ship's cargo hold needs.
+func Evaluate(contract models.Contract, ship models.Ship, prices []markets.Price, waypoints []models.Waypoint, opts ...evaluateOpts) Evaluation {
+	e := &evaluator{
+		now:       time.Now(),
+		mode:      models.ShipNavFlightModeCruise,
+		fuelPrice: -1,
+		margin:    0.25,
+		minProfit: 1,
+		mining:    map[string]mining{},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.fuelPrice < 0 {
+		e.fuelPrice = 0
+		for _, p := range prices {
+			if p.Symbol == "FUEL" && p.PurchasePrice > 0 && (e.fuelPrice == 0 || p.PurchasePrice < e.fuelPrice) {
+				e.fuelPrice = p.PurchasePrice
+			}
+		}
+	}
+
+	ev := Evaluation{Contract: contract.ID, Revenue: contract.Terms.Payment.OnFulfilled}
+	if !contract.Accepted {
+		ev.Revenue += contract.Terms.Payment.OnAccepted
+	}
+	decline := func(format string, args ...any) {
+		ev.Accept = false
+		ev.Reasons = append(ev.Reasons, fmt.Sprintf(format, args...))
+	}
+	ev.Accept = true
+
+	switch {
+	case contract.Fulfilled:
+		decline("contract is fulfilled already")
+		return ev
+	case !contract.Accepted && !contract.DeadlineToAccept.IsZero() && e.now.After(contract.DeadlineToAccept):
+		decline("deadline to accept passed at %s", contract.DeadlineToAccept.Format(time.RFC3339))
+		return ev
+	case ship.Cargo.Capacity == 0:
+		decline("ship %s has no cargo hold", ship.Symbol)
+		return ev
+	}
+
+	byWaypoint := map[string]models.Waypoint{}
+	for _, wp := range waypoints {
+		byWaypoint[wp.Symbol] = wp
+	}
+	at, ok := byWaypoint[ship.Nav.WaypointSymbol]
+	if !ok {
+		ev.Reasons = append(ev.Reasons, fmt.Sprintf("ship %s is outside the system; getting there is not counted", ship.Symbol))
+	}
+
+	var elapsed time.Duration
+	for _, good := range contract.Terms.Deliver {
+		units := good.UnitsRequired - good.UnitsFulfilled
+		if units <= 0 {
+			continue
+		}
+		dest, ok := byWaypoint[good.DestinationSymbol]
+		if !ok {
+			decline("destination %s of %s is not among the waypoints", good.DestinationSymbol, good.TradeSymbol)
+			continue
+		}
+
+		budget := time.Duration(math.MaxInt64)
+		if !contract.Terms.Deadline.IsZero() {
+			budget = contract.Terms.Deadline.Sub(e.now) - e.pad(elapsed)
+		}
+		var best *Delivery
+		sources := e.sources(good.TradeSymbol, prices, byWaypoint)
+		for _, src := range sources {
+			d, ok := e.delivery(ship, at, byWaypoint[src.Waypoint], dest, good, units, src)
+			if !ok {
+				continue
+			}
+			inTime := e.pad(d.Time) <= budget
+			switch {
+			case best == nil:
+				best = &d
+			case inTime && (e.pad(best.Time) > budget || d.Cost < best.Cost):
+				best = &d
+			case !inTime && e.pad(best.Time) > budget && d.Time < best.Time:
+				best = &d
+			}
+		}
+		if best == nil && len(sources) > 0 {
+			var where []string
+			for _, src := range sources {
+				where = append(where, src.Waypoint)
+			}
+			decline("%s is sold or mined only out of reach, with a leg longer than a full tank: %s", good.TradeSymbol, strings.Join(where, ", "))
+			continue
+		}
+		if best == nil {
+			decline("no known market sells %s and it can't be mined", good.TradeSymbol)
+			continue
+		}
+
+		ev.Deliveries = append(ev.Deliveries, *best)
+		ev.Cost += best.Cost
+		elapsed += best.Time
+		at = dest
+		how := fmt.Sprintf("buy %d %s at %s for %d each", units, good.TradeSymbol, best.Source.Waypoint, best.Source.Price)
+		if best.Source.Mined {
+			how = fmt.Sprintf("mine %d %s at %s", units, good.TradeSymbol, best.Source.Waypoint)
+		}
+		ev.Reasons = append(ev.Reasons, fmt.Sprintf("%s and deliver to %s in %d trips: %d credits, %s", how, good.DestinationSymbol, best.Trips, best.Cost, best.Time.Round(time.Second)))
+	}
+
+	ev.Profit = ev.Revenue - ev.Cost
+	ev.Time = e.pad(elapsed)
+	ev.Finish = e.now.Add(ev.Time)
+	if !contract.Terms.Deadline.IsZero() && ev.Finish.After(contract.Terms.Deadline) {
+		decline("estimated finish %s misses the deadline %s", ev.Finish.Format(time.RFC3339), contract.Terms.Deadline.Format(time.RFC3339))
+	}
+	if ev.Profit < e.minProfit {
+		decline("profit of %d credits on revenue of %d is below the minimum of %d", ev.Profit, ev.Revenue, e.minProfit)
+	} else {
+		ev.Reasons = append(ev.Reasons, fmt.Sprintf("profit of %d credits on revenue of %d", ev.Profit, ev.Revenue))
+	}
+	return ev
+}
+
+func (e *evaluator) pad(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (1 + e.margin))
+}
+
+// sources lists the known markets selling symbol among the waypoints, and
+// the mining site if there is one.
+func (e *evaluator) sources(symbol string, prices []markets.Price, waypoints map[string]models.Waypoint) []Source {
+	var out []Source
+	for _, p := range prices {
+		if _, ok := waypoints[p.Waypoint]; ok && p.Symbol == symbol && p.PurchasePrice > 0 {
+			out = append(out, Source{Waypoint: p.Waypoint, Price: p.PurchasePrice})
+		}
+	}
+	if m, ok := e.mining[symbol]; ok && m.perHour > 0 {
+		if _, ok := waypoints[m.waypoint]; ok {
+			out = append(out, Source{Waypoint: m.waypoint, Mined: true})
+		}
+	}
+	return out
+}
+
+// delivery times and costs bringing units of good from src to dest in as
+// many trips as the hold needs, starting at from. It fails if a leg is
+// longer than a full tank.
+func (e *evaluator) delivery(ship models.Ship, from, src, dest models.Waypoint, good models.ContractDeliverGood, units int, source Source) (Delivery, bool) {
+	d := Delivery{
+		Symbol:      good.TradeSymbol,
+		Destination: good.DestinationSymbol,
+		Units:       units,
+		Source:      source,
+		Trips:       (units + ship.Cargo.Capacity - 1) / ship.Cargo.Capacity,
+	}
+	out := navigation.Flight(ship, src, dest, e.mode)
+	back := navigation.Flight(ship, dest, src, e.mode)
+	legs := []navigation.Estimate{out}
+	for i := 1; i < d.Trips; i++ {
+		legs = append(legs, back, out)
+	}
+	if from.Symbol != "" && from.Symbol != src.Symbol {
+		legs = append(legs, navigation.Flight(ship, from, src, e.mode))
+	}
+	for _, leg := range legs {
+		if !leg.Fits(models.ShipFuel{Capacity: ship.Fuel.Capacity, Current: ship.Fuel.Capacity}) {
+			return Delivery{}, false
+		}
+		d.Time += leg.Time
+		d.Fuel += leg.Fuel
+	}
+	if source.Mined {
+		d.Time += time.Duration(float64(units) / e.mining[good.TradeSymbol].perHour * float64(time.Hour))
+	}
+	d.Cost = units*source.Price + int(math.Round(float64(d.Fuel*e.fuelPrice)/navigation.FuelUnit))
+	return d, true
+}
@@ -0,0 +1,208 @@
+package contracts_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/contracts"
+	"spacetradersgo/v2/markets"
+	"spacetradersgo/v2/models"
+)
+
+var evalNow = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// The contract delivers to X1-T-A, where the ship is. X1-T-B is 30 away,
+// a round trip of 90s cruising at speed 25; X1-T-C is 10 away, 50s.
+var evalWaypoints = []models.Waypoint{
+	{Symbol: "X1-T-A", X: 0, Y: 0},
+	{Symbol: "X1-T-B", X: 30, Y: 0},
+	{Symbol: "X1-T-C", X: 0, Y: 10},
+}
+
+func evalShip(fuel int) models.Ship {
+	var ship models.Ship
+	ship.Symbol = "SHIP-1"
+	ship.Nav.WaypointSymbol = "X1-T-A"
+	ship.Cargo.Capacity = 40
+	ship.Fuel.Capacity = fuel
+	ship.Fuel.Current = fuel
+	ship.Engine.Speed = 25
+	return ship
+}
+
+// evalContract delivers 40 units of each good to X1-T-A by deadline, which
+// is after evalNow, or none if zero.
+func evalContract(deadline time.Duration, goods ...string) models.Contract {
+	c := models.Contract{ID: "c1"}
+	c.Terms.Payment.OnAccepted = 1000
+	c.Terms.Payment.OnFulfilled = 5000
+	if deadline > 0 {
+		c.Terms.Deadline = evalNow.Add(deadline)
+	}
+	for _, g := range goods {
+		c.Terms.Deliver = append(c.Terms.Deliver, models.ContractDeliverGood{TradeSymbol: g, DestinationSymbol: "X1-T-A", UnitsRequired: 40})
+	}
+	return c
+}
+
+func sells(waypoint, symbol string, price int) markets.Price {
+	return markets.Price{System: "X1-T", Waypoint: waypoint, Symbol: symbol, PurchasePrice: price}
+}
+
+// B sells cheap and C dear.
+var evalPrices = []markets.Price{
+	sells("X1-T-B", "IRON_ORE", 10),
+	sells("X1-T-C", "IRON_ORE", 20),
+	sells("X1-T-B", "COPPER_ORE", 10),
+	sells("X1-T-C", "COPPER_ORE", 20),
+}
+
+func sources(ev contracts.Evaluation) []string {
+	var out []string
+	for _, d := range ev.Deliveries {
+		out = append(out, d.Symbol+"@"+d.Source.Waypoint)
+	}
+	return out
+}
+
+func hasReason(ev contracts.Evaluation, s string) bool {
+	for _, r := range ev.Reasons {
+		if strings.Contains(r, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEvaluateSourceChoice(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		contract models.Contract
+		want     string
+		accept   bool
+	}{
+		{"cheapest when both make it", evalContract(time.Hour, "IRON_ORE"), "IRON_ORE@X1-T-B", true},
+		{"cheapest that makes it", evalContract(time.Minute, "IRON_ORE"), "IRON_ORE@X1-T-C", true},
+		{"fastest when none makes it", evalContract(30*time.Second, "IRON_ORE"), "IRON_ORE@X1-T-C", false},
+		{"no deadline", evalContract(0, "IRON_ORE"), "IRON_ORE@X1-T-B", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ev := contracts.Evaluate(tc.contract, evalShip(400), evalPrices, evalWaypoints, contracts.WithNow(evalNow), contracts.WithMargin(0))
+			if got := sources(ev); len(got) != 1 || got[0] != tc.want {
+				t.Errorf("sources = %v, want %s", got, tc.want)
+			}
+			if ev.Accept != tc.accept {
+				t.Errorf("Accept = %v, want %v; reasons %q", ev.Accept, tc.accept, ev.Reasons)
+			}
+			if !tc.accept && !hasReason(ev, "misses the deadline") {
+				t.Errorf("reasons %q don't mention the deadline", ev.Reasons)
+			}
+		})
+	}
+}
+
+func TestEvaluateBudgetsDeadlineAcrossDeliveries(t *testing.T) {
+	// Iron is sold only at B and takes 90s, leaving copper the rest.
+	prices := append([]markets.Price{sells("X1-T-B", "IRON_ORE", 10)}, evalPrices[2:]...)
+	for _, tc := range []struct {
+		deadline time.Duration
+		margin   float64
+		want     string
+	}{
+		{200 * time.Second, 0, "COPPER_ORE@X1-T-B"},
+		{150 * time.Second, 0, "COPPER_ORE@X1-T-C"},
+		// Padded by half, iron takes 135s and B's 90s copper 135s more.
+		{250 * time.Second, 0.5, "COPPER_ORE@X1-T-C"},
+		{300 * time.Second, 0.5, "COPPER_ORE@X1-T-B"},
+	} {
+		ev := contracts.Evaluate(evalContract(tc.deadline, "IRON_ORE", "COPPER_ORE"), evalShip(400), prices, evalWaypoints,
+			contracts.WithNow(evalNow), contracts.WithMargin(tc.margin))
+		got := sources(ev)
+		if len(got) != 2 || got[0] != "IRON_ORE@X1-T-B" || got[1] != tc.want {
+			t.Errorf("deadline %v, margin %v: sources = %v, want iron at B then %s", tc.deadline, tc.margin, got, tc.want)
+		}
+		var elapsed time.Duration
+		for _, d := range ev.Deliveries {
+			elapsed += d.Time
+		}
+		if want := time.Duration(float64(elapsed) * (1 + tc.margin)); ev.Time != want || !ev.Finish.Equal(evalNow.Add(want)) {
+			t.Errorf("deadline %v: Time = %v, Finish = %v, want %v padded", tc.deadline, ev.Time, ev.Finish, want)
+		}
+		if !ev.Accept {
+			t.Errorf("deadline %v: declined: %q", tc.deadline, ev.Reasons)
+		}
+	}
+}
+
+func TestEvaluateOnAccepted(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		accepted    bool
+		toAccept    time.Duration
+		wantRevenue int
+		wantAccept  bool
+	}{
+		{"offered", false, time.Hour, 6000, true},
+		{"accepted", true, time.Hour, 5000, true},
+		{"offer expired", false, -time.Hour, 6000, false},
+		{"accepted before the offer expired", true, -time.Hour, 5000, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := evalContract(time.Hour, "IRON_ORE")
+			c.Accepted = tc.accepted
+			c.DeadlineToAccept = evalNow.Add(tc.toAccept)
+			ev := contracts.Evaluate(c, evalShip(400), evalPrices, evalWaypoints, contracts.WithNow(evalNow))
+			if ev.Revenue != tc.wantRevenue || ev.Accept != tc.wantAccept {
+				t.Errorf("Revenue = %d, Accept = %v, want %d and %v; reasons %q", ev.Revenue, ev.Accept, tc.wantRevenue, tc.wantAccept, ev.Reasons)
+			}
+			if ev.Accept && ev.Profit != tc.wantRevenue-400 {
+				t.Errorf("Profit = %d, want %d", ev.Profit, tc.wantRevenue-400)
+			}
+		})
+	}
+}
+
+func TestEvaluateMining(t *testing.T) {
+	// 40 units at 120 an hour take 20 minutes on top of the 90s round trip.
+	ev := contracts.Evaluate(evalContract(0, "IRON_ORE"), evalShip(400), nil, evalWaypoints,
+		contracts.WithNow(evalNow), contracts.WithMargin(0), contracts.WithFuelPrice(100),
+		contracts.WithMining("IRON_ORE", "X1-T-B", 120))
+	if len(ev.Deliveries) != 1 {
+		t.Fatalf("deliveries = %+v, want one; reasons %q", ev.Deliveries, ev.Reasons)
+	}
+	d := ev.Deliveries[0]
+	if !d.Source.Mined || d.Source.Waypoint != "X1-T-B" {
+		t.Errorf("source = %+v, want mined at X1-T-B", d.Source)
+	}
+	if want := 20*time.Minute + 90*time.Second; d.Time != want {
+		t.Errorf("Time = %v, want %v", d.Time, want)
+	}
+	// Mined goods cost only the 60 fuel there and back.
+	if d.Fuel != 60 || d.Cost != 60 {
+		t.Errorf("Fuel = %d, Cost = %d, want 60 and 60", d.Fuel, d.Cost)
+	}
+	if !hasReason(ev, "mine 40 IRON_ORE at X1-T-B") {
+		t.Errorf("reasons %q don't mention mining", ev.Reasons)
+	}
+}
+
+func TestEvaluateNoSource(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		fuel   int
+		prices []markets.Price
+		want   string
+	}{
+		{"nowhere", 400, []markets.Price{sells("X1-T-B", "COPPER_ORE", 10)}, "no known market sells IRON_ORE"},
+		{"not among waypoints", 400, []markets.Price{sells("X1-T-Z", "IRON_ORE", 10)}, "no known market sells IRON_ORE"},
+		{"beyond a full tank", 20, []markets.Price{sells("X1-T-B", "IRON_ORE", 10)}, "IRON_ORE is sold or mined only out of reach"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ev := contracts.Evaluate(evalContract(0, "IRON_ORE"), evalShip(tc.fuel), tc.prices, evalWaypoints, contracts.WithNow(evalNow))
+			if ev.Accept || !hasReason(ev, tc.want) {
+				t.Errorf("Accept = %v, reasons %q, want declined with %q", ev.Accept, ev.Reasons, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,815 @@
+package contracts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/internal/wait"
+	"spacetradersgo/v2/markets"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+	"spacetradersgo/v2/systems"
+	"sync"
+	"time"
+)
+
+// ErrNoSource is returned, wrapped, when a good to deliver can be neither
+// bought at a known market nor mined.
+var ErrNoSource = errors.New("contracts: no source")
+
+// Phase is what a ship is doing for a contract.
+type Phase string
+
+const (
+	// PhaseSourcing is flying to a market or mining site and filling the
+	// hold there.
+	PhaseSourcing Phase = "SOURCING"
+	// PhaseDelivering is flying to the destination and delivering.
+	PhaseDelivering Phase = "DELIVERING"
+	// PhaseDone means nothing is left for the ship to do.
+	PhaseDone Phase = "DONE"
+)
+
+// ShipProgress is one ship's part in a contract.
+type ShipProgress struct {
+	Phase  Phase  `json:"phase"`
+	Symbol string `json:"symbol,omitempty"`
+	// Source is the market or mining site being used.
+	Source    string `json:"source,omitempty"`
+	Delivered int    `json:"delivered"`
+}
+
+// Progress is what a Workflow persists about a contract. The contract's
+// terms and the ships' cargo and nav are fetched again on every run; this
+// records what they can't tell.
+type Progress struct {
+	Contract  string                   `json:"contract"`
+	Ships     map[string]*ShipProgress `json:"ships"`
+	Fulfilled bool                     `json:"fulfilled"`
+	// Next is the contract negotiated once this one was fulfilled.
+	Next    string    `json:"next,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// ProgressStore persists Progress between runs.
+type ProgressStore interface {
+	// Load returns the contract's progress, or nil and no error if there
+	// is none.
+	Load(contract string) (*Progress, error)
+	Save(p *Progress) error
+}
+
+// FileProgress keeps one JSON file per contract in Dir.
+type FileProgress struct {
+	Dir string
+}
+
+func (f FileProgress) path(contract string) string {
+	return filepath.Join(f.Dir, contract+".json")
+}
+
+func (f FileProgress) Load(contract string) (*Progress, error) {
+	b, err := os.ReadFile(f.path(contract))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := &Progress{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("contracts: progress %s: %w", f.path(contract), err)
+	}
+	return p, nil
+}
+
+// Save writes the progress to a temporary file first and renames it into
+// place, so a crash never leaves a torn file behind.
+func (f FileProgress) Save(p *Progress) error {
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.path(p.Contract) + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path(p.Contract))
+}
+
+// Action is something a Workflow did.
+type Action string
+
+const (
+	ActionPurchased  Action = "PURCHASED"
+	ActionExtracted  Action = "EXTRACTED"
+	ActionJettisoned Action = "JETTISONED"
+	ActionDelivered  Action = "DELIVERED"
+	ActionDone       Action = "DONE"
+	ActionFulfilled  Action = "FULFILLED"
+	ActionNegotiated Action = "NEGOTIATED"
+)
+
+// WorkflowEvent reports an Action of a Workflow. Ship is empty for
+// contract-wide actions.
+type WorkflowEvent struct {
+	Contract string
+	Ship     string
+	Action   Action
+	Symbol   string
+	Units    int
+	Waypoint string
+}
+
+// Workflow carries an accepted contract through to the end: each assigned
+// ship buys or mines the goods to deliver, flies them to their destination
+// and delivers them, trip after trip, until every good is fulfilled; then
+// the contract is fulfilled and the next one negotiated.
+//
+// Ships fly with a navigation.Executor, refuelling on the way. Goods are
+// mined at the sites given with WithMiningSite, or else bought at the
+// cheapest market in the History given with WithPrices.
+//
+// With WithProgressStore and WithRouteCheckpoints, a Workflow that is
+// stopped, by a crash or a cancelled context, picks up where it was on the
+// next Run: cargo, nav and the contract's fulfilled units come from the
+// API, and flights resume mid-route.
+type Workflow struct {
+	contracts ContractsClient
+	fleets    fleets.FleetsClient
+	systems   systems.SystemsClient
+	token     string
+
+	store       ProgressStore
+	checkpoints navigation.CheckpointStore
+	prices      *markets.History
+	mining      map[string]string
+	negotiate   bool
+	events      func(WorkflowEvent)
+
+	mu        sync.Mutex
+	progress  *Progress
+	contract  models.Contract
+	held      map[string]map[string]int
+	reserved  map[string]reservation
+	waypoints map[string][]models.Waypoint
+	stations  map[string][]string
+}
+
+// reservation is the load of a good a ship has set out to deliver: what it
+// holds and what it is still buying or mining. Other ships leave it out of
+// what they fetch until it is delivered or the ship gives up.
+type reservation struct {
+	symbol string
+	units  int
+}
+
+type workflowOpts func(*Workflow)
+
+var (
+	defaultWorkflowOpts = []workflowOpts{
+		WithNegotiation(true),
+	}
+)
+
+// WithProgressStore persists progress to store.
+func WithProgressStore(store ProgressStore) workflowOpts {
+	return func(w *Workflow) {
+		w.store = store
+	}
+}
+
+// WithRouteCheckpoints lets flights cut short resume mid-route.
+func WithRouteCheckpoints(store navigation.CheckpointStore) workflowOpts {
+	return func(w *Workflow) {
+		w.checkpoints = store
+	}
+}
+
+// WithPrices sets where to look for markets to buy goods at.
+func WithPrices(history *markets.History) workflowOpts {
+	return func(w *Workflow) {
+		w.prices = history
+	}
+}
+
+// WithMiningSite mines symbol at waypoint instead of buying it. The
+// assigned ships need mining mounts and the site needs deposits of symbol:
+// anything else extracted is jettisoned and mining goes on.
+func WithMiningSite(symbol, waypoint string) workflowOpts {
+	return func(w *Workflow) {
+		w.mining[symbol] = waypoint
+	}
+}
+
+// WithNegotiation sets whether to negotiate the next contract after
+// fulfilling one. It is on by default.
+func WithNegotiation(negotiate bool) workflowOpts {
+	return func(w *Workflow) {
+		w.negotiate = negotiate
+	}
+}
+
+// WithWorkflowEvents calls fn with every action taken. fn may be called
+// from several goroutines at once.
+func WithWorkflowEvents(fn func(WorkflowEvent)) workflowOpts {
+	return func(w *Workflow) {
+		w.events = fn
+	}
+}
+
+// NewWorkflow returns a Workflow acting for the agent behind token.
+func NewWorkflow(contractsClient ContractsClient, fleetsClient fleets.FleetsClient, systemsClient systems.SystemsClient, token string, opts ...workflowOpts) *Workflow {
+	w := &Workflow{
+		contracts: contractsClient,
+		fleets:    fleetsClient,
+		systems:   systemsClient,
+		token:     token,
+		mining:    map[string]string{},
+	}
+	for _, opt := range append(defaultWorkflowOpts, opts...) {
+		opt(w)
+	}
+	return w
+}
+
+// Run works the contract with ships until it is fulfilled, and negotiates
+// the next one, whose ID ends up in the returned Progress. Without ships,
+// the ships of the saved progress carry on.
+func (w *Workflow) Run(ctx context.Context, contractID string, ships ...string) (*Progress, error) {
+	if err := w.load(contractID, ships); err != nil {
+		return nil, err
+	}
+	if !w.progress.Fulfilled {
+		resp, err := w.contracts.GetContract(ctx, &GetContractRequest{Token: w.token, ContractID: contractID})
+		if err != nil {
+			return w.snapshot(), err
+		}
+		switch {
+		case !resp.Contract.Accepted:
+			return w.snapshot(), fmt.Errorf("contracts: contract %s has not been accepted", contractID)
+		case resp.Contract.Fulfilled:
+			w.mu.Lock()
+			w.progress.Fulfilled = true
+			err := w.save()
+			w.mu.Unlock()
+			if err != nil {
+				return w.snapshot(), err
+			}
+		default:
+			w.contract = resp.Contract
+			if err := w.work(ctx); err != nil {
+				return w.snapshot(), err
+			}
+			if err := w.fulfil(ctx); err != nil {
+				return w.snapshot(), err
+			}
+		}
+	}
+	if w.negotiate && w.progress.Next == "" {
+		if err := w.negotiateNext(ctx); err != nil {
+			return w.snapshot(), err
+		}
+	}
+	return w.snapshot(), nil
+}
+
+func (w *Workflow) load(contractID string, ships []string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.progress = nil
+	w.held = map[string]map[string]int{}
+	w.reserved = map[string]reservation{}
+	w.waypoints = map[string][]models.Waypoint{}
+	w.stations = map[string][]string{}
+	if w.store != nil {
+		p, err := w.store.Load(contractID)
+		if err != nil {
+			return err
+		}
+		w.progress = p
+	}
+	if w.progress == nil {
+		w.progress = &Progress{Contract: contractID, Ships: map[string]*ShipProgress{}}
+	}
+	for _, ship := range ships {
+		if _, ok := w.progress.Ships[ship]; !ok {
+			w.progress.Ships[ship] = &ShipProgress{Phase: PhaseSourcing}
+		}
+	}
+	if len(w.progress.Ships) == 0 {
+		return fmt.Errorf("contracts: no ships assigned to contract %s", contractID)
+	}
+	return w.save()
+}
+
+// work runs every ship until there is nothing left for any of them to do.
+func (w *Workflow) work(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(w.progress.Ships))
+	for ship := range w.progress.Ships {
+		wg.Add(1)
+		go func(ship string) {
+			defer wg.Done()
+			if err := w.runShip(ctx, ship); err != nil {
+				errs <- fmt.Errorf("contracts: ship %s: %w", ship, err)
+				cancel()
+			}
+		}(ship)
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+func (w *Workflow) runShip(ctx context.Context, ship string) error {
+	defer w.release(ship)
+	executor := w.executor()
+	if w.checkpoints != nil {
+		if _, err := executor.Resume(ctx, ship); err != nil && !errors.Is(err, navigation.ErrNoCheckpoint) {
+			return err
+		}
+	}
+
+	for {
+		resp, err := w.fleets.GetShip(ctx, &fleets.GetShipRequest{Token: w.token, ShipID: ship})
+		if err != nil {
+			return err
+		}
+		cargo := resp.Ship.Cargo
+		w.setHeld(ship, cargo)
+
+		good, own, target := w.next(ship, cargo)
+		free := cargo.Capacity - cargo.Units
+		switch {
+		case good == nil:
+			return w.done(ship)
+		case own > 0 && (free == 0 || target <= 0):
+			if err := w.deliver(ctx, resp.Ship, *good, own); err != nil {
+				return err
+			}
+		case target <= 0:
+			// Other ships carry what is left of the contract.
+			return w.done(ship)
+		default:
+			if free == 0 {
+				return fmt.Errorf("cargo hold is full of goods the contract doesn't need")
+			}
+			if target > free {
+				target = free
+			}
+			if err := w.source(ctx, resp.Ship, *good, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *Workflow) done(ship string) error {
+	w.release(ship)
+	if err := w.setPhase(ship, PhaseDone, "", ""); err != nil {
+		return err
+	}
+	w.emit(WorkflowEvent{Ship: ship, Action: ActionDone})
+	return nil
+}
+
+// next picks the good ship should work on, how many units of it the ship
+// holds, and how many more it should fetch, leaving out what other ships
+// carry or have reserved. A ship holding a needed good sticks with it. The
+// pick, up to the ship's free cargo space, is reserved for the ship.
+func (w *Workflow) next(ship string, cargo models.ShipCargo) (*models.ContractDeliverGood, int, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.reserved, ship)
+	var pick *models.ContractDeliverGood
+	var pickOwn, pickTarget int
+	for i := range w.contract.Terms.Deliver {
+		good := &w.contract.Terms.Deliver[i]
+		remaining := good.UnitsRequired - good.UnitsFulfilled
+		if remaining <= 0 {
+			continue
+		}
+		own := w.held[ship][good.TradeSymbol]
+		others := w.committed(ship, good.TradeSymbol)
+		target := remaining - others - own
+		if own > remaining-others {
+			own = remaining - others
+		}
+		if own <= 0 && target <= 0 {
+			continue
+		}
+		if pick == nil || own > pickOwn {
+			pick, pickOwn, pickTarget = good, own, target
+		}
+	}
+	if pick == nil {
+		return nil, 0, 0
+	}
+	units := pickOwn
+	if free := cargo.Capacity - cargo.Units; pickTarget > 0 && free > 0 {
+		if pickTarget < free {
+			units += pickTarget
+		} else {
+			units += free
+		}
+	}
+	w.reserved[ship] = reservation{symbol: pick.TradeSymbol, units: units}
+	good := *pick
+	return &good, pickOwn, pickTarget
+}
+
+// committed counts the units of symbol that ships other than ship hold or
+// have reserved; w.mu must be held.
+func (w *Workflow) committed(ship, symbol string) int {
+	total := 0
+	for s := range w.progress.Ships {
+		if s == ship {
+			continue
+		}
+		units := w.held[s][symbol]
+		if r, ok := w.reserved[s]; ok && r.symbol == symbol && r.units > units {
+			units = r.units
+		}
+		total += units
+	}
+	return total
+}
+
+// release drops the reservation of ship.
+func (w *Workflow) release(ship string) {
+	w.mu.Lock()
+	delete(w.reserved, ship)
+	w.mu.Unlock()
+}
+
+func (w *Workflow) source(ctx context.Context, ship models.Ship, good models.ContractDeliverGood, units int) error {
+	if site, ok := w.mining[good.TradeSymbol]; ok {
+		if err := w.setPhase(ship.Symbol, PhaseSourcing, good.TradeSymbol, site); err != nil {
+			return err
+		}
+		if err := w.travel(ctx, ship.Symbol, site); err != nil {
+			return err
+		}
+		return w.mine(ctx, ship.Symbol, good.TradeSymbol, units)
+	}
+
+	market, ok := w.market(good)
+	if !ok {
+		return fmt.Errorf("%w for %s: no known market sells it and no mining site is set", ErrNoSource, good.TradeSymbol)
+	}
+	if err := w.setPhase(ship.Symbol, PhaseSourcing, good.TradeSymbol, market.Waypoint); err != nil {
+		return err
+	}
+	if err := w.travel(ctx, ship.Symbol, market.Waypoint); err != nil {
+		return err
+	}
+	if _, err := fleets.EnsureDocked(ctx, w.fleets, w.token, ship.Symbol); err != nil {
+		return err
+	}
+	batch := market.TradeVolume
+	if batch <= 0 {
+		batch = units
+	}
+	for left := units; left > 0; {
+		n := left
+		if n > batch {
+			n = batch
+		}
+		resp, err := w.fleets.PurchaseCargo(ctx, &fleets.PurchaseCargoRequest{Token: w.token, ShipID: ship.Symbol, Symbol: good.TradeSymbol, Units: n})
+		if err != nil {
+			return err
+		}
+		w.setHeld(ship.Symbol, resp.Data.Cargo)
+		w.emit(WorkflowEvent{Ship: ship.Symbol, Action: ActionPurchased, Symbol: good.TradeSymbol, Units: n, Waypoint: market.Waypoint})
+		left -= n
+	}
+	return nil
+}
+
+// market is the cheapest known market selling good in the system of its
+// destination.
+func (w *Workflow) market(good models.ContractDeliverGood) (markets.Price, bool) {
+	if w.prices == nil {
+		return markets.Price{}, false
+	}
+	var best markets.Price
+	for _, p := range w.prices.LatestInSystem(markets.SystemOf(good.DestinationSymbol), good.TradeSymbol) {
+		if p.PurchasePrice > 0 && (best.Waypoint == "" || p.PurchasePrice < best.PurchasePrice) {
+			best = p
+		}
+	}
+	return best, best.Waypoint != ""
+}
+
+// mine extracts until the ship holds units more of symbol or its hold is
+// full, jettisoning each yield the contract doesn't need. Cargo the ship
+// already carried is left alone.
+func (w *Workflow) mine(ctx context.Context, ship, symbol string, units int) error {
+	if _, err := fleets.EnsureInOrbit(ctx, w.fleets, w.token, ship); err != nil {
+		return err
+	}
+	start := w.holding(ship, symbol)
+	for {
+		if err := w.waitCooldown(ctx, ship); err != nil {
+			return err
+		}
+		resp, err := w.fleets.ExtractResource(ctx, &fleets.ExtractResourceRequest{Token: w.token, ShipID: ship})
+		if models.IsCode(err, models.ErrCodeCooldownConflict) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		y := resp.Data.Extraction.Yield
+		w.emit(WorkflowEvent{Ship: ship, Action: ActionExtracted, Symbol: y.Symbol, Units: y.Units})
+
+		cargo := resp.Data.Cargo
+		if !w.needed(y.Symbol) {
+			jr, err := w.fleets.Jettison(ctx, &fleets.JettisonRequest{Token: w.token, ShipID: ship, Symbol: y.Symbol, Units: y.Units})
+			if err != nil {
+				return err
+			}
+			cargo = jr.Data.Cargo
+			w.emit(WorkflowEvent{Ship: ship, Action: ActionJettisoned, Symbol: y.Symbol, Units: y.Units})
+		}
+		w.setHeld(ship, cargo)
+		if w.holding(ship, symbol)-start >= units || cargo.Units >= cargo.Capacity {
+			return nil
+		}
+	}
+}
+
+func (w *Workflow) waitCooldown(ctx context.Context, ship string) error {
+	resp, err := w.fleets.GetShipCooldown(ctx, &fleets.GetShipCooldownRequest{Token: w.token, ShipID: ship})
+	if err != nil || !resp.IsOnCooldown || resp.Cooldown.RemainingSeconds <= 0 {
+		return err
+	}
+	return wait.For(ctx, time.Duration(resp.Cooldown.RemainingSeconds)*time.Second+500*time.Millisecond)
+}
+
+func (w *Workflow) deliver(ctx context.Context, ship models.Ship, good models.ContractDeliverGood, units int) error {
+	if err := w.setPhase(ship.Symbol, PhaseDelivering, good.TradeSymbol, ""); err != nil {
+		return err
+	}
+	if err := w.travel(ctx, ship.Symbol, good.DestinationSymbol); err != nil {
+		return err
+	}
+	if _, err := fleets.EnsureDocked(ctx, w.fleets, w.token, ship.Symbol); err != nil {
+		return err
+	}
+	resp, err := w.contracts.DeliverContract(ctx, &DeliverContractRequest{
+		Token:       w.token,
+		ContractID:  w.progress.Contract,
+		ShipSymbol:  ship.Symbol,
+		TradeSymbol: good.TradeSymbol,
+		Units:       units,
+	})
+	if err != nil {
+		return err
+	}
+	if state, ok := w.fleets.(interface{ SetCargo(string, fleets.Cargo) }); ok {
+		state.SetCargo(ship.Symbol, resp.Data.Cargo)
+	}
+	w.setHeld(ship.Symbol, resp.Data.Cargo)
+
+	w.mu.Lock()
+	w.contract = resp.Data.Contract
+	w.progress.Ships[ship.Symbol].Delivered += units
+	delete(w.reserved, ship.Symbol)
+	err = w.save()
+	w.mu.Unlock()
+	w.emit(WorkflowEvent{Ship: ship.Symbol, Action: ActionDelivered, Symbol: good.TradeSymbol, Units: units, Waypoint: good.DestinationSymbol})
+	return err
+}
+
+// travel flies the ship to waypoint within its system, planning the route
+// with the system's waypoints and refuelling at its markets that trade
+// FUEL.
+func (w *Workflow) travel(ctx context.Context, ship, waypoint string) error {
+	resp, err := w.fleets.GetShip(ctx, &fleets.GetShipRequest{Token: w.token, ShipID: ship})
+	if err != nil {
+		return err
+	}
+	if resp.Ship.Nav.WaypointSymbol == waypoint && resp.Ship.Nav.Status != models.ShipNavStatusInTransit {
+		return nil
+	}
+	waypoints, err := w.systemWaypoints(ctx, resp.Ship.Nav.SystemSymbol)
+	if err != nil {
+		return err
+	}
+	stations, err := w.fuelStations(ctx, resp.Ship.Nav.SystemSymbol, waypoints)
+	if err != nil {
+		return err
+	}
+	plan, err := navigation.PlanRoute(resp.Ship, waypoints, waypoint, navigation.WithFuelStations(stations...))
+	if err != nil {
+		return err
+	}
+	_, err = w.executor().Execute(ctx, ship, plan)
+	return err
+}
+
+func (w *Workflow) systemWaypoints(ctx context.Context, system string) ([]models.Waypoint, error) {
+	w.mu.Lock()
+	cached, ok := w.waypoints[system]
+	w.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+	var waypoints []models.Waypoint
+	for page := 1; ; page++ {
+		resp, err := w.systems.ListWaypoints(ctx, &systems.ListWaypointsRequest{Token: w.token, SystemID: system, Page: page, NumPerPage: 20})
+		if err != nil {
+			return nil, err
+		}
+		waypoints = append(waypoints, resp.Waypoints...)
+		if len(resp.Waypoints) == 0 || len(waypoints) >= resp.Meta.Total {
+			break
+		}
+	}
+	w.mu.Lock()
+	w.waypoints[system] = waypoints
+	w.mu.Unlock()
+	return waypoints, nil
+}
+
+// fuelStations returns the waypoints among waypoints, all of system, whose
+// markets trade FUEL.
+func (w *Workflow) fuelStations(ctx context.Context, system string, waypoints []models.Waypoint) ([]string, error) {
+	w.mu.Lock()
+	cached, ok := w.stations[system]
+	w.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+	stations := []string{}
+	for _, wp := range waypoints {
+		if !hasTrait(wp, "MARKETPLACE") {
+			continue
+		}
+		resp, err := w.systems.GetMarket(ctx, &systems.GetMarketRequest{Token: w.token, SystemID: system, WaypointID: wp.Symbol})
+		if err != nil {
+			return nil, err
+		}
+		if navigation.SellsFuel(resp.Market) {
+			stations = append(stations, wp.Symbol)
+		}
+	}
+	w.mu.Lock()
+	w.stations[system] = stations
+	w.mu.Unlock()
+	return stations, nil
+}
+
+func hasTrait(wp models.Waypoint, symbol string) bool {
+	for _, t := range wp.Traits {
+		if string(t.Symbol) == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) executor() *navigation.Executor {
+	if w.checkpoints != nil {
+		return navigation.NewExecutor(w.fleets, w.token, navigation.WithCheckpoints(w.checkpoints))
+	}
+	return navigation.NewExecutor(w.fleets, w.token)
+}
+
+func (w *Workflow) fulfil(ctx context.Context) error {
+	w.mu.Lock()
+	for _, good := range w.contract.Terms.Deliver {
+		if good.UnitsFulfilled < good.UnitsRequired {
+			w.mu.Unlock()
+			return fmt.Errorf("contracts: contract %s still needs %d %s", w.contract.ID, good.UnitsRequired-good.UnitsFulfilled, good.TradeSymbol)
+		}
+	}
+	w.mu.Unlock()
+
+	_, err := w.contracts.FulfillContract(ctx, &FulfillContractRequest{Token: w.token, ContractID: w.progress.Contract})
+	if err != nil && !models.IsCode(err, models.ErrCodeContractFulfilled) {
+		return err
+	}
+	w.mu.Lock()
+	w.progress.Fulfilled = true
+	err = w.save()
+	w.mu.Unlock()
+	w.emit(WorkflowEvent{Action: ActionFulfilled})
+	return err
+}
+
+// negotiateNext negotiates a new contract with one of the ships, preferring
+// one already docked, since negotiating needs a docked ship at a waypoint
+// of a faction. Ties go to the first ship by symbol.
+func (w *Workflow) negotiateNext(ctx context.Context) error {
+	ships := make([]string, 0, len(w.progress.Ships))
+	for s := range w.progress.Ships {
+		ships = append(ships, s)
+	}
+	sort.Strings(ships)
+	ship := ships[0]
+	for _, s := range ships {
+		nav, err := w.fleets.GetShipNav(ctx, &fleets.GetShipNavRequest{Token: w.token, ShipID: s})
+		if err != nil {
+			return err
+		}
+		if nav.Nav.Status == models.ShipNavStatusDocked {
+			ship = s
+			break
+		}
+	}
+	if _, err := fleets.EnsureDocked(ctx, w.fleets, w.token, ship); err != nil {
+		return err
+	}
+	resp, err := w.fleets.NegotiateContract(ctx, &fleets.NegotiateContractRequest{Token: w.token, ShipID: ship})
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.progress.Next = resp.Data.Contract.ID
+	err = w.save()
+	w.mu.Unlock()
+	w.emit(WorkflowEvent{Ship: ship, Action: ActionNegotiated})
+	return err
+}
+
+// needed reports whether the contract still needs symbol.
+func (w *Workflow) needed(symbol string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, good := range w.contract.Terms.Deliver {
+		if good.TradeSymbol == symbol && good.UnitsFulfilled < good.UnitsRequired {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) setHeld(ship string, cargo models.ShipCargo) {
+	held := map[string]int{}
+	for _, item := range cargo.Inventory {
+		held[item.Symbol] += item.Units
+	}
+	w.mu.Lock()
+	w.held[ship] = held
+	w.mu.Unlock()
+}
+
+func (w *Workflow) holding(ship, symbol string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.held[ship][symbol]
+}
+
+func (w *Workflow) setPhase(ship string, phase Phase, symbol, source string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	p := w.progress.Ships[ship]
+	if p.Phase == phase && p.Symbol == symbol && p.Source == source {
+		return nil
+	}
+	p.Phase, p.Symbol, p.Source = phase, symbol, source
+	return w.save()
+}
+
+// save persists the progress; w.mu must be held.
+func (w *Workflow) save() error {
+	if w.store == nil {
+		return nil
+	}
+	w.progress.Updated = time.Now()
+	return w.store.Save(w.progress)
+}
+
+func (w *Workflow) snapshot() *Progress {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	p := *w.progress
+	p.Ships = map[string]*ShipProgress{}
+	for s, sp := range w.progress.Ships {
+		c := *sp
+		p.Ships[s] = &c
+	}
+	return &p
+}
+
+func (w *Workflow) emit(e WorkflowEvent) {
+	if w.events == nil {
+		return
+	}
+	e.Contract = w.progress.Contract
+	w.events(e)
+}
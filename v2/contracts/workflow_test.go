@@ -0,0 +1,378 @@
+package contracts_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	v2 "spacetradersgo/v2"
+	"spacetradersgo/v2/agents"
+	"spacetradersgo/v2/contracts"
+	"spacetradersgo/v2/fake"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/markets"
+	"spacetradersgo/v2/mocks"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/systems"
+)
+
+// instant lands flights and ends cooldowns as soon as they start, by moving
+// the fake server's clock, so a Workflow runs without waiting. It only
+// suits a single ship in flight, since the clock can't run for two.
+type instant struct {
+	fleets.FleetsClient
+	clock *fake.ManualClock
+}
+
+func (c instant) NavigateShip(ctx context.Context, req *fleets.NavigateShipRequest) (*fleets.NavigateShipResponse, error) {
+	resp, err := c.FleetsClient.NavigateShip(ctx, req)
+	if err == nil {
+		c.clock.Set(resp.Data.Nav.Route.Arrival)
+		resp.Data.Nav.Route.Arrival = resp.Data.Nav.Route.DepartureTime
+		resp.SetRaw(nil)
+	}
+	return resp, err
+}
+
+func (c instant) CreateSurvey(ctx context.Context, req *fleets.CreateSurveyRequest) (*fleets.CreateSurveyResponse, error) {
+	resp, err := c.FleetsClient.CreateSurvey(ctx, req)
+	if err == nil {
+		c.clock.Advance(time.Duration(resp.Data.Cooldown.TotalSeconds) * time.Second)
+	}
+	return resp, err
+}
+
+func (c instant) ExtractResource(ctx context.Context, req *fleets.ExtractResourceRequest) (*fleets.ExtractResourceResponse, error) {
+	resp, err := c.FleetsClient.ExtractResource(ctx, req)
+	if err == nil {
+		c.clock.Advance(time.Duration(resp.Data.Cooldown.TotalSeconds) * time.Second)
+	}
+	return resp, err
+}
+
+// session is an agent on a fake server that has accepted the procurement
+// contract it started with.
+type session struct {
+	srv      *fake.Server
+	clock    *fake.ManualClock
+	sdk      *v2.SpcaeTradersClient
+	fleets   instant
+	token    string
+	ship     models.Ship
+	contract models.Contract
+	good     models.ContractDeliverGood
+}
+
+func newSession(t *testing.T) *session {
+	t.Helper()
+	ctx := context.Background()
+	clock := fake.NewManualClock(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+	srv := fake.NewServer(fake.WithSeed(7), fake.WithClock(clock))
+	t.Cleanup(srv.Close)
+	sdk := v2.NewSpaceTradersClient(v2.WithHTTPClient(srv.Client()))
+	reg, err := sdk.Agents.NewAgent(ctx, &agents.NewAgentRequest{Symbol: "WORKER", Faction: "COSMIC"})
+	if err != nil {
+		t.Fatalf("NewAgent: %v", err)
+	}
+	token := reg.Data.Token
+	accept, err := sdk.Contracts.AcceptContract(ctx, &contracts.AcceptContractRequest{Token: token, ContractID: reg.Data.Contract.ID})
+	if err != nil {
+		t.Fatalf("AcceptContract: %v", err)
+	}
+	c := accept.Data.Contract
+	return &session{srv: srv, clock: clock, sdk: sdk, fleets: instant{sdk.Fleets, clock}, token: token,
+		ship: reg.Data.Ship, contract: c, good: c.Terms.Deliver[0]}
+}
+
+// sellAtHQ makes the headquarters, where the ships start and the contract
+// delivers, export goods, and returns a History that knows its prices.
+func (s *session) sellAtHQ(t *testing.T, goods ...models.TradeSymbol) *markets.History {
+	t.Helper()
+	hq := s.ship.Nav.WaypointSymbol
+	if err := s.srv.SetMarket(hq, fake.MarketProfile{Exports: goods, TradeVolume: 10}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := s.sdk.Systems.GetMarket(context.Background(), &systems.GetMarketRequest{Token: s.token, SystemID: s.ship.Nav.SystemSymbol, WaypointID: hq})
+	if err != nil {
+		t.Fatalf("GetMarket: %v", err)
+	}
+	history := markets.NewHistory()
+	history.RecordMarket(resp.Market, s.clock.Now())
+	return history
+}
+
+func (s *session) buy(t *testing.T, symbol models.TradeSymbol, units int) {
+	t.Helper()
+	for units > 0 {
+		n := units
+		if n > 10 {
+			n = 10
+		}
+		if _, err := s.sdk.Fleets.PurchaseCargo(context.Background(), &fleets.PurchaseCargoRequest{Token: s.token, ShipID: s.ship.Symbol, Symbol: string(symbol), Units: n}); err != nil {
+			t.Fatalf("PurchaseCargo: %v", err)
+		}
+		units -= n
+	}
+}
+
+func (s *session) cargo(t *testing.T, ship, symbol string) int {
+	t.Helper()
+	resp, err := s.sdk.Fleets.GetShipCargo(context.Background(), &fleets.GetShipCargoRequest{Token: s.token, ShipID: ship})
+	if err != nil {
+		t.Fatalf("GetShipCargo: %v", err)
+	}
+	for _, item := range resp.Cargo.Inventory {
+		if item.Symbol == symbol {
+			return item.Units
+		}
+	}
+	return 0
+}
+
+func (s *session) fulfilled(t *testing.T) bool {
+	t.Helper()
+	resp, err := s.sdk.Contracts.GetContract(context.Background(), &contracts.GetContractRequest{Token: s.token, ContractID: s.contract.ID})
+	if err != nil {
+		t.Fatalf("GetContract: %v", err)
+	}
+	return resp.Contract.Fulfilled
+}
+
+// events collects what a Workflow reports.
+type events struct {
+	mu   sync.Mutex
+	list []contracts.WorkflowEvent
+}
+
+func (e *events) add(ev contracts.WorkflowEvent) {
+	e.mu.Lock()
+	e.list = append(e.list, ev)
+	e.mu.Unlock()
+}
+
+// units sums the units of action by ship, with "" for the total.
+func (e *events) units(action contracts.Action) map[string]int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := map[string]int{}
+	for _, ev := range e.list {
+		if ev.Action == action {
+			out[ev.Ship] += ev.Units
+			out[""] += ev.Units
+		}
+	}
+	return out
+}
+
+func TestWorkflowSplitsGoodBetweenShips(t *testing.T) {
+	s := newSession(t)
+	ctx := context.Background()
+	good := models.TradeSymbol(s.good.TradeSymbol)
+	history := s.sellAtHQ(t, good)
+	bought, err := s.sdk.Systems.PurchaseShip(ctx, &systems.PurchaseShipRequest{Token: s.token, ShipType: models.ShipTypeShipMiningDrone, WaypointSymbol: s.ship.Nav.WaypointSymbol})
+	if err != nil {
+		t.Fatalf("PurchaseShip: %v", err)
+	}
+	drone := bought.Data.Ship.Symbol
+	if s.good.UnitsRequired <= s.ship.Cargo.Capacity {
+		t.Fatalf("contract needs %d units, too few for both ships to share", s.good.UnitsRequired)
+	}
+
+	ev := &events{}
+	wf := contracts.NewWorkflow(s.sdk.Contracts, s.sdk.Fleets, s.sdk.Systems, s.token,
+		contracts.WithPrices(history), contracts.WithWorkflowEvents(ev.add))
+	p, err := wf.Run(ctx, s.contract.ID, drone, s.ship.Symbol)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	purchased, delivered := ev.units(contracts.ActionPurchased), ev.units(contracts.ActionDelivered)
+	if purchased[""] != s.good.UnitsRequired || delivered[""] != s.good.UnitsRequired {
+		t.Errorf("bought %d and delivered %d units, want exactly the %d required", purchased[""], delivered[""], s.good.UnitsRequired)
+	}
+	if purchased[drone] == 0 || purchased[s.ship.Symbol] == 0 {
+		t.Errorf("purchases by ship = %v, want both ships to share the load", purchased)
+	}
+	for _, ship := range []string{drone, s.ship.Symbol} {
+		if p.Ships[ship].Phase != contracts.PhaseDone || p.Ships[ship].Delivered != delivered[ship] {
+			t.Errorf("progress of %s = %+v, want done having delivered %d", ship, p.Ships[ship], delivered[ship])
+		}
+		if n := s.cargo(t, ship, s.good.TradeSymbol); n != 0 {
+			t.Errorf("%s still holds %d %s", ship, n, s.good.TradeSymbol)
+		}
+	}
+	if !p.Fulfilled || !s.fulfilled(t) {
+		t.Error("contract not fulfilled")
+	}
+	// Both ships end docked at the headquarters, so the first by symbol
+	// negotiates the next contract.
+	negotiated := ""
+	for _, e := range ev.list {
+		if e.Action == contracts.ActionNegotiated {
+			negotiated = e.Ship
+		}
+	}
+	if p.Next == "" || negotiated != s.ship.Symbol {
+		t.Errorf("next contract %q negotiated by %q, want one by %s", p.Next, negotiated, s.ship.Symbol)
+	}
+}
+
+func TestWorkflowResumesFromSavedProgress(t *testing.T) {
+	s := newSession(t)
+	good := models.TradeSymbol(s.good.TradeSymbol)
+	history := s.sellAtHQ(t, good, models.TradeSymbolFabrics)
+	// Fill most of the hold with something else, so the contract takes
+	// several trips of 10 units.
+	s.buy(t, models.TradeSymbolFabrics, s.ship.Cargo.Capacity-10)
+	store := contracts.FileProgress{Dir: t.TempDir()}
+
+	// Stop the first run once it has delivered a load.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := &events{}
+	wf := contracts.NewWorkflow(s.sdk.Contracts, s.sdk.Fleets, s.sdk.Systems, s.token,
+		contracts.WithPrices(history), contracts.WithProgressStore(store), contracts.WithNegotiation(false),
+		contracts.WithWorkflowEvents(func(e contracts.WorkflowEvent) {
+			first.add(e)
+			if e.Action == contracts.ActionDelivered {
+				cancel()
+			}
+		}))
+	if _, err := wf.Run(ctx, s.contract.ID, s.ship.Symbol); !errors.Is(err, context.Canceled) {
+		t.Fatalf("first Run: err = %v, want it cancelled", err)
+	}
+	saved, err := store.Load(s.contract.ID)
+	if err != nil || saved == nil {
+		t.Fatalf("Load = %+v, %v", saved, err)
+	}
+	if got := saved.Ships[s.ship.Symbol]; got.Delivered != 10 || saved.Fulfilled {
+		t.Fatalf("saved progress %+v of %+v, want 10 delivered and not fulfilled", got, saved)
+	}
+
+	// A fresh Workflow picks the ships up from the store.
+	second := &events{}
+	wf = contracts.NewWorkflow(s.sdk.Contracts, s.sdk.Fleets, s.sdk.Systems, s.token,
+		contracts.WithPrices(history), contracts.WithProgressStore(store), contracts.WithNegotiation(false),
+		contracts.WithWorkflowEvents(second.add))
+	p, err := wf.Run(context.Background(), s.contract.ID)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	bought := first.units(contracts.ActionPurchased)[""] + second.units(contracts.ActionPurchased)[""]
+	if bought != s.good.UnitsRequired {
+		t.Errorf("bought %d units across both runs, want %d", bought, s.good.UnitsRequired)
+	}
+	if got := p.Ships[s.ship.Symbol].Delivered; got != s.good.UnitsRequired || !p.Fulfilled || !s.fulfilled(t) {
+		t.Errorf("progress %+v, ship delivered %d; want all %d delivered and fulfilled", p, got, s.good.UnitsRequired)
+	}
+	if n := s.cargo(t, s.ship.Symbol, string(models.TradeSymbolFabrics)); n != s.ship.Cargo.Capacity-10 {
+		t.Errorf("ship holds %d FABRICS, want its %d untouched", n, s.ship.Cargo.Capacity-10)
+	}
+}
+
+func TestWorkflowJettisonsUnneededYields(t *testing.T) {
+	s := newSession(t)
+	ctx := context.Background()
+	s.sellAtHQ(t, models.TradeSymbolFabrics)
+	s.buy(t, models.TradeSymbolFabrics, 5)
+	site := s.miningSite(t)
+
+	ev := &events{}
+	wf := contracts.NewWorkflow(s.sdk.Contracts, s.fleets, s.sdk.Systems, s.token,
+		contracts.WithMiningSite(s.good.TradeSymbol, site), contracts.WithNegotiation(false),
+		contracts.WithWorkflowEvents(ev.add))
+	p, err := wf.Run(ctx, s.contract.ID, s.ship.Symbol)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !p.Fulfilled || !s.fulfilled(t) {
+		t.Fatal("contract not fulfilled")
+	}
+
+	var extracted *contracts.WorkflowEvent
+	jettisoned := 0
+	for i, e := range ev.list {
+		switch e.Action {
+		case contracts.ActionExtracted:
+			extracted = &ev.list[i]
+		case contracts.ActionJettisoned:
+			jettisoned++
+			if extracted == nil || e.Symbol != extracted.Symbol || e.Units != extracted.Units {
+				t.Errorf("jettisoned %d %s, not the yield %+v just extracted", e.Units, e.Symbol, extracted)
+			}
+			if e.Symbol == s.good.TradeSymbol {
+				t.Errorf("jettisoned %d %s, which the contract needs", e.Units, e.Symbol)
+			}
+		}
+	}
+	if jettisoned == 0 {
+		t.Error("no yield was jettisoned; the site only had what the contract wanted")
+	}
+	if n := s.cargo(t, s.ship.Symbol, string(models.TradeSymbolFabrics)); n != 5 {
+		t.Errorf("ship holds %d FABRICS, want the 5 it carried before mining", n)
+	}
+}
+
+// miningSite flies the ship to the asteroid fields of its system and
+// surveys each, returning one with deposits of the contract's good and
+// something else.
+func (s *session) miningSite(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+	wps, err := s.sdk.Systems.ListWaypoints(ctx, &systems.ListWaypointsRequest{Token: s.token, SystemID: s.ship.Nav.SystemSymbol, NumPerPage: 20})
+	if err != nil {
+		t.Fatalf("ListWaypoints: %v", err)
+	}
+	for _, wp := range wps.Waypoints {
+		if wp.Type != models.WaypointTypeAsteroidField {
+			continue
+		}
+		if _, err := s.fleets.OrbitShip(ctx, &fleets.OrbitShipRequest{Token: s.token, ShipID: s.ship.Symbol}); err != nil {
+			t.Fatalf("OrbitShip: %v", err)
+		}
+		if _, err := s.fleets.NavigateShip(ctx, &fleets.NavigateShipRequest{Token: s.token, ShipID: s.ship.Symbol, WaypointSymbol: wp.Symbol}); err != nil {
+			t.Fatalf("NavigateShip: %v", err)
+		}
+		survey, err := s.fleets.CreateSurvey(ctx, &fleets.CreateSurveyRequest{Token: s.token, ShipID: s.ship.Symbol})
+		if err != nil {
+			t.Fatalf("CreateSurvey: %v", err)
+		}
+		deposits := map[string]bool{}
+		for _, sv := range survey.Data.Surveys {
+			for _, d := range sv.Deposits {
+				deposits[d.Symbol] = true
+			}
+		}
+		if deposits[s.good.TradeSymbol] && len(deposits) > 1 {
+			return wp.Symbol
+		}
+	}
+	t.Fatalf("no asteroid field has deposits of %s", s.good.TradeSymbol)
+	return ""
+}
+
+func TestWorkflowToleratesContractAlreadyFulfilled(t *testing.T) {
+	ctx := context.Background()
+	c := mocks.NewClients()
+	c.Contracts.OnGetContract().Return(&contracts.GetContractResponse{Contract: models.Contract{
+		ID:       "C1",
+		Accepted: true,
+		Terms: models.ContractTerms{Deliver: []models.ContractDeliverGood{
+			{TradeSymbol: "IRON_ORE", DestinationSymbol: "X1-A-B", UnitsRequired: 10, UnitsFulfilled: 10},
+		}},
+	}}, nil)
+	// Another process fulfilled it between the check and the call.
+	c.Contracts.OnFulfillContract().Fail(&models.APIError{StatusCode: 400, Code: models.ErrCodeContractFulfilled})
+	c.Fleets.OnGetShip().Return(&fleets.GetShipResponse{Ship: models.Ship{Symbol: "S-1", Cargo: models.ShipCargo{Capacity: 30}}}, nil)
+
+	wf := contracts.NewWorkflow(c.Contracts, c.Fleets, c.Systems, "token", contracts.WithNegotiation(false))
+	p, err := wf.Run(ctx, "C1", "S-1")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !p.Fulfilled || p.Ships["S-1"].Phase != contracts.PhaseDone {
+		t.Errorf("progress = %+v, ship %+v; want fulfilled and done", p, p.Ships["S-1"])
+	}
+}
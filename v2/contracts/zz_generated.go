@@ -0,0 +1,190 @@
+// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.
+
+package contracts
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/models"
+	"strconv"
+)
+
+type ContractsClient interface {
+	// List all of your contracts.
+	ListContracts(ctx context.Context, req *ListContractsRequest) (*ListContractsResponse, error)
+	// Get the details of a contract by ID.
+	GetContract(ctx context.Context, req *GetContractRequest) (*GetContractResponse, error)
+	// Accept a contract.
+	AcceptContract(ctx context.Context, req *AcceptContractRequest) (*AcceptContractResponse, error)
+	// Deliver cargo on a given contract.
+	DeliverContract(ctx context.Context, req *DeliverContractRequest) (*DeliverContractResponse, error)
+	// Fulfill a contract.
+	FulfillContract(ctx context.Context, req *FulfillContractRequest) (*FulfillContractResponse, error)
+}
+
+type ListContractsRequest struct {
+	Token string
+	// What entry offset to request
+	// >= 1
+	Page int
+	// How many entries to return per page
+	// >= 1 <= 20
+	NumPerPage int
+}
+type ListContractsResponse struct {
+	models.RawResponse
+
+	Contracts []models.Contract `json:"data"`
+	Meta      models.Meta       `json:"meta"`
+}
+
+type GetContractRequest struct {
+	Token string
+	// The contract ID
+	ContractID string
+}
+type GetContractResponse struct {
+	models.RawResponse
+
+	Contract models.Contract `json:"data"`
+}
+
+type AcceptContractRequest struct {
+	Token string
+	// The contract ID
+	ContractID string
+}
+type AcceptContractResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Agent    models.Agent    `json:"agent"`
+		Contract models.Contract `json:"contract"`
+	} `json:"data"`
+}
+
+type DeliverContractRequest struct {
+	Token string `json:"-"`
+	// The contract ID
+	ContractID string `json:"-"`
+	// ShipSymbol Symbol of a ship located in the destination to deliver a contract and that has a good to deliver in its cargo.
+	ShipSymbol string `json:"shipSymbol"`
+	// TradeSymbol The symbol of the good to deliver.
+	TradeSymbol string `json:"tradeSymbol"`
+	// Units Amount of units to deliver.
+	Units int `json:"units"`
+}
+type DeliverContractResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Contract models.Contract  `json:"contract"`
+		Cargo    models.ShipCargo `json:"cargo"`
+	} `json:"data"`
+}
+
+type FulfillContractRequest struct {
+	Token string
+	// The contract ID
+	ContractID string
+}
+type FulfillContractResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Agent    models.Agent    `json:"agent"`
+		Contract models.Contract `json:"contract"`
+	} `json:"data"`
+}
+
+// List all of your contracts.
+func (c *contractsClient) ListContracts(ctx context.Context, req *ListContractsRequest) (*ListContractsResponse, error) {
+	query := url.Values{}
+	if req.Page != 0 {
+		query.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.NumPerPage != 0 {
+		query.Set("limit", strconv.Itoa(req.NumPerPage))
+	}
+
+	resp := &ListContractsResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "ListContracts",
+		Method:    http.MethodGet,
+		Path:      "/my/contracts",
+		Query:     query,
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get the details of a contract by ID.
+func (c *contractsClient) GetContract(ctx context.Context, req *GetContractRequest) (*GetContractResponse, error) {
+	resp := &GetContractResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetContract",
+		Method:    http.MethodGet,
+		Path:      "/my/contracts/" + url.PathEscape(req.ContractID),
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Accept a contract.
+func (c *contractsClient) AcceptContract(ctx context.Context, req *AcceptContractRequest) (*AcceptContractResponse, error) {
+	resp := &AcceptContractResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "AcceptContract",
+		Method:    http.MethodPost,
+		Path:      "/my/contracts/" + url.PathEscape(req.ContractID) + "/accept",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Deliver cargo on a given contract.
+func (c *contractsClient) DeliverContract(ctx context.Context, req *DeliverContractRequest) (*DeliverContractResponse, error) {
+	resp := &DeliverContractResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "DeliverContract",
+		Method:    http.MethodPost,
+		Path:      "/my/contracts/" + url.PathEscape(req.ContractID) + "/deliver",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Fulfill a contract.
+func (c *contractsClient) FulfillContract(ctx context.Context, req *FulfillContractRequest) (*FulfillContractResponse, error) {
+	resp := &FulfillContractResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "FulfillContract",
+		Method:    http.MethodPost,
+		Path:      "/my/contracts/" + url.PathEscape(req.ContractID) + "/fulfill",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -2,14 +2,14 @@ package factions
 
 import (
 	"context"
-	"encoding/json"
-	"io"
 	"net/http"
-	"strconv"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/strict"
 )
 
 type factionsClient struct {
 	httpClient *http.Client
+	strict     *strict.Recorder
 }
 
 type factionsClientOpts func(*factionsClient)
@@ -26,6 +26,14 @@ func WithHTTPClient(httpClient *http.Client) factionsClientOpts {
 	}
 }
 
+// WithStrictDecoding checks every response against the SDK types and
+// records unknown or missing fields in recorder.
+func WithStrictDecoding(recorder *strict.Recorder) factionsClientOpts {
+	return func(c *factionsClient) {
+		c.strict = recorder
+	}
+}
+
 func NewFactions(opts ...factionsClientOpts) *factionsClient {
 	c := &factionsClient{}
 
@@ -37,68 +45,11 @@ func NewFactions(opts ...factionsClientOpts) *factionsClient {
 	return c
 }
 
-// View the details of a faction by symbol.
-func (c *factionsClient) GetFaction(ctx context.Context, req *GetFactionRequest) (*GetFactionResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/factions/"+req.FactionSymbol, nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetFactionResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (c *factionsClient) rest() *rest.Client {
+	return &rest.Client{HTTPClient: c.httpClient, Strict: c.strict}
 }
 
-// View all factions.
-func (c *factionsClient) ListFactions(ctx context.Context, req *ListFactionsRequest) (*ListFactionsResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/factions", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-	values := httpReq.URL.Query()
-	if req.NumPerPage != 0 {
-		values.Set("limit", strconv.Itoa(req.NumPerPage))
-	}
-	if req.Page != 0 {
-		values.Set("page", strconv.Itoa(req.Page))
-	}
-	httpReq.URL.RawQuery = values.Encode()
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &ListFactionsResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (c *factionsClient) do(ctx context.Context, req *rest.Request, out any) error {
+	_, err := c.rest().Do(ctx, req, out)
+	return err
 }
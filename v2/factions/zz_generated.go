@@ -0,0 +1,87 @@
+// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.
+
+package factions
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/models"
+	"strconv"
+)
+
+type FactionsClient interface {
+	// List all discovered factions in the game.
+	ListFactions(ctx context.Context, req *ListFactionsRequest) (*ListFactionsResponse, error)
+	// View the details of a faction.
+	GetFaction(ctx context.Context, req *GetFactionRequest) (*GetFactionResponse, error)
+}
+
+type ListFactionsRequest struct {
+	Token string
+	// What entry offset to request
+	// >= 1
+	Page int
+	// How many entries to return per page
+	// >= 1 <= 20
+	NumPerPage int
+}
+type ListFactionsResponse struct {
+	models.RawResponse
+
+	Factions []models.Faction `json:"data"`
+	Meta     models.Meta      `json:"meta"`
+}
+
+type GetFactionRequest struct {
+	Token string
+	// The faction symbol
+	FactionSymbol string
+}
+type GetFactionResponse struct {
+	models.RawResponse
+
+	Faction models.Faction `json:"data"`
+}
+
+// List all discovered factions in the game.
+func (c *factionsClient) ListFactions(ctx context.Context, req *ListFactionsRequest) (*ListFactionsResponse, error) {
+	query := url.Values{}
+	if req.Page != 0 {
+		query.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.NumPerPage != 0 {
+		query.Set("limit", strconv.Itoa(req.NumPerPage))
+	}
+
+	resp := &ListFactionsResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "ListFactions",
+		Method:    http.MethodGet,
+		Path:      "/factions",
+		Query:     query,
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// View the details of a faction.
+func (c *factionsClient) GetFaction(ctx context.Context, req *GetFactionRequest) (*GetFactionResponse, error) {
+	resp := &GetFactionResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetFaction",
+		Method:    http.MethodGet,
+		Path:      "/factions/" + url.PathEscape(req.FactionSymbol),
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
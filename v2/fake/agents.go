@@ -0,0 +1,228 @@
+package fake
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"spacetradersgo/v2/models"
+	"time"
+)
+
+const startingCredits = 100000
+
+type agent struct {
+	models.Agent
+	token     string
+	ships     map[string]*ship
+	contracts []*models.Contract
+}
+
+type ship struct {
+	models.Ship
+	cooldownTotal int
+	cooldownUntil time.Time
+}
+
+func (a *agent) sortedShips() []*ship {
+	out := make([]*ship, 0, len(a.ships))
+	for _, k := range sortedKeys(a.ships) {
+		out = append(out, a.ships[k])
+	}
+	return out
+}
+
+// present reports whether any of the agent's ships is at wp and not in
+// transit, which is what unlocks market prices and shipyard listings.
+func (s *Server) present(a *agent, wp string) bool {
+	for _, sh := range a.ships {
+		if sh.Nav.WaypointSymbol == wp && sh.Nav.Status != models.ShipNavStatusInTransit {
+			return true
+		}
+	}
+	return false
+}
+
+var agentSymbol = regexp.MustCompile(`^[A-Za-z0-9_-]{3,14}$`)
+
+// Register creates an agent directly, without going through the API, and
+// returns its token. It fails the same way the register endpoint does.
+func (s *Server) Register(symbol, faction string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, err := s.newAgent(symbol, faction)
+	if err != nil {
+		return "", err
+	}
+	return a.token, nil
+}
+
+func (s *Server) newAgent(symbol, faction string) (*agent, *apiError) {
+	if !agentSymbol.MatchString(symbol) {
+		return nil, errorf(http.StatusUnprocessableEntity, models.ErrCodeValidation, "Agent symbol must be 3 to 14 letters, digits, dashes or underscores.")
+	}
+	f, ok := s.u.faction(faction)
+	if !ok {
+		return nil, errorf(http.StatusUnprocessableEntity, models.ErrCodeValidation, "Faction %s does not exist.", faction)
+	}
+	if _, ok := s.bySymbol[symbol]; ok {
+		return nil, conflict(models.ErrCodeRegisterAgentExists, "Agent symbol %s has already been claimed.", symbol)
+	}
+
+	a := &agent{
+		Agent: models.Agent{
+			AccountID:       fmt.Sprintf("acct-%08x", s.rng.Uint32()),
+			Symbol:          symbol,
+			Headquarters:    f.Headquarters,
+			Credits:         startingCredits,
+			StartingFaction: f.Symbol,
+		},
+		token: fmt.Sprintf("fake-%s-%016x", symbol, s.rng.Uint64()),
+		ships: map[string]*ship{},
+	}
+	s.agents[a.token] = a
+	s.bySymbol[symbol] = a
+
+	hq := s.u.byWP[f.Headquarters]
+	s.addShip(a, models.ShipTypeShipCommandFrigate, hq)
+	s.addContract(a, f.Symbol)
+	return a, nil
+}
+
+func (s *Server) addShip(a *agent, t models.ShipType, wp *waypoint) *ship {
+	symbol := fmt.Sprintf("%s-%X", a.Symbol, len(a.ships)+1)
+	sh := &ship{Ship: newShip(symbol, a.StartingFaction, t, wp)}
+	a.ships[symbol] = sh
+	return sh
+}
+
+// addContract offers a procurement contract for an ore the faction's home
+// system mines, delivered to its headquarters.
+func (s *Server) addContract(a *agent, faction string) *models.Contract {
+	f, _ := s.u.faction(faction)
+	home := s.u.byWP[f.Headquarters]
+	var deposits []models.TradeSymbol
+	for _, wp := range s.u.bySystem[home.SystemSymbol].waypoints {
+		deposits = append(deposits, wp.deposits...)
+	}
+	good := deposits[s.rng.Intn(len(deposits))]
+	units := 20 + 10*s.rng.Intn(8)
+
+	now := s.now()
+	c := &models.Contract{
+		ID:            fmt.Sprintf("c%024x", s.rng.Uint64()),
+		FactionSymbol: faction,
+		Type:          models.ContractTypeProcurement,
+		Terms: models.ContractTerms{
+			Deadline: now.Add(7 * 24 * time.Hour),
+			Payment: models.ContractPayment{
+				OnAccepted:  basePrices[good] * units / 4,
+				OnFulfilled: basePrices[good] * units,
+			},
+			Deliver: []models.ContractDeliverGood{{
+				TradeSymbol:       string(good),
+				DestinationSymbol: f.Headquarters,
+				UnitsRequired:     units,
+			}},
+		},
+		Expiration:       now.Add(24 * time.Hour),
+		DeadlineToAccept: now.Add(24 * time.Hour),
+	}
+	a.contracts = append(a.contracts, c)
+	return c
+}
+
+func (s *Server) register(c *call) (int, any, error) {
+	var body struct {
+		Symbol  string `json:"symbol"`
+		Faction string `json:"faction"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	a, err := s.newAgent(body.Symbol, body.Faction)
+	if err != nil {
+		return 0, nil, err
+	}
+	f, _ := s.u.faction(a.StartingFaction)
+	return http.StatusCreated, data(map[string]any{
+		"agent":    a.Agent,
+		"contract": a.contracts[0],
+		"faction":  f,
+		"ship":     a.sortedShips()[0].Ship,
+		"token":    a.token,
+	}), nil
+}
+
+func (s *Server) getAgent(c *call) (int, any, error) {
+	return http.StatusOK, data(c.agent.Agent), nil
+}
+
+func (s *Server) listFactions(c *call) (int, any, error) {
+	page, limit, err := c.page()
+	if err != nil {
+		return 0, nil, err
+	}
+	lo, hi, meta := paginate(len(s.u.factions), page, limit)
+	return http.StatusOK, paged{Data: s.u.factions[lo:hi], Meta: meta}, nil
+}
+
+func (s *Server) getFaction(c *call) (int, any, error) {
+	f, ok := s.u.faction(c.params["faction"])
+	if !ok {
+		return 0, nil, notFound("Faction %s not found.", c.params["faction"])
+	}
+	return http.StatusOK, data(f), nil
+}
+
+func (s *Server) getStatus(c *call) (int, any, error) {
+	ships, waypoints := 0, 0
+	for _, a := range s.agents {
+		ships += len(a.ships)
+	}
+	for _, sys := range s.u.systems {
+		waypoints += len(sys.waypoints)
+	}
+	return http.StatusOK, map[string]any{
+		"status":      "SpaceTraders is currently online and available to play",
+		"version":     "v2",
+		"resetDate":   s.started.Format("2006-01-02"),
+		"description": "An in-process fake of the SpaceTraders API.",
+		"stats": map[string]int{
+			"agents":    len(s.agents),
+			"ships":     ships,
+			"systems":   len(s.u.systems),
+			"waypoints": waypoints,
+		},
+		"serverResets": map[string]string{
+			"next":      s.started.Add(14 * 24 * time.Hour).Format(time.RFC3339),
+			"frequency": "never",
+		},
+		"announcements": []any{},
+		"links":         []any{},
+	}, nil
+}
+
+func (s *Server) agentContract(c *call) (*models.Contract, *apiError) {
+	for _, ct := range c.agent.contracts {
+		if ct.ID == c.params["contract"] {
+			return ct, nil
+		}
+	}
+	return nil, notFound("Contract %s not found.", c.params["contract"])
+}
+
+func (s *Server) agentShip(c *call) (*ship, *apiError) {
+	sh, ok := c.agent.ships[c.params["ship"]]
+	if !ok {
+		return nil, notFound("Ship %s not found.", c.params["ship"])
+	}
+	return sh, nil
+}
+
+func contractList(cs []*models.Contract) []models.Contract {
+	out := make([]models.Contract, 0, len(cs))
+	for _, c := range cs {
+		out = append(out, *c)
+	}
+	return out
+}
@@ -0,0 +1,249 @@
+package fake
+
+import (
+	"spacetradersgo/v2/models"
+	"strings"
+)
+
+// basePrices is what each good is worth before a market's role and supply
+// move the price. Only goods listed here are traded in the fake universe.
+var basePrices = map[models.TradeSymbol]int{
+	models.TradeSymbolFuel:            72,
+	models.TradeSymbolIronOre:         40,
+	models.TradeSymbolCopperOre:       46,
+	models.TradeSymbolAluminumOre:     50,
+	models.TradeSymbolSilverOre:       72,
+	models.TradeSymbolGoldOre:         94,
+	models.TradeSymbolPlatinumOre:     118,
+	models.TradeSymbolQuartzSand:      20,
+	models.TradeSymbolSiliconCrystals: 34,
+	models.TradeSymbolIceWater:        14,
+	models.TradeSymbolAmmoniaIce:      26,
+	models.TradeSymbolPreciousStones:  64,
+	models.TradeSymbolDiamonds:        310,
+	models.TradeSymbolLiquidHydrogen:  30,
+	models.TradeSymbolLiquidNitrogen:  32,
+	models.TradeSymbolHydrocarbon:     42,
+	models.TradeSymbolIron:            96,
+	models.TradeSymbolCopper:          104,
+	models.TradeSymbolAluminum:        112,
+	models.TradeSymbolPlastics:        84,
+	models.TradeSymbolFertilizers:     52,
+	models.TradeSymbolFood:            44,
+	models.TradeSymbolFabrics:         62,
+	models.TradeSymbolClothing:        96,
+	models.TradeSymbolMachinery:       160,
+	models.TradeSymbolElectronics:     210,
+	models.TradeSymbolEquipment:       260,
+	models.TradeSymbolMedicine:        190,
+	models.TradeSymbolMicroprocessors: 420,
+}
+
+var (
+	// ores are what asteroid fields yield.
+	ores = []models.TradeSymbol{
+		models.TradeSymbolIronOre, models.TradeSymbolCopperOre, models.TradeSymbolAluminumOre,
+		models.TradeSymbolSilverOre, models.TradeSymbolGoldOre, models.TradeSymbolPlatinumOre,
+		models.TradeSymbolQuartzSand, models.TradeSymbolSiliconCrystals, models.TradeSymbolIceWater,
+		models.TradeSymbolAmmoniaIce, models.TradeSymbolPreciousStones, models.TradeSymbolDiamonds,
+	}
+	// gases are what gas giants export.
+	gases = []models.TradeSymbol{
+		models.TradeSymbolLiquidHydrogen, models.TradeSymbolLiquidNitrogen, models.TradeSymbolHydrocarbon,
+	}
+	// refined goods are made from ores and gases.
+	refined = []models.TradeSymbol{
+		models.TradeSymbolIron, models.TradeSymbolCopper, models.TradeSymbolAluminum,
+		models.TradeSymbolPlastics, models.TradeSymbolFertilizers,
+	}
+	// manufactured goods are made from refined goods.
+	manufactured = []models.TradeSymbol{
+		models.TradeSymbolFood, models.TradeSymbolFabrics, models.TradeSymbolClothing,
+		models.TradeSymbolMachinery, models.TradeSymbolElectronics, models.TradeSymbolEquipment,
+		models.TradeSymbolMedicine, models.TradeSymbolMicroprocessors,
+	}
+)
+
+// title turns IRON_ORE into "Iron Ore".
+func title(symbol string) string {
+	words := strings.Split(strings.ToLower(symbol), "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func tradeGood(symbol models.TradeSymbol) models.TradeGood {
+	name := title(string(symbol))
+	return models.TradeGood{Symbol: symbol, Name: name, Description: "A standard unit of " + strings.ToLower(name) + "."}
+}
+
+func cargoItem(symbol string, units int) models.ShipCargoItem {
+	g := tradeGood(models.TradeSymbol(symbol))
+	return models.ShipCargoItem{Symbol: symbol, Name: g.Name, Description: g.Description, Units: units}
+}
+
+func trait(symbol string) models.WaypointTrait {
+	name := title(symbol)
+	return models.WaypointTrait{Symbol: symbol, Name: name, Description: "This waypoint has the " + strings.ToLower(name) + " trait."}
+}
+
+// shipTemplate describes a purchasable ship type.
+type shipTemplate struct {
+	role    models.ShipRole
+	price   int
+	crew    int
+	frame   models.ShipFrame
+	reactor models.ShipReactor
+	engine  models.ShipEngine
+	modules []models.ShipModule
+	mounts  []models.ShipMount
+}
+
+func frame(symbol string, fuel, slots, mounts int) models.ShipFrame {
+	return models.ShipFrame{Symbol: symbol, Name: title(strings.TrimPrefix(symbol, "FRAME_")), Description: "A ship frame.",
+		Condition: 100, ModuleSlots: slots, MountingPoints: mounts, FuelCapacity: fuel}
+}
+
+func reactor(symbol string, power int) models.ShipReactor {
+	return models.ShipReactor{Symbol: symbol, Name: title(strings.TrimPrefix(symbol, "REACTOR_")), Description: "A ship reactor.",
+		Condition: 100, PowerOutput: power}
+}
+
+func engine(symbol string, speed int) models.ShipEngine {
+	return models.ShipEngine{Symbol: symbol, Name: title(strings.TrimPrefix(symbol, "ENGINE_")), Description: "A ship engine.",
+		Condition: 100, Speed: speed}
+}
+
+func module(symbol string, capacity, rng int) models.ShipModule {
+	return models.ShipModule{Symbol: symbol, Name: title(strings.TrimPrefix(symbol, "MODULE_")), Description: "A ship module.",
+		Capacity: capacity, Range: rng, Requirements: models.ShipRequirements{Slots: 1}}
+}
+
+func mount(symbol string, strength int, deposits ...models.TradeSymbol) models.ShipMount {
+	m := models.ShipMount{Symbol: symbol, Name: title(strings.TrimPrefix(symbol, "MOUNT_")), Description: "A ship mount.",
+		Strength: strength}
+	for _, d := range deposits {
+		m.Deposits = append(m.Deposits, string(d))
+	}
+	return m
+}
+
+const (
+	moduleCargoHold  = "MODULE_CARGO_HOLD_I"
+	moduleJumpDrive  = "MODULE_JUMP_DRIVE_I"
+	moduleWarpDrive  = "MODULE_WARP_DRIVE_I"
+	mountMiningLaser = "MOUNT_MINING_LASER_"
+	mountSurveyor    = "MOUNT_SURVEYOR_"
+	mountSensorArray = "MOUNT_SENSOR_ARRAY_"
+)
+
+var shipTemplates = map[models.ShipType]shipTemplate{
+	models.ShipTypeShipCommandFrigate: {
+		role: models.ShipRoleCommand, price: 450000, crew: 25,
+		frame:   frame("FRAME_FRIGATE", 1200, 8, 5),
+		reactor: reactor("REACTOR_FISSION_I", 31),
+		engine:  engine("ENGINE_ION_DRIVE_II", 30),
+		modules: []models.ShipModule{
+			module(moduleCargoHold, 30, 0), module(moduleCargoHold, 30, 0),
+			module("MODULE_CREW_QUARTERS_I", 40, 0), module("MODULE_MINERAL_PROCESSOR_I", 0, 0),
+			module(moduleJumpDrive, 0, 500),
+		},
+		mounts: []models.ShipMount{
+			mount(mountSensorArray+"I", 1), mount(mountMiningLaser+"I", 10),
+			mount(mountSurveyor+"I", 1, ores...),
+		},
+	},
+	models.ShipTypeShipProbe: {
+		role: models.ShipRoleSatellite, price: 20000, crew: 0,
+		frame:   frame("FRAME_PROBE", 0, 0, 0),
+		reactor: reactor("REACTOR_SOLAR_I", 3),
+		engine:  engine("ENGINE_IMPULSE_DRIVE_I", 3),
+	},
+	models.ShipTypeShipMiningDrone: {
+		role: models.ShipRoleExcavator, price: 60000, crew: 0,
+		frame:   frame("FRAME_DRONE", 100, 3, 2),
+		reactor: reactor("REACTOR_CHEMICAL_I", 15),
+		engine:  engine("ENGINE_IMPULSE_DRIVE_I", 10),
+		modules: []models.ShipModule{module(moduleCargoHold, 30, 0)},
+		mounts:  []models.ShipMount{mount(mountMiningLaser+"I", 10)},
+	},
+	models.ShipTypeShipOreHound: {
+		role: models.ShipRoleExcavator, price: 150000, crew: 8,
+		frame:   frame("FRAME_MINER", 400, 4, 3),
+		reactor: reactor("REACTOR_FISSION_I", 31),
+		engine:  engine("ENGINE_ION_DRIVE_I", 20),
+		modules: []models.ShipModule{module(moduleCargoHold, 30, 0), module(moduleCargoHold, 30, 0)},
+		mounts:  []models.ShipMount{mount(mountMiningLaser+"II", 25), mount(mountSurveyor+"I", 1, ores...)},
+	},
+	models.ShipTypeShipLightHauler: {
+		role: models.ShipRoleHauler, price: 280000, crew: 12,
+		frame:   frame("FRAME_LIGHT_FREIGHTER", 1700, 6, 1),
+		reactor: reactor("REACTOR_FISSION_I", 31),
+		engine:  engine("ENGINE_ION_DRIVE_I", 30),
+		modules: []models.ShipModule{
+			module(moduleCargoHold, 30, 0), module(moduleCargoHold, 30, 0),
+			module(moduleCargoHold, 30, 0), module(moduleCargoHold, 30, 0),
+		},
+		mounts: []models.ShipMount{mount(mountSensorArray+"I", 1)},
+	},
+	models.ShipTypeShipExplorer: {
+		role: models.ShipRoleExplorer, price: 320000, crew: 10,
+		frame:   frame("FRAME_EXPLORER", 2400, 5, 2),
+		reactor: reactor("REACTOR_FUSION_I", 40),
+		engine:  engine("ENGINE_ION_DRIVE_II", 40),
+		modules: []models.ShipModule{
+			module(moduleCargoHold, 30, 0), module(moduleJumpDrive, 0, 500), module(moduleWarpDrive, 0, 2000),
+		},
+		mounts: []models.ShipMount{mount(mountSensorArray+"II", 4), mount(mountSurveyor+"II", 2, ores...)},
+	},
+}
+
+// newShip builds a ship of template t, docked at wp.
+func newShip(symbol, faction string, shipType models.ShipType, wp *waypoint) models.Ship {
+	t := shipTemplates[shipType]
+	capacity := 0
+	for _, m := range t.modules {
+		if m.Symbol == moduleCargoHold {
+			capacity += m.Capacity
+		}
+	}
+	loc := wp.routeWaypoint()
+	return models.Ship{
+		Symbol:       symbol,
+		Registration: models.ShipRegistration{Name: symbol, FactionSymbol: faction, Role: t.role},
+		Nav: models.ShipNav{
+			SystemSymbol:   wp.SystemSymbol,
+			WaypointSymbol: wp.Symbol,
+			Route:          models.ShipNavRoute{Departure: loc, Destination: loc},
+			Status:         models.ShipNavStatusDocked,
+			FlightMode:     models.ShipNavFlightModeCruise,
+		},
+		Crew: models.ShipCrew{Current: t.crew, Required: t.crew, Capacity: t.crew,
+			Rotation: models.ShipCrewRotationStrict, Morale: 100},
+		Frame:   t.frame,
+		Reactor: t.reactor,
+		Engine:  t.engine,
+		Modules: append([]models.ShipModule{}, t.modules...),
+		Mounts:  append([]models.ShipMount{}, t.mounts...),
+		Cargo:   models.ShipCargo{Capacity: capacity, Inventory: []models.ShipCargoItem{}},
+		Fuel:    models.ShipFuel{Current: t.frame.FuelCapacity, Capacity: t.frame.FuelCapacity},
+	}
+}
+
+func shipyardShip(shipType models.ShipType) models.ShipyardShip {
+	t := shipTemplates[shipType]
+	return models.ShipyardShip{
+		Type:          shipType,
+		Name:          title(strings.TrimPrefix(string(shipType), "SHIP_")),
+		Description:   "A " + strings.ToLower(title(strings.TrimPrefix(string(shipType), "SHIP_"))) + ".",
+		PurchasePrice: t.price,
+		Frame:         t.frame,
+		Reactor:       t.reactor,
+		Engine:        t.engine,
+		Modules:       append([]models.ShipModule{}, t.modules...),
+		Mounts:        append([]models.ShipMount{}, t.mounts...),
+	}
+}
@@ -0,0 +1,57 @@
+package fake
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the server's source of time. Travel, cooldowns, survey expiry
+// and contract deadlines are all measured against it, so a test that
+// installs a ManualClock with WithClock can skip ahead instead of waiting.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// ManualClock only moves when told to. It is safe for concurrent use.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock returns a clock stopped at start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// Set moves the clock to t, which may be in the past.
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// WithClock replaces the wall clock the server uses by default.
+func WithClock(clock Clock) serverOpts {
+	return func(s *Server) {
+		s.clock = clock
+	}
+}
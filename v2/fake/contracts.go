@@ -0,0 +1,136 @@
+package fake
+
+import (
+	"net/http"
+	"spacetradersgo/v2/models"
+)
+
+func (s *Server) listContracts(c *call) (int, any, error) {
+	page, limit, err := c.page()
+	if err != nil {
+		return 0, nil, err
+	}
+	all := contractList(c.agent.contracts)
+	lo, hi, meta := paginate(len(all), page, limit)
+	return http.StatusOK, paged{Data: all[lo:hi], Meta: meta}, nil
+}
+
+func (s *Server) getContract(c *call) (int, any, error) {
+	ct, err := s.agentContract(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(ct), nil
+}
+
+func (s *Server) acceptContract(c *call) (int, any, error) {
+	ct, err := s.agentContract(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if ct.Accepted {
+		return 0, nil, badRequest(models.ErrCodeAcceptContractConflict, "Contract %s has already been accepted.", ct.ID)
+	}
+	if s.now().After(ct.DeadlineToAccept) {
+		return 0, nil, badRequest(models.ErrCodeContractDeadline, "Contract %s can no longer be accepted.", ct.ID)
+	}
+	ct.Accepted = true
+	c.agent.Credits += int64(ct.Terms.Payment.OnAccepted)
+	return http.StatusOK, data(map[string]any{"agent": c.agent.Agent, "contract": ct}), nil
+}
+
+func (s *Server) deliverContract(c *call) (int, any, error) {
+	var body struct {
+		ShipSymbol  string `json:"shipSymbol"`
+		TradeSymbol string `json:"tradeSymbol"`
+		Units       int    `json:"units"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	ct, err := s.agentContract(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	switch {
+	case !ct.Accepted:
+		return 0, nil, badRequest(models.ErrCodeContractNotAccepted, "Contract %s has not been accepted.", ct.ID)
+	case ct.Fulfilled:
+		return 0, nil, badRequest(models.ErrCodeContractFulfilled, "Contract %s has already been fulfilled.", ct.ID)
+	case s.now().After(ct.Terms.Deadline):
+		return 0, nil, badRequest(models.ErrCodeContractDeadline, "Contract %s has expired.", ct.ID)
+	}
+
+	sh, ok := c.agent.ships[body.ShipSymbol]
+	if !ok {
+		return 0, nil, notFound("Ship %s not found.", body.ShipSymbol)
+	}
+	if err := s.requireDocked(sh); err != nil {
+		return 0, nil, err
+	}
+
+	var term *models.ContractDeliverGood
+	for i := range ct.Terms.Deliver {
+		if ct.Terms.Deliver[i].TradeSymbol == body.TradeSymbol {
+			term = &ct.Terms.Deliver[i]
+		}
+	}
+	if term == nil {
+		return 0, nil, badRequest(models.ErrCodeDeliverTerms, "Contract %s does not require %s.", ct.ID, body.TradeSymbol)
+	}
+	if term.DestinationSymbol != sh.Nav.WaypointSymbol {
+		return 0, nil, badRequest(models.ErrCodeDeliverInvalidLocation, "%s must be delivered to %s.", body.TradeSymbol, term.DestinationSymbol)
+	}
+	if term.UnitsFulfilled+body.Units > term.UnitsRequired {
+		return 0, nil, badRequest(models.ErrCodeDeliverFulfilled, "Contract %s only needs %d more units of %s.",
+			ct.ID, term.UnitsRequired-term.UnitsFulfilled, body.TradeSymbol)
+	}
+	if err := removeCargo(sh, body.TradeSymbol, body.Units); err != nil {
+		return 0, nil, err
+	}
+	term.UnitsFulfilled += body.Units
+	return http.StatusOK, data(map[string]any{"contract": ct, "cargo": sh.Cargo}), nil
+}
+
+func (s *Server) fulfillContract(c *call) (int, any, error) {
+	ct, err := s.agentContract(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	switch {
+	case !ct.Accepted:
+		return 0, nil, badRequest(models.ErrCodeContractNotAccepted, "Contract %s has not been accepted.", ct.ID)
+	case ct.Fulfilled:
+		return 0, nil, badRequest(models.ErrCodeContractFulfilled, "Contract %s has already been fulfilled.", ct.ID)
+	}
+	for _, term := range ct.Terms.Deliver {
+		if term.UnitsFulfilled < term.UnitsRequired {
+			return 0, nil, badRequest(models.ErrCodeFulfillContractDelivery, "Contract %s still needs %d units of %s.",
+				ct.ID, term.UnitsRequired-term.UnitsFulfilled, term.TradeSymbol)
+		}
+	}
+	ct.Fulfilled = true
+	c.agent.Credits += int64(ct.Terms.Payment.OnFulfilled)
+	return http.StatusOK, data(map[string]any{"agent": c.agent.Agent, "contract": ct}), nil
+}
+
+func (s *Server) negotiateContract(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireDocked(sh); err != nil {
+		return 0, nil, err
+	}
+	wp := s.u.byWP[sh.Nav.WaypointSymbol]
+	if wp.Faction == nil {
+		return 0, nil, badRequest(models.ErrCodeWaypointNoFaction, "Waypoint %s has no faction to negotiate with.", wp.Symbol)
+	}
+	for _, ct := range c.agent.contracts {
+		if !ct.Fulfilled && s.now().Before(ct.Terms.Deadline) {
+			return 0, nil, badRequest(models.ErrCodeExistingContract, "Agent already has an active contract %s.", ct.ID)
+		}
+	}
+	ct := s.addContract(c.agent, wp.Faction.Symbol)
+	return http.StatusCreated, data(map[string]any{"contract": ct}), nil
+}
@@ -0,0 +1,469 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	v2 "spacetradersgo/v2"
+	"spacetradersgo/v2/agents"
+	"spacetradersgo/v2/fake"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+	"spacetradersgo/v2/systems"
+)
+
+var epoch = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// session is an agent registered through the API of a fake server whose
+// clock only moves when the test advances it.
+type session struct {
+	srv   *fake.Server
+	clock *fake.ManualClock
+	sdk   *v2.SpcaeTradersClient
+	token string
+	ship  models.Ship
+	agent models.Agent
+}
+
+func newSession(t *testing.T) *session {
+	t.Helper()
+	clock := fake.NewManualClock(epoch)
+	srv := fake.NewServer(fake.WithSeed(7), fake.WithClock(clock))
+	t.Cleanup(srv.Close)
+	sdk := v2.NewSpaceTradersClient(v2.WithHTTPClient(srv.Client()))
+
+	resp, err := sdk.Agents.NewAgent(context.Background(), &agents.NewAgentRequest{Symbol: "TESTER", Faction: "COSMIC"})
+	if err != nil {
+		t.Fatalf("NewAgent: %v", err)
+	}
+	return &session{srv: srv, clock: clock, sdk: sdk, token: resp.Data.Token, ship: resp.Data.Ship, agent: resp.Data.Agent}
+}
+
+func (s *session) waypoints(t *testing.T) []models.Waypoint {
+	t.Helper()
+	resp, err := s.sdk.Systems.ListWaypoints(context.Background(), &systems.ListWaypointsRequest{Token: s.token, SystemID: s.ship.Nav.SystemSymbol, NumPerPage: 20})
+	if err != nil {
+		t.Fatalf("ListWaypoints: %v", err)
+	}
+	return resp.Waypoints
+}
+
+func (s *session) waypoint(t *testing.T, symbol string) models.Waypoint {
+	t.Helper()
+	for _, wp := range s.waypoints(t) {
+		if wp.Symbol == symbol {
+			return wp
+		}
+	}
+	t.Fatalf("waypoint %s not found", symbol)
+	return models.Waypoint{}
+}
+
+// find returns the waypoint of the ship's system nearest to it that match
+// accepts, leaving out those at the ship's coordinates.
+func (s *session) find(t *testing.T, match func(models.Waypoint) bool) models.Waypoint {
+	t.Helper()
+	here := s.waypoint(t, s.ship.Nav.WaypointSymbol)
+	var best models.Waypoint
+	for _, wp := range s.waypoints(t) {
+		if navigation.WaypointDistance(here, wp) == 0 || !match(wp) {
+			continue
+		}
+		if best.Symbol == "" || navigation.WaypointDistance(here, wp) < navigation.WaypointDistance(here, best) {
+			best = wp
+		}
+	}
+	if best.Symbol == "" {
+		t.Fatal("no matching waypoint in the home system")
+	}
+	return best
+}
+
+// fly orbits, navigates to dest and advances the clock to the arrival.
+func (s *session) fly(t *testing.T, dest string) *fleets.NavigateShipResponse {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := s.sdk.Fleets.OrbitShip(ctx, &fleets.OrbitShipRequest{Token: s.token, ShipID: s.ship.Symbol}); err != nil {
+		t.Fatalf("OrbitShip: %v", err)
+	}
+	resp, err := s.sdk.Fleets.NavigateShip(ctx, &fleets.NavigateShipRequest{Token: s.token, ShipID: s.ship.Symbol, WaypointSymbol: dest})
+	if err != nil {
+		t.Fatalf("NavigateShip: %v", err)
+	}
+	s.clock.Set(resp.Data.Nav.Route.Arrival)
+	s.ship.Nav = resp.Data.Nav
+	s.ship.Fuel = resp.Data.Fuel
+	return resp
+}
+
+func hasTrait(wp models.Waypoint, symbol string) bool {
+	for _, tr := range wp.Traits {
+		if tr.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRegister(t *testing.T) {
+	s := newSession(t)
+	ctx := context.Background()
+
+	if s.agent.Credits != 100000 || s.agent.Headquarters == "" {
+		t.Errorf("new agent = %+v", s.agent)
+	}
+	if s.ship.Symbol != "TESTER-1" || s.ship.Nav.WaypointSymbol != s.agent.Headquarters || s.ship.Nav.Status != models.ShipNavStatusDocked {
+		t.Errorf("new ship %s at %s %s, want TESTER-1 docked at %s", s.ship.Symbol, s.ship.Nav.WaypointSymbol, s.ship.Nav.Status, s.agent.Headquarters)
+	}
+
+	got, err := s.sdk.Agents.GetAgent(ctx, &agents.GetAgentRequest{Token: s.token})
+	if err != nil {
+		t.Fatalf("GetAgent: %v", err)
+	}
+	if got.Agent.Symbol != "TESTER" {
+		t.Errorf("GetAgent symbol = %s, want TESTER", got.Agent.Symbol)
+	}
+
+	_, err = s.sdk.Agents.NewAgent(ctx, &agents.NewAgentRequest{Symbol: "TESTER", Faction: "COSMIC"})
+	if !models.IsCode(err, models.ErrCodeRegisterAgentExists) {
+		t.Errorf("registering TESTER twice: err = %v, want code %d", err, models.ErrCodeRegisterAgentExists)
+	}
+	_, err = s.sdk.Agents.GetAgent(ctx, &agents.GetAgentRequest{Token: "nonsense"})
+	var apiErr *models.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 401 {
+		t.Errorf("GetAgent with a bad token: err = %v, want a 401", err)
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	s := newSession(t)
+	ctx := context.Background()
+	origin := s.waypoint(t, s.ship.Nav.WaypointSymbol)
+	dest := s.find(t, func(wp models.Waypoint) bool { return true })
+
+	_, err := s.sdk.Fleets.NavigateShip(ctx, &fleets.NavigateShipRequest{Token: s.token, ShipID: s.ship.Symbol, WaypointSymbol: dest.Symbol})
+	if !models.IsCode(err, models.ErrCodeShipNotInOrbit) {
+		t.Fatalf("navigating while docked: err = %v, want code %d", err, models.ErrCodeShipNotInOrbit)
+	}
+
+	if _, err := s.sdk.Fleets.OrbitShip(ctx, &fleets.OrbitShipRequest{Token: s.token, ShipID: s.ship.Symbol}); err != nil {
+		t.Fatalf("OrbitShip: %v", err)
+	}
+	resp, err := s.sdk.Fleets.NavigateShip(ctx, &fleets.NavigateShipRequest{Token: s.token, ShipID: s.ship.Symbol, WaypointSymbol: dest.Symbol})
+	if err != nil {
+		t.Fatalf("NavigateShip: %v", err)
+	}
+
+	dist := math.Round(navigation.WaypointDistance(origin, dest))
+	nav, fuel := resp.Data.Nav, resp.Data.Fuel
+	if nav.Status != models.ShipNavStatusInTransit {
+		t.Errorf("status after NavigateShip = %s, want IN_TRANSIT", nav.Status)
+	}
+	if want := s.ship.Fuel.Capacity - navigation.FuelCost(dist, models.ShipNavFlightModeCruise); fuel.Current != want {
+		t.Errorf("fuel after flying %.0f = %d, want %d", dist, fuel.Current, want)
+	}
+	if got, want := nav.Route.Arrival.Sub(nav.Route.DepartureTime), navigation.TravelTime(dist, s.ship.Engine.Speed, models.ShipNavFlightModeCruise); got != want {
+		t.Errorf("flight time = %s, want %s", got, want)
+	}
+	if !nav.Route.DepartureTime.Equal(epoch) {
+		t.Errorf("departure = %s, want the server clock's %s", nav.Route.DepartureTime, epoch)
+	}
+
+	_, err = s.sdk.Fleets.DockShip(ctx, &fleets.DockShipRequest{Token: s.token, ShipID: s.ship.Symbol})
+	if !models.IsCode(err, models.ErrCodeShipInTransit) {
+		t.Errorf("docking in transit: err = %v, want code %d", err, models.ErrCodeShipInTransit)
+	}
+
+	s.clock.Set(nav.Route.Arrival.Add(-time.Second))
+	got, err := s.sdk.Fleets.GetShipNav(ctx, &fleets.GetShipNavRequest{Token: s.token, ShipID: s.ship.Symbol})
+	if err != nil {
+		t.Fatalf("GetShipNav: %v", err)
+	}
+	if got.Nav.Status != models.ShipNavStatusInTransit {
+		t.Errorf("status a second before arrival = %s, want IN_TRANSIT", got.Nav.Status)
+	}
+
+	s.clock.Advance(time.Second)
+	got, err = s.sdk.Fleets.GetShipNav(ctx, &fleets.GetShipNavRequest{Token: s.token, ShipID: s.ship.Symbol})
+	if err != nil {
+		t.Fatalf("GetShipNav: %v", err)
+	}
+	if got.Nav.Status != models.ShipNavStatusInOrbit || got.Nav.WaypointSymbol != dest.Symbol {
+		t.Errorf("after arrival the ship is %s at %s, want IN_ORBIT at %s", got.Nav.Status, got.Nav.WaypointSymbol, dest.Symbol)
+	}
+	if _, err := s.sdk.Fleets.DockShip(ctx, &fleets.DockShipRequest{Token: s.token, ShipID: s.ship.Symbol}); err != nil {
+		t.Errorf("DockShip after arrival: %v", err)
+	}
+}
+
+func TestNavigateInsufficientFuel(t *testing.T) {
+	s := newSession(t)
+	ctx := context.Background()
+	here := s.waypoint(t, s.ship.Nav.WaypointSymbol)
+	var dest models.Waypoint
+	farthest := 0.0
+	for _, wp := range s.waypoints(t) {
+		if d := navigation.WaypointDistance(here, wp); d > farthest {
+			dest, farthest = wp, d
+		}
+	}
+
+	if _, err := s.sdk.Fleets.OrbitShip(ctx, &fleets.OrbitShipRequest{Token: s.token, ShipID: s.ship.Symbol}); err != nil {
+		t.Fatalf("OrbitShip: %v", err)
+	}
+	// Burn fuel in BURN mode back and forth until a flight no longer fits.
+	if _, err := s.sdk.Fleets.PatchShipNav(ctx, &fleets.PatchShipNavRequest{Token: s.token, ShipID: s.ship.Symbol, FlightMode: models.ShipNavFlightModeBurn}); err != nil {
+		t.Fatalf("PatchShipNav: %v", err)
+	}
+	targets := []string{dest.Symbol, s.ship.Nav.WaypointSymbol}
+	for i := 0; ; i++ {
+		resp, err := s.sdk.Fleets.NavigateShip(ctx, &fleets.NavigateShipRequest{Token: s.token, ShipID: s.ship.Symbol, WaypointSymbol: targets[i%2]})
+		if models.IsCode(err, models.ErrCodeNavigateInsufficientFuel) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NavigateShip: %v", err)
+		}
+		if i > 1000 {
+			t.Fatal("fuel never ran out")
+		}
+		s.clock.Set(resp.Data.Nav.Route.Arrival)
+	}
+}
+
+func TestTrade(t *testing.T) {
+	s := newSession(t)
+	ctx := context.Background()
+	dest := s.find(t, func(wp models.Waypoint) bool { return hasTrait(wp, "MARKETPLACE") })
+	s.fly(t, dest.Symbol)
+
+	if _, err := s.sdk.Fleets.DockShip(ctx, &fleets.DockShipRequest{Token: s.token, ShipID: s.ship.Symbol}); err != nil {
+		t.Fatalf("DockShip: %v", err)
+	}
+	market, err := s.sdk.Systems.GetMarket(ctx, &systems.GetMarketRequest{Token: s.token, SystemID: dest.SystemSymbol, WaypointID: dest.Symbol})
+	if err != nil {
+		t.Fatalf("GetMarket: %v", err)
+	}
+	if len(market.Market.TradeGoods) == 0 {
+		t.Fatal("market shows no trade goods with a ship present")
+	}
+	good := market.Market.TradeGoods[0]
+
+	credits := s.agent.Credits
+	bought, err := s.sdk.Fleets.PurchaseCargo(ctx, &fleets.PurchaseCargoRequest{Token: s.token, ShipID: s.ship.Symbol, Symbol: good.Symbol, Units: 2})
+	if err != nil {
+		t.Fatalf("PurchaseCargo: %v", err)
+	}
+	tx := bought.Data.Transaction
+	if tx.PricePerUnit != good.PurchasePrice || tx.TotalPrice != 2*good.PurchasePrice || tx.Type != models.MarketTransactionTypePurchase {
+		t.Errorf("purchase transaction = %+v, want 2 units at %d", tx, good.PurchasePrice)
+	}
+	if want := credits - int64(tx.TotalPrice); bought.Data.Agent.Credits != want {
+		t.Errorf("credits after purchase = %d, want %d", bought.Data.Agent.Credits, want)
+	}
+	if bought.Data.Cargo.Units != 2 {
+		t.Errorf("cargo units after purchase = %d, want 2", bought.Data.Cargo.Units)
+	}
+
+	sold, err := s.sdk.Fleets.SellCargo(ctx, &fleets.SellCargoRequest{Token: s.token, ShipID: s.ship.Symbol, Symbol: good.Symbol, Units: 2})
+	if err != nil {
+		t.Fatalf("SellCargo: %v", err)
+	}
+	if want := bought.Data.Agent.Credits + int64(sold.Data.Transaction.TotalPrice); sold.Data.Agent.Credits != want {
+		t.Errorf("credits after sale = %d, want %d", sold.Data.Agent.Credits, want)
+	}
+	if sold.Data.Cargo.Units != 0 {
+		t.Errorf("cargo units after sale = %d, want 0", sold.Data.Cargo.Units)
+	}
+	_, err = s.sdk.Fleets.SellCargo(ctx, &fleets.SellCargoRequest{Token: s.token, ShipID: s.ship.Symbol, Symbol: good.Symbol, Units: 1})
+	if err == nil {
+		t.Error("selling cargo the ship doesn't hold succeeded")
+	}
+
+	refuel, err := s.sdk.Fleets.RefuelShip(ctx, &fleets.RefuelShipRequest{Token: s.token, ShipID: s.ship.Symbol})
+	if err != nil {
+		t.Fatalf("RefuelShip: %v", err)
+	}
+	if refuel.Data.Fuel.Current != s.ship.Fuel.Capacity {
+		t.Errorf("fuel after refuelling = %d, want %d", refuel.Data.Fuel.Current, s.ship.Fuel.Capacity)
+	}
+	if refuel.Data.Transaction.TradeSymbol != string(models.TradeSymbolFuel) {
+		t.Errorf("refuel transaction = %+v, want FUEL", refuel.Data.Transaction)
+	}
+}
+
+func TestCooldown(t *testing.T) {
+	s := newSession(t)
+	ctx := context.Background()
+	field := s.find(t, func(wp models.Waypoint) bool { return wp.Type == models.WaypointTypeAsteroidField })
+	s.fly(t, field.Symbol)
+
+	cd, err := s.sdk.Fleets.GetShipCooldown(ctx, &fleets.GetShipCooldownRequest{Token: s.token, ShipID: s.ship.Symbol})
+	if err != nil {
+		t.Fatalf("GetShipCooldown: %v", err)
+	}
+	if cd.IsOnCooldown {
+		t.Errorf("fresh ship is on cooldown: %+v", cd.Cooldown)
+	}
+
+	extract := func() (*fleets.ExtractResourceResponse, error) {
+		return s.sdk.Fleets.ExtractResource(ctx, &fleets.ExtractResourceRequest{Token: s.token, ShipID: s.ship.Symbol})
+	}
+	resp, err := extract()
+	if err != nil {
+		t.Fatalf("ExtractResource: %v", err)
+	}
+	if resp.Data.Extraction.Yield.Units < 1 || resp.Data.Cargo.Units != resp.Data.Extraction.Yield.Units {
+		t.Errorf("extraction yielded %+v into cargo of %d units", resp.Data.Extraction.Yield, resp.Data.Cargo.Units)
+	}
+	total := time.Duration(resp.Data.Cooldown.TotalSeconds) * time.Second
+	if total <= 0 || !resp.Data.Cooldown.Expiration.Equal(s.clock.Now().Add(total)) {
+		t.Errorf("cooldown after extracting = %+v", resp.Data.Cooldown)
+	}
+
+	_, err = extract()
+	if !models.IsCode(err, models.ErrCodeCooldownConflict) {
+		t.Errorf("extracting on cooldown: err = %v, want code %d", err, models.ErrCodeCooldownConflict)
+	}
+
+	s.clock.Advance(total / 2)
+	cd, err = s.sdk.Fleets.GetShipCooldown(ctx, &fleets.GetShipCooldownRequest{Token: s.token, ShipID: s.ship.Symbol})
+	if err != nil {
+		t.Fatalf("GetShipCooldown: %v", err)
+	}
+	if want := int((total - total/2) / time.Second); !cd.IsOnCooldown || cd.Cooldown.RemainingSeconds != want {
+		t.Errorf("cooldown half way = %+v, want %d seconds remaining", cd.Cooldown, want)
+	}
+
+	s.clock.Advance(total - total/2)
+	cd, err = s.sdk.Fleets.GetShipCooldown(ctx, &fleets.GetShipCooldownRequest{Token: s.token, ShipID: s.ship.Symbol})
+	if err != nil {
+		t.Fatalf("GetShipCooldown: %v", err)
+	}
+	if cd.IsOnCooldown {
+		t.Errorf("cooldown after it expired = %+v", cd.Cooldown)
+	}
+	if _, err := extract(); err != nil {
+		t.Errorf("ExtractResource after the cooldown: %v", err)
+	}
+}
+
+func TestMarketRecovery(t *testing.T) {
+	clock := fake.NewManualClock(epoch)
+	srv := fake.NewServer(fake.WithSeed(7), fake.WithClock(clock), fake.WithMarketRecovery(time.Hour))
+	defer srv.Close()
+	sdk := v2.NewSpaceTradersClient(v2.WithHTTPClient(srv.Client()))
+	ctx := context.Background()
+
+	reg, err := sdk.Agents.NewAgent(ctx, &agents.NewAgentRequest{Symbol: "TESTER", Faction: "COSMIC"})
+	if err != nil {
+		t.Fatalf("NewAgent: %v", err)
+	}
+	token, ship := reg.Data.Token, reg.Data.Ship
+	hq := ship.Nav.WaypointSymbol
+	err = srv.SetMarket(hq, fake.MarketProfile{
+		Exports:     []models.TradeSymbol{models.TradeSymbolIronOre},
+		TradeVolume: 10,
+		BasePrices:  map[models.TradeSymbol]int{models.TradeSymbolIronOre: 100},
+	})
+	if err != nil {
+		t.Fatalf("SetMarket: %v", err)
+	}
+
+	price := func() models.MarketTradeGood {
+		t.Helper()
+		resp, err := sdk.Systems.GetMarket(ctx, &systems.GetMarketRequest{Token: token, SystemID: ship.Nav.SystemSymbol, WaypointID: hq})
+		if err != nil {
+			t.Fatalf("GetMarket: %v", err)
+		}
+		for _, g := range resp.Market.TradeGoods {
+			if g.Symbol == string(models.TradeSymbolIronOre) {
+				return g
+			}
+		}
+		t.Fatal("market doesn't trade IRON_ORE")
+		return models.MarketTradeGood{}
+	}
+
+	// An export rests at 3/4 stock, where the mid price is 3/4 of the base
+	// price. Buying two trade volumes takes it down to 1/4 stock.
+	if g := price(); g.PurchasePrice != 78 || g.Supply != models.SupplyLevelAbundant {
+		t.Fatalf("resting price %d %s, want 78 ABUNDANT", g.PurchasePrice, g.Supply)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := sdk.Fleets.PurchaseCargo(ctx, &fleets.PurchaseCargoRequest{Token: token, ShipID: ship.Symbol, Symbol: string(models.TradeSymbolIronOre), Units: 10}); err != nil {
+			t.Fatalf("PurchaseCargo: %v", err)
+		}
+	}
+	if g := price(); g.PurchasePrice != 130 || g.Supply != models.SupplyLevelLimited {
+		t.Errorf("price after buying 20 units %d %s, want 130 LIMITED", g.PurchasePrice, g.Supply)
+	}
+
+	// After one half-life stock is half way back, at 1/2.
+	clock.Advance(time.Hour)
+	if g := price(); g.PurchasePrice != 104 || g.Supply != models.SupplyLevelModerate {
+		t.Errorf("price an hour later %d %s, want 104 MODERATE", g.PurchasePrice, g.Supply)
+	}
+	clock.Advance(24 * time.Hour)
+	if g := price(); g.PurchasePrice != 78 {
+		t.Errorf("price a day later %d, want 78", g.PurchasePrice)
+	}
+}
+
+func TestSeedDeterminism(t *testing.T) {
+	list := func() []models.System {
+		srv := fake.NewServer(fake.WithSeed(7), fake.WithClock(fake.NewManualClock(epoch)))
+		defer srv.Close()
+		token, err := srv.Register("TESTER", "COSMIC")
+		if err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+		sdk := v2.NewSpaceTradersClient(v2.WithHTTPClient(srv.Client()))
+		resp, err := sdk.Systems.ListSystems(context.Background(), &systems.ListSystemsRequest{Token: token, NumPerPage: 20})
+		if err != nil {
+			t.Fatalf("ListSystems: %v", err)
+		}
+		return resp.Systems
+	}
+	a, b := list(), list()
+	if len(a) == 0 || len(a) != len(b) {
+		t.Fatalf("got %d and %d systems", len(a), len(b))
+	}
+	for i := range a {
+		if a[i].Symbol != b[i].Symbol || a[i].X != b[i].X || a[i].Y != b[i].Y {
+			t.Errorf("system %d differs between runs: %+v and %+v", i, a[i], b[i])
+		}
+	}
+}
+
+func TestSetMarketKeepsSeedOutcomes(t *testing.T) {
+	register := func(setMarket bool) models.Contract {
+		srv := fake.NewServer(fake.WithSeed(7), fake.WithClock(fake.NewManualClock(epoch)))
+		defer srv.Close()
+		sdk := v2.NewSpaceTradersClient(v2.WithHTTPClient(srv.Client()))
+		ctx := context.Background()
+		first, err := sdk.Agents.NewAgent(ctx, &agents.NewAgentRequest{Symbol: "FIRST", Faction: "COSMIC"})
+		if err != nil {
+			t.Fatalf("NewAgent: %v", err)
+		}
+		if setMarket {
+			err := srv.SetMarket(first.Data.Agent.Headquarters, fake.MarketProfile{Exports: []models.TradeSymbol{models.TradeSymbolIronOre}})
+			if err != nil {
+				t.Fatalf("SetMarket: %v", err)
+			}
+		}
+		second, err := sdk.Agents.NewAgent(ctx, &agents.NewAgentRequest{Symbol: "SECOND", Faction: "COSMIC"})
+		if err != nil {
+			t.Fatalf("NewAgent: %v", err)
+		}
+		return second.Data.Contract
+	}
+	without, with := register(false), register(true)
+	if without.ID != with.ID || without.Terms.Deliver[0] != with.Terms.Deliver[0] {
+		t.Errorf("SetMarket changed the next contract drawn from the seed: %+v, then %+v", without, with)
+	}
+}
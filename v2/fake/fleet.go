@@ -0,0 +1,530 @@
+package fake
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+	"strings"
+	"time"
+)
+
+func (s *Server) requireDocked(sh *ship) *apiError {
+	if err := s.requireArrived(sh); err != nil {
+		return err
+	}
+	if sh.Nav.Status != models.ShipNavStatusDocked {
+		return badRequest(models.ErrCodeShipNotDocked, "Ship %s must be docked.", sh.Symbol)
+	}
+	return nil
+}
+
+func (s *Server) requireOrbit(sh *ship) *apiError {
+	if err := s.requireArrived(sh); err != nil {
+		return err
+	}
+	if sh.Nav.Status != models.ShipNavStatusInOrbit {
+		return badRequest(models.ErrCodeShipNotInOrbit, "Ship %s must be in orbit.", sh.Symbol)
+	}
+	return nil
+}
+
+func (s *Server) requireArrived(sh *ship) *apiError {
+	if sh.Nav.Status == models.ShipNavStatusInTransit {
+		return badRequest(models.ErrCodeShipInTransit, "Ship %s is currently in transit to %s.", sh.Symbol, sh.Nav.WaypointSymbol)
+	}
+	return nil
+}
+
+// mountStrength adds up the strength of every mount whose symbol starts
+// with prefix, e.g. all mining lasers.
+func mountStrength(sh *ship, prefix string) int {
+	total := 0
+	for _, m := range sh.Mounts {
+		if strings.HasPrefix(m.Symbol, prefix) {
+			total += m.Strength
+		}
+	}
+	return total
+}
+
+func moduleRange(sh *ship, symbol string) (int, bool) {
+	for _, m := range sh.Modules {
+		if m.Symbol == symbol {
+			return m.Range, true
+		}
+	}
+	return 0, false
+}
+
+func addCargo(sh *ship, symbol string, units int) *apiError {
+	if sh.Cargo.Units+units > sh.Cargo.Capacity {
+		return badRequest(models.ErrCodeCargoExceedsLimit, "Ship %s has %d units of free cargo space, %d needed.",
+			sh.Symbol, sh.Cargo.Capacity-sh.Cargo.Units, units)
+	}
+	sh.Cargo.Units += units
+	for i := range sh.Cargo.Inventory {
+		if sh.Cargo.Inventory[i].Symbol == symbol {
+			sh.Cargo.Inventory[i].Units += units
+			return nil
+		}
+	}
+	sh.Cargo.Inventory = append(sh.Cargo.Inventory, cargoItem(symbol, units))
+	return nil
+}
+
+func removeCargo(sh *ship, symbol string, units int) *apiError {
+	if units < 1 {
+		return errorf(http.StatusUnprocessableEntity, models.ErrCodeValidation, "units must be at least 1")
+	}
+	for i, item := range sh.Cargo.Inventory {
+		if item.Symbol != symbol {
+			continue
+		}
+		if item.Units < units {
+			return badRequest(models.ErrCodeCargoUnitCount, "Ship %s has %d units of %s, %d requested.", sh.Symbol, item.Units, symbol, units)
+		}
+		sh.Cargo.Units -= units
+		sh.Cargo.Inventory[i].Units -= units
+		if sh.Cargo.Inventory[i].Units == 0 {
+			sh.Cargo.Inventory = append(sh.Cargo.Inventory[:i], sh.Cargo.Inventory[i+1:]...)
+		}
+		return nil
+	}
+	return badRequest(models.ErrCodeCargoMissing, "Ship %s has no %s in its cargo.", sh.Symbol, symbol)
+}
+
+// cooldown reports the ship's cooldown, which is all zeroes once it has
+// expired.
+func (s *Server) cooldown(sh *ship) models.Cooldown {
+	remaining := int(math.Ceil(sh.cooldownUntil.Sub(s.now()).Seconds()))
+	if remaining <= 0 {
+		return models.Cooldown{ShipSymbol: sh.Symbol}
+	}
+	return models.Cooldown{ShipSymbol: sh.Symbol, TotalSeconds: sh.cooldownTotal, RemainingSeconds: remaining,
+		Expiration: sh.cooldownUntil}
+}
+
+func (s *Server) listShips(c *call) (int, any, error) {
+	page, limit, err := c.page()
+	if err != nil {
+		return 0, nil, err
+	}
+	all := c.agent.sortedShips()
+	lo, hi, meta := paginate(len(all), page, limit)
+	ships := make([]models.Ship, 0, hi-lo)
+	for _, sh := range all[lo:hi] {
+		ships = append(ships, sh.Ship)
+	}
+	return http.StatusOK, paged{Data: ships, Meta: meta}, nil
+}
+
+func (s *Server) purchaseShip(c *call) (int, any, error) {
+	var body struct {
+		ShipType       models.ShipType `json:"shipType"`
+		WaypointSymbol string          `json:"waypointSymbol"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	wp, ok := s.u.byWP[body.WaypointSymbol]
+	if !ok {
+		return 0, nil, notFound("Waypoint %s not found.", body.WaypointSymbol)
+	}
+	if wp.shipyard == nil {
+		return 0, nil, badRequest(models.ErrCodeShipyardNotFound, "Waypoint %s has no shipyard.", wp.Symbol)
+	}
+	if !wp.shipyard.sells(body.ShipType) {
+		return 0, nil, badRequest(models.ErrCodeShipyardShipUnavailable, "Shipyard %s does not sell %s.", wp.Symbol, body.ShipType)
+	}
+	if !s.present(c.agent, wp.Symbol) {
+		return 0, nil, badRequest(models.ErrCodeWaypointNoAccess, "Agent has no ship at %s to take delivery.", wp.Symbol)
+	}
+	price := shipTemplates[body.ShipType].price
+	if c.agent.Credits < int64(price) {
+		return 0, nil, badRequest(models.ErrCodePurchaseShipCredits, "Ship costs %d credits, agent has %d.", price, c.agent.Credits)
+	}
+
+	c.agent.Credits -= int64(price)
+	sh := s.addShip(c.agent, body.ShipType, wp)
+	tx := models.ShipyardTransaction{WaypointSymbol: wp.Symbol, ShipSymbol: sh.Symbol, Price: price,
+		AgentSymbol: c.agent.Symbol, Timestamp: s.now()}
+	wp.shipyard.transactions = append(wp.shipyard.transactions, tx)
+	return http.StatusCreated, data(map[string]any{"agent": c.agent.Agent, "ship": sh.Ship, "transaction": tx}), nil
+}
+
+func (s *Server) getShip(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(sh.Ship), nil
+}
+
+func (s *Server) getShipCargo(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(sh.Cargo), nil
+}
+
+func (s *Server) getShipNav(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(sh.Nav), nil
+}
+
+func (s *Server) orbitShip(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireArrived(sh); err != nil {
+		return 0, nil, err
+	}
+	sh.Nav.Status = models.ShipNavStatusInOrbit
+	return http.StatusOK, data(map[string]any{"nav": sh.Nav}), nil
+}
+
+func (s *Server) dockShip(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireArrived(sh); err != nil {
+		return 0, nil, err
+	}
+	sh.Nav.Status = models.ShipNavStatusDocked
+	return http.StatusOK, data(map[string]any{"nav": sh.Nav}), nil
+}
+
+func (s *Server) patchShipNav(c *call) (int, any, error) {
+	var body struct {
+		FlightMode models.ShipNavFlightMode `json:"flightMode"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	switch body.FlightMode {
+	case models.ShipNavFlightModeDrift, models.ShipNavFlightModeStealth, models.ShipNavFlightModeCruise, models.ShipNavFlightModeBurn:
+	default:
+		return 0, nil, errorf(http.StatusUnprocessableEntity, models.ErrCodeValidation, "Invalid flight mode %q.", body.FlightMode)
+	}
+	sh.Nav.FlightMode = body.FlightMode
+	return http.StatusOK, data(sh.Nav), nil
+}
+
+func (s *Server) getShipCooldown(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	cd := s.cooldown(sh)
+	if cd.RemainingSeconds == 0 {
+		return http.StatusNoContent, nil, nil
+	}
+	return http.StatusOK, data(cd), nil
+}
+
+// moveTo sends the ship to dest, arriving in orbit after travel. A ship
+// with no travel time, as after a jump, arrives at once.
+func (s *Server) moveTo(sh *ship, dest *waypoint, travel time.Duration) {
+	origin := s.u.byWP[sh.Nav.WaypointSymbol]
+	now := s.now()
+	sh.Nav.SystemSymbol = dest.SystemSymbol
+	sh.Nav.WaypointSymbol = dest.Symbol
+	sh.Nav.Route = models.ShipNavRoute{
+		Departure:     origin.routeWaypoint(),
+		Destination:   dest.routeWaypoint(),
+		DepartureTime: now,
+		Arrival:       now.Add(travel),
+	}
+	sh.Nav.Status = models.ShipNavStatusInOrbit
+	if travel > 0 {
+		sh.Nav.Status = models.ShipNavStatusInTransit
+	}
+}
+
+func (s *Server) navigateShip(c *call) (int, any, error) {
+	var body struct {
+		WaypointSymbol string `json:"waypointSymbol"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if sh.Nav.Status == models.ShipNavStatusInTransit {
+		return 0, nil, badRequest(models.ErrCodeNavigateInTransit, "Ship %s is currently in transit.", sh.Symbol)
+	}
+	if err := s.requireOrbit(sh); err != nil {
+		return 0, nil, err
+	}
+	dest, ok := s.u.byWP[body.WaypointSymbol]
+	if !ok {
+		return 0, nil, badRequest(models.ErrCodeNavigateInvalidDest, "Waypoint %s does not exist.", body.WaypointSymbol)
+	}
+	if dest.SystemSymbol != sh.Nav.SystemSymbol {
+		return 0, nil, badRequest(models.ErrCodeNavigateOutsideSystem, "Waypoint %s is outside the ship's system %s.", dest.Symbol, sh.Nav.SystemSymbol)
+	}
+	if dest.Symbol == sh.Nav.WaypointSymbol {
+		return 0, nil, badRequest(models.ErrCodeNavigateSameDestination, "Ship %s is already at %s.", sh.Symbol, dest.Symbol)
+	}
+	origin := s.u.byWP[sh.Nav.WaypointSymbol]
+	if err := s.fly(sh, dest, distance(origin.X, origin.Y, dest.X, dest.Y)); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(map[string]any{"fuel": sh.Fuel, "nav": sh.Nav}), nil
+}
+
+func (s *Server) warpShip(c *call) (int, any, error) {
+	var body struct {
+		WaypointSymbol string `json:"waypointSymbol"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireOrbit(sh); err != nil {
+		return 0, nil, err
+	}
+	if _, ok := moduleRange(sh, moduleWarpDrive); !ok {
+		return 0, nil, badRequest(models.ErrCodeMissingWarpDrive, "Ship %s has no warp drive.", sh.Symbol)
+	}
+	dest, ok := s.u.byWP[body.WaypointSymbol]
+	if !ok {
+		return 0, nil, badRequest(models.ErrCodeNavigateInvalidDest, "Waypoint %s does not exist.", body.WaypointSymbol)
+	}
+	if dest.SystemSymbol == sh.Nav.SystemSymbol {
+		return 0, nil, badRequest(models.ErrCodeWarpInsideSystem, "Use navigate to travel within system %s.", dest.SystemSymbol)
+	}
+	if err := s.fly(sh, dest, systemDistance(s.u.bySystem[sh.Nav.SystemSymbol], s.u.bySystem[dest.SystemSymbol])); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(map[string]any{"fuel": sh.Fuel, "nav": sh.Nav}), nil
+}
+
+func (s *Server) jumpShip(c *call) (int, any, error) {
+	var body struct {
+		SystemSymbol string `json:"systemSymbol"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireOrbit(sh); err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireCooldown(sh); err != nil {
+		return 0, nil, err
+	}
+	target, ok := s.u.bySystem[body.SystemSymbol]
+	if !ok {
+		return 0, nil, badRequest(models.ErrCodeJumpNoSystem, "System %s does not exist.", body.SystemSymbol)
+	}
+	if target.Symbol == sh.Nav.SystemSymbol {
+		return 0, nil, badRequest(models.ErrCodeJumpSameSystem, "Ship %s is already in %s.", sh.Symbol, target.Symbol)
+	}
+
+	here := s.u.byWP[sh.Nav.WaypointSymbol]
+	origin := s.u.bySystem[here.SystemSymbol]
+	if here.jumpGate != nil {
+		connected := false
+		for _, cs := range here.jumpGate.ConnectedSystems {
+			connected = connected || cs.Symbol == target.Symbol
+		}
+		if !connected {
+			return 0, nil, badRequest(models.ErrCodeJumpNoSystem, "Jump gate %s does not connect to %s.", here.Symbol, target.Symbol)
+		}
+	} else {
+		rng, ok := moduleRange(sh, moduleJumpDrive)
+		if !ok {
+			return 0, nil, badRequest(models.ErrCodeJumpMissingModule, "Ship %s is not at a jump gate and has no jump drive.", sh.Symbol)
+		}
+		if systemDistance(origin, target) > rng {
+			return 0, nil, badRequest(models.ErrCodeJumpNoValidWaypoint, "System %s is beyond the jump drive's range of %d.", target.Symbol, rng)
+		}
+	}
+	s.moveTo(sh, target.gate, 0)
+	s.startCooldown(sh, navigation.JumpCooldown(float64(systemDistance(origin, target))))
+	return http.StatusOK, data(map[string]any{"cooldown": s.cooldown(sh), "nav": sh.Nav}), nil
+}
+
+func (s *Server) createChart(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireArrived(sh); err != nil {
+		return 0, nil, err
+	}
+	wp := s.u.byWP[sh.Nav.WaypointSymbol]
+	if wp.Chart != nil {
+		return 0, nil, badRequest(models.ErrCodeWaypointCharted, "Waypoint %s has already been charted.", wp.Symbol)
+	}
+	wp.Chart = &models.Chart{WaypointSymbol: wp.Symbol, SubmittedBy: c.agent.Symbol, SubmittedOn: s.now()}
+	traits := wp.Traits[:0]
+	for _, t := range wp.Traits {
+		if t.Symbol != "UNCHARTED" {
+			traits = append(traits, t)
+		}
+	}
+	wp.Traits = traits
+	return http.StatusCreated, data(map[string]any{"chart": wp.Chart, "waypoint": wp.Waypoint}), nil
+}
+
+type survey struct {
+	models.Survey
+	// remaining is how many more extractions the surveyed deposit allows.
+	remaining int
+}
+
+var surveySizes = []struct {
+	size        models.SurveySize
+	extractions int
+	bonus       int
+}{
+	{models.SurveySizeSmall, 10, 1},
+	{models.SurveySizeModerate, 25, 3},
+	{models.SurveySizeLarge, 50, 5},
+}
+
+func (s *Server) createSurvey(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireArrived(sh); err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireCooldown(sh); err != nil {
+		return 0, nil, err
+	}
+	strength := mountStrength(sh, mountSurveyor)
+	if strength == 0 {
+		return 0, nil, badRequest(models.ErrCodeMissingSurveyor, "Ship %s has no surveyor mounted.", sh.Symbol)
+	}
+	wp := s.u.byWP[sh.Nav.WaypointSymbol]
+	if len(wp.deposits) == 0 {
+		return 0, nil, badRequest(models.ErrCodeSurveyWaypointType, "Waypoint %s has nothing to survey.", wp.Symbol)
+	}
+
+	surveys := []models.Survey{}
+	for i := 0; i < strength; i++ {
+		size := surveySizes[s.rng.Intn(len(surveySizes))]
+		sv := &survey{Survey: models.Survey{
+			Signature:  fmt.Sprintf("%s-%06X", wp.Symbol, s.rng.Intn(1<<24)),
+			Symbol:     wp.Symbol,
+			Deposits:   []models.SurveyDeposit{},
+			Expiration: s.now().Add(time.Duration(15+s.rng.Intn(45)) * time.Minute),
+			Size:       size.size,
+		}, remaining: size.extractions}
+		for n := 5 + s.rng.Intn(3); n > 0; n-- {
+			sv.Deposits = append(sv.Deposits, models.SurveyDeposit{Symbol: string(wp.deposits[s.rng.Intn(len(wp.deposits))])})
+		}
+		s.surveys[sv.Signature] = sv
+		surveys = append(surveys, sv.Survey)
+	}
+	s.startCooldown(sh, surveyCooldown)
+	return http.StatusCreated, data(map[string]any{"cooldown": s.cooldown(sh), "surveys": surveys}), nil
+}
+
+func (s *Server) extractResources(c *call) (int, any, error) {
+	var body struct {
+		Survey *models.Survey `json:"survey"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireOrbit(sh); err != nil {
+		return 0, nil, err
+	}
+	wp := s.u.byWP[sh.Nav.WaypointSymbol]
+	if wp.Type != models.WaypointTypeAsteroidField || len(wp.deposits) == 0 {
+		return 0, nil, badRequest(models.ErrCodeExtractInvalidWaypoint, "Waypoint %s is not an asteroid field.", wp.Symbol)
+	}
+	if err := s.requireCooldown(sh); err != nil {
+		return 0, nil, err
+	}
+	strength := mountStrength(sh, mountMiningLaser)
+	if strength == 0 {
+		return 0, nil, badRequest(models.ErrCodeMissingMounts, "Ship %s has no mining laser mounted.", sh.Symbol)
+	}
+	free := sh.Cargo.Capacity - sh.Cargo.Units
+	if free == 0 {
+		return 0, nil, badRequest(models.ErrCodeCargoFull, "Ship %s has no free cargo space.", sh.Symbol)
+	}
+
+	symbol := string(wp.deposits[s.rng.Intn(len(wp.deposits))])
+	units := 1 + s.rng.Intn(strength/2+1)
+	if body.Survey != nil {
+		sv, ok := s.surveys[body.Survey.Signature]
+		switch {
+		case !ok || sv.Symbol != wp.Symbol:
+			return 0, nil, badRequest(models.ErrCodeSurveyVerification, "Survey %s is not valid at %s.", body.Survey.Signature, wp.Symbol)
+		case s.now().After(sv.Expiration):
+			return 0, nil, badRequest(models.ErrCodeSurveyExpired, "Survey %s has expired.", sv.Signature)
+		case sv.remaining == 0:
+			return 0, nil, badRequest(models.ErrCodeSurveyExhausted, "Survey %s has been exhausted.", sv.Signature)
+		}
+		sv.remaining--
+		symbol = sv.Deposits[s.rng.Intn(len(sv.Deposits))].Symbol
+		for _, sz := range surveySizes {
+			if sz.size == sv.Size {
+				units += sz.bonus
+			}
+		}
+	}
+	if units > free {
+		units = free
+	}
+	addCargo(sh, symbol, units)
+	s.startCooldown(sh, extractCooldown)
+
+	return http.StatusCreated, data(map[string]any{
+		"cooldown":   s.cooldown(sh),
+		"extraction": models.Extraction{ShipSymbol: sh.Symbol, Yield: models.ExtractionYield{Symbol: symbol, Units: units}},
+		"cargo":      sh.Cargo,
+	}), nil
+}
+
+func (s *Server) jettison(c *call) (int, any, error) {
+	var body struct {
+		Symbol string `json:"symbol"`
+		Units  int    `json:"units"`
+	}
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireArrived(sh); err != nil {
+		return 0, nil, err
+	}
+	if err := removeCargo(sh, body.Symbol, body.Units); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(map[string]any{"cargo": sh.Cargo}), nil
+}
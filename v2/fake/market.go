@@ -0,0 +1,249 @@
+package fake
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"spacetradersgo/v2/models"
+	"time"
+)
+
+// Markets keep a stock level per good between 0 (scarce) and 1
+// (abundant). Prices follow stock: a good costs its base price at half
+// stock, half again as much when scarce and half as much when abundant.
+// Selling to a market raises its stock and buying lowers it, so repeated
+// trades move the price against the trader. Left alone, stock drifts back
+// to the level the good's role implies: exports settle high and cheap,
+// imports low and dear, exchange goods in the middle.
+const (
+	exportStock   = 0.75
+	importStock   = 0.25
+	exchangeStock = 0.5
+	// stockDepth is how many full trade volumes move stock from empty to
+	// full, i.e. trading one volume shifts the supply level by one step.
+	stockDepth = 4
+	// spread is the gap between purchase and sell price, as a fraction of
+	// the mid price.
+	spread = 0.04
+)
+
+// MarketProfile describes what a market trades, for SetMarket.
+type MarketProfile struct {
+	Exports  []models.TradeSymbol
+	Imports  []models.TradeSymbol
+	Exchange []models.TradeSymbol
+	// TradeVolume caps the units of each good per transaction. Zero picks
+	// a volume at random, as generated markets do.
+	TradeVolume int
+	// BasePrices overrides the catalogue price of individual goods.
+	BasePrices map[models.TradeSymbol]int
+}
+
+type goodRole int
+
+const (
+	roleExport goodRole = iota
+	roleImport
+	roleExchange
+)
+
+func (r goodRole) target() float64 {
+	switch r {
+	case roleExport:
+		return exportStock
+	case roleImport:
+		return importStock
+	}
+	return exchangeStock
+}
+
+type market struct {
+	symbol       string
+	goods        []*marketGood
+	transactions []models.MarketTransaction
+	// updated is when stock last recovered; zero until first seen.
+	updated time.Time
+}
+
+type marketGood struct {
+	symbol models.TradeSymbol
+	role   goodRole
+	base   int
+	volume int
+	stock  float64
+}
+
+// newMarket sets up a market from p with stock at each good's resting
+// level. Every market trades fuel at exchange unless p already lists it.
+func newMarket(rng *rand.Rand, symbol string, p MarketProfile) *market {
+	m := &market{symbol: symbol}
+	seen := map[models.TradeSymbol]bool{}
+	add := func(role goodRole, goods []models.TradeSymbol) {
+		for _, g := range goods {
+			if seen[g] {
+				continue
+			}
+			seen[g] = true
+			base, ok := p.BasePrices[g]
+			if !ok {
+				base, ok = basePrices[g]
+			}
+			if !ok {
+				base = defaultBasePrice
+			}
+			volume := p.TradeVolume
+			if volume == 0 {
+				volume = []int{10, 20, 60, 100}[rng.Intn(4)]
+			}
+			m.goods = append(m.goods, &marketGood{symbol: g, role: role, base: base, volume: volume, stock: role.target()})
+		}
+	}
+	add(roleExport, p.Exports)
+	add(roleImport, p.Imports)
+	add(roleExchange, p.Exchange)
+	add(roleExchange, []models.TradeSymbol{models.TradeSymbolFuel})
+	return m
+}
+
+// defaultBasePrice prices goods missing from the catalogue.
+const defaultBasePrice = 50
+
+func (m *market) good(symbol string) *marketGood {
+	for _, g := range m.goods {
+		if string(g.symbol) == symbol {
+			return g
+		}
+	}
+	return nil
+}
+
+// recover moves every good's stock back towards its resting level. After
+// halfLife has passed, half the gap has closed.
+func (m *market) recover(now time.Time, halfLife time.Duration) {
+	if m.updated.IsZero() || halfLife <= 0 {
+		m.updated = now
+		return
+	}
+	elapsed := now.Sub(m.updated)
+	if elapsed <= 0 {
+		return
+	}
+	keep := math.Pow(0.5, float64(elapsed)/float64(halfLife))
+	for _, g := range m.goods {
+		target := g.role.target()
+		g.stock = target + (g.stock-target)*keep
+	}
+	m.updated = now
+}
+
+// shift moves stock by units traded into (positive) or out of (negative)
+// the market.
+func (g *marketGood) shift(units int) {
+	g.stock += float64(units) / float64(g.volume*stockDepth)
+	if g.stock < 0 {
+		g.stock = 0
+	}
+	if g.stock > 1 {
+		g.stock = 1
+	}
+}
+
+func (g *marketGood) supply() models.SupplyLevel {
+	// Round so a good that has nearly recovered reads as recovered.
+	stock := math.Round(g.stock*100) / 100
+	switch {
+	case stock < 0.25:
+		return models.SupplyLevelScarce
+	case stock < 0.5:
+		return models.SupplyLevelLimited
+	case stock < 0.75:
+		return models.SupplyLevelModerate
+	}
+	return models.SupplyLevelAbundant
+}
+
+// prices returns what a ship pays to buy one unit and receives for selling
+// one at the current stock level.
+func (g *marketGood) prices() (purchase, sell int) {
+	mid := float64(g.base) * (1.5 - g.stock)
+	purchase = int(math.Round(mid * (1 + spread)))
+	sell = int(math.Round(mid * (1 - spread)))
+	if sell < 1 {
+		sell = 1
+	}
+	if purchase <= sell {
+		purchase = sell + 1
+	}
+	return purchase, sell
+}
+
+func (g *marketGood) tradeGood() models.MarketTradeGood {
+	purchase, sell := g.prices()
+	return models.MarketTradeGood{Symbol: string(g.symbol), TradeVolume: g.volume, Supply: g.supply(),
+		PurchasePrice: purchase, SellPrice: sell}
+}
+
+// view renders the market the way the API does: trade goods and recent
+// transactions only when the agent has a ship present.
+func (m *market) view(present bool) models.Market {
+	v := models.Market{Symbol: m.symbol, Exports: []models.TradeGood{}, Imports: []models.TradeGood{}, Exchange: []models.TradeGood{}}
+	for _, g := range m.goods {
+		switch g.role {
+		case roleExport:
+			v.Exports = append(v.Exports, tradeGood(g.symbol))
+		case roleImport:
+			v.Imports = append(v.Imports, tradeGood(g.symbol))
+		case roleExchange:
+			v.Exchange = append(v.Exchange, tradeGood(g.symbol))
+		}
+		if present {
+			v.TradeGoods = append(v.TradeGoods, g.tradeGood())
+		}
+	}
+	if present {
+		v.Transactions = append([]models.MarketTransaction{}, m.transactions...)
+	}
+	return v
+}
+
+// WithMarketRecovery sets how quickly markets recover from trades: after
+// halfLife, stock has moved half way back to its resting level. Zero
+// freezes markets wherever trading leaves them.
+func WithMarketRecovery(halfLife time.Duration) serverOpts {
+	return func(s *Server) {
+		s.recovery = halfLife
+	}
+}
+
+// SetMarket replaces the market at waypoint with one built from p, adding
+// the MARKETPLACE trait if the waypoint had no market. Goods start at
+// their resting stock, so scenarios are reproducible whatever trading came
+// before. Random trade volumes come from the seed and the waypoint alone,
+// leaving every other outcome of the seed as it would be without the call.
+func (s *Server) SetMarket(waypoint string, p MarketProfile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wp, ok := s.u.byWP[waypoint]
+	if !ok {
+		return fmt.Errorf("fake: waypoint %s does not exist", waypoint)
+	}
+	if !wp.hasTrait("MARKETPLACE") {
+		wp.Traits = append(wp.Traits, trait("MARKETPLACE"))
+	}
+	h := fnv.New64a()
+	h.Write([]byte(wp.Symbol))
+	rng := rand.New(rand.NewSource(s.seed ^ int64(h.Sum64())))
+	wp.market = newMarket(rng, wp.Symbol, p)
+	wp.market.updated = s.now()
+	return nil
+}
+
+// marketAt returns the market at wp with its stock brought up to date, or
+// nil if there is none.
+func (s *Server) marketAt(wp *waypoint) *market {
+	if wp.market != nil {
+		wp.market.recover(s.now(), s.recovery)
+	}
+	return wp.market
+}
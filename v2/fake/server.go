@@ -0,0 +1,402 @@
+// Package fake runs an in-process SpaceTraders server for tests that must
+// not touch the real API.
+//
+// The server keeps a small universe in memory: factions, systems with
+// waypoints, markets, shipyards and jump gates, plus whatever agents,
+// ships and contracts the test creates. The universe is generated from a
+// seed, so the same seed always produces the same symbols and prices.
+// Point any client at it with the *http.Client from Client:
+//
+//	srv := fake.NewServer(fake.WithSeed(42))
+//	defer srv.Close()
+//
+//	sdk := v2.NewSpaceTradersClient(v2.WithHTTPClient(srv.Client()))
+//	resp, err := sdk.Agents.NewAgent(ctx, &agents.NewAgentRequest{Symbol: "TESTER", Faction: "COSMIC"})
+//
+// Ships take time to travel, burn fuel and go on cooldown after extracting
+// or jumping, all measured against the server's Clock; see WithClock.
+// Market prices respond to trades and recover as time passes; SetMarket
+// sets up a market for a specific scenario.
+//
+// Errors come back in the API's shape with the game's error codes, so code
+// that inspects *models.APIError behaves as it would against the real API.
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"spacetradersgo/v2/models"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Server struct {
+	// URL is the base URL of the underlying httptest server.
+	URL string
+
+	srv *httptest.Server
+
+	seed     int64
+	systems  int
+	clock    Clock
+	recovery time.Duration
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	u        *universe
+	agents   map[string]*agent // by token
+	bySymbol map[string]*agent
+	surveys  map[string]*survey
+	started  time.Time
+}
+
+type serverOpts func(*Server)
+
+var (
+	defaultOpts = []serverOpts{
+		WithSeed(1),
+		WithSystems(12),
+		WithClock(realClock{}),
+		WithMarketRecovery(30 * time.Minute),
+	}
+)
+
+// WithSeed sets the seed the universe and every random outcome, such as
+// extraction yields and contract terms, are drawn from.
+func WithSeed(seed int64) serverOpts {
+	return func(s *Server) {
+		s.seed = seed
+	}
+}
+
+// WithSystems sets how many systems the universe has.
+func WithSystems(n int) serverOpts {
+	return func(s *Server) {
+		s.systems = n
+	}
+}
+
+// NewServer generates a universe and starts serving it. Call Close when
+// done.
+func NewServer(opts ...serverOpts) *Server {
+	s := &Server{
+		agents:   map[string]*agent{},
+		bySymbol: map[string]*agent{},
+		surveys:  map[string]*survey{},
+	}
+
+	opts = append(defaultOpts, opts...)
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.rng = rand.New(rand.NewSource(s.seed))
+	s.started = s.now()
+	s.u = generate(s.rng, s.systems)
+
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns an *http.Client that sends every request, whatever its
+// host, to this server. Pass it to the SDK's WithHTTPClient options.
+func (s *Server) Client() *http.Client {
+	return &http.Client{Transport: &rewrite{base: s.srv.Client().Transport, host: strings.TrimPrefix(s.URL, "http://")}}
+}
+
+type rewrite struct {
+	base http.RoundTripper
+	host string
+}
+
+func (t *rewrite) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = t.host
+	req.Host = t.host
+	return t.base.RoundTrip(req)
+}
+
+func (s *Server) now() time.Time {
+	return s.clock.Now().UTC()
+}
+
+// apiError is what handlers return to fail a request. It is written out in
+// the same envelope the real API uses.
+type apiError struct {
+	status  int
+	code    int
+	message string
+	data    any
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errorf(status, code int, format string, args ...any) *apiError {
+	return &apiError{status: status, code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...any) *apiError {
+	return errorf(http.StatusNotFound, models.ErrCodeNotFound, format, args...)
+}
+
+func badRequest(code int, format string, args ...any) *apiError {
+	return errorf(http.StatusBadRequest, code, format, args...)
+}
+
+func conflict(code int, format string, args ...any) *apiError {
+	return errorf(http.StatusConflict, code, format, args...)
+}
+
+// call carries one request through its handler.
+type call struct {
+	r      *http.Request
+	params map[string]string
+	agent  *agent
+}
+
+func (c *call) decode(v any) *apiError {
+	if c.r.Body == nil {
+		return nil
+	}
+	dec := json.NewDecoder(c.r.Body)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return errorf(http.StatusUnprocessableEntity, models.ErrCodeValidation, "Invalid request body: %v", err)
+	}
+	return nil
+}
+
+// page reads the page and limit query parameters the way the API does:
+// page defaults to 1 and limit to 10, and limit may not exceed 20.
+func (c *call) page() (page, limit int, err *apiError) {
+	page, limit = 1, 10
+	q := c.r.URL.Query()
+	if v := q.Get("page"); v != "" {
+		n, convErr := strconv.Atoi(v)
+		if convErr != nil || n < 1 {
+			return 0, 0, errorf(http.StatusUnprocessableEntity, models.ErrCodeValidation, "page must be a positive integer")
+		}
+		page = n
+	}
+	if v := q.Get("limit"); v != "" {
+		n, convErr := strconv.Atoi(v)
+		if convErr != nil || n < 1 || n > 20 {
+			return 0, 0, errorf(http.StatusUnprocessableEntity, models.ErrCodeValidation, "limit must be between 1 and 20")
+		}
+		limit = n
+	}
+	return page, limit, nil
+}
+
+// paged is a response body carrying a page of results.
+type paged struct {
+	Data any         `json:"data"`
+	Meta models.Meta `json:"meta"`
+}
+
+// paginate slices n items into the requested page and returns the bounds.
+func paginate(n, page, limit int) (lo, hi int, meta models.Meta) {
+	lo = (page - 1) * limit
+	if lo > n {
+		lo = n
+	}
+	hi = lo + limit
+	if hi > n {
+		hi = n
+	}
+	return lo, hi, models.Meta{Total: n, Page: page, Limit: limit}
+}
+
+// handler answers a call with a status code and a body. A nil body writes
+// no content; an error is written in the API's error envelope.
+type handler func(s *Server, c *call) (int, any, error)
+
+type route struct {
+	method string
+	// segments of the path below /v2; a segment starting with "{" matches
+	// anything and is captured under its name.
+	segments []string
+	public   bool
+	handle   handler
+}
+
+func r(method, pattern string, public bool, h handler) route {
+	return route{method: method, segments: strings.Split(strings.Trim(pattern, "/"), "/"), public: public, handle: h}
+}
+
+var routes = []route{
+	r(http.MethodGet, "/", true, (*Server).getStatus),
+	r(http.MethodPost, "/register", true, (*Server).register),
+	r(http.MethodGet, "/my/agent", false, (*Server).getAgent),
+	r(http.MethodGet, "/factions", false, (*Server).listFactions),
+	r(http.MethodGet, "/factions/{faction}", false, (*Server).getFaction),
+
+	r(http.MethodGet, "/my/contracts", false, (*Server).listContracts),
+	r(http.MethodGet, "/my/contracts/{contract}", false, (*Server).getContract),
+	r(http.MethodPost, "/my/contracts/{contract}/accept", false, (*Server).acceptContract),
+	r(http.MethodPost, "/my/contracts/{contract}/deliver", false, (*Server).deliverContract),
+	r(http.MethodPost, "/my/contracts/{contract}/fulfill", false, (*Server).fulfillContract),
+
+	r(http.MethodGet, "/my/ships", false, (*Server).listShips),
+	r(http.MethodPost, "/my/ships", false, (*Server).purchaseShip),
+	r(http.MethodGet, "/my/ships/{ship}", false, (*Server).getShip),
+	r(http.MethodGet, "/my/ships/{ship}/cargo", false, (*Server).getShipCargo),
+	r(http.MethodPost, "/my/ships/{ship}/orbit", false, (*Server).orbitShip),
+	r(http.MethodPost, "/my/ships/{ship}/dock", false, (*Server).dockShip),
+	r(http.MethodPost, "/my/ships/{ship}/chart", false, (*Server).createChart),
+	r(http.MethodGet, "/my/ships/{ship}/cooldown", false, (*Server).getShipCooldown),
+	r(http.MethodPost, "/my/ships/{ship}/survey", false, (*Server).createSurvey),
+	r(http.MethodPost, "/my/ships/{ship}/extract", false, (*Server).extractResources),
+	r(http.MethodPost, "/my/ships/{ship}/jettison", false, (*Server).jettison),
+	r(http.MethodPost, "/my/ships/{ship}/jump", false, (*Server).jumpShip),
+	r(http.MethodPost, "/my/ships/{ship}/navigate", false, (*Server).navigateShip),
+	r(http.MethodGet, "/my/ships/{ship}/nav", false, (*Server).getShipNav),
+	r(http.MethodPatch, "/my/ships/{ship}/nav", false, (*Server).patchShipNav),
+	r(http.MethodPost, "/my/ships/{ship}/warp", false, (*Server).warpShip),
+	r(http.MethodPost, "/my/ships/{ship}/sell", false, (*Server).sellCargo),
+	r(http.MethodPost, "/my/ships/{ship}/purchase", false, (*Server).purchaseCargo),
+	r(http.MethodPost, "/my/ships/{ship}/refuel", false, (*Server).refuelShip),
+	r(http.MethodPost, "/my/ships/{ship}/negotiate/contract", false, (*Server).negotiateContract),
+
+	r(http.MethodGet, "/systems", false, (*Server).listSystems),
+	r(http.MethodGet, "/systems/{system}", false, (*Server).getSystem),
+	r(http.MethodGet, "/systems/{system}/waypoints", false, (*Server).listWaypoints),
+	r(http.MethodGet, "/systems/{system}/waypoints/{waypoint}", false, (*Server).getWaypoint),
+	r(http.MethodGet, "/systems/{system}/waypoints/{waypoint}/market", false, (*Server).getMarket),
+	r(http.MethodGet, "/systems/{system}/waypoints/{waypoint}/shipyard", false, (*Server).getShipyard),
+	r(http.MethodGet, "/systems/{system}/waypoints/{waypoint}/jump-gate", false, (*Server).getJumpGate),
+}
+
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") {
+			params[strings.Trim(seg, "{}")] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// ServeHTTP routes a request under /v2 to its handler. Handlers run one at
+// a time, so the universe needs no finer-grained locking.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.TrimPrefix(req.URL.Path, "/v2")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var (
+		found   bool
+		matched *route
+		params  map[string]string
+	)
+	for i := range routes {
+		p, ok := routes[i].match(segments)
+		if !ok {
+			continue
+		}
+		found = true
+		if routes[i].method == req.Method {
+			matched, params = &routes[i], p
+			break
+		}
+	}
+	if matched == nil {
+		if found {
+			writeError(w, errorf(http.StatusMethodNotAllowed, http.StatusMethodNotAllowed, "Method %s is not allowed on %s", req.Method, path))
+			return
+		}
+		writeError(w, notFound("Route %s %s not found", req.Method, path))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := &call{r: req, params: params}
+	if !matched.public {
+		a, err := s.authenticate(req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		c.agent = a
+		s.settle(a)
+	}
+
+	status, body, err := matched.handle(s, c)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (s *Server) authenticate(req *http.Request) (*agent, *apiError) {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		return nil, errorf(http.StatusUnauthorized, models.ErrCodeUnauthorized, "Missing bearer token. Register an agent to receive one.")
+	}
+	a, ok := s.agents[token]
+	if !ok {
+		return nil, errorf(http.StatusUnauthorized, models.ErrCodeAgentNotExists, "Agent for this token does not exist.")
+	}
+	return a, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*apiError)
+	if !ok {
+		e = errorf(http.StatusInternalServerError, http.StatusInternalServerError, "%v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	body := map[string]any{"message": e.message, "code": e.code}
+	if e.data != nil {
+		body["data"] = e.data
+	}
+	json.NewEncoder(w).Encode(map[string]any{"error": body})
+}
+
+// data wraps v in the envelope every payload is sent in.
+func data(v any) map[string]any {
+	return map[string]any{"data": v}
+}
+
+// sortedKeys returns the keys of m in order, for deterministic listings.
+func sortedKeys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,106 @@
+package fake
+
+import (
+	"net/http"
+	"spacetradersgo/v2/models"
+)
+
+func (s *Server) system(c *call) (*system, *apiError) {
+	sys, ok := s.u.bySystem[c.params["system"]]
+	if !ok {
+		return nil, notFound("System %s not found.", c.params["system"])
+	}
+	return sys, nil
+}
+
+func (s *Server) waypoint(c *call) (*waypoint, *apiError) {
+	sys, err := s.system(c)
+	if err != nil {
+		return nil, err
+	}
+	wp, ok := s.u.byWP[c.params["waypoint"]]
+	if !ok || wp.SystemSymbol != sys.Symbol {
+		return nil, notFound("Waypoint %s not found in %s.", c.params["waypoint"], sys.Symbol)
+	}
+	return wp, nil
+}
+
+func (s *Server) listSystems(c *call) (int, any, error) {
+	page, limit, err := c.page()
+	if err != nil {
+		return 0, nil, err
+	}
+	lo, hi, meta := paginate(len(s.u.systems), page, limit)
+	systems := make([]models.System, 0, hi-lo)
+	for _, sys := range s.u.systems[lo:hi] {
+		systems = append(systems, sys.System)
+	}
+	return http.StatusOK, paged{Data: systems, Meta: meta}, nil
+}
+
+func (s *Server) getSystem(c *call) (int, any, error) {
+	sys, err := s.system(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(sys.System), nil
+}
+
+func (s *Server) listWaypoints(c *call) (int, any, error) {
+	sys, err := s.system(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	page, limit, err := c.page()
+	if err != nil {
+		return 0, nil, err
+	}
+	lo, hi, meta := paginate(len(sys.waypoints), page, limit)
+	waypoints := make([]models.Waypoint, 0, hi-lo)
+	for _, wp := range sys.waypoints[lo:hi] {
+		waypoints = append(waypoints, wp.Waypoint)
+	}
+	return http.StatusOK, paged{Data: waypoints, Meta: meta}, nil
+}
+
+func (s *Server) getWaypoint(c *call) (int, any, error) {
+	wp, err := s.waypoint(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, data(wp.Waypoint), nil
+}
+
+func (s *Server) getMarket(c *call) (int, any, error) {
+	wp, err := s.waypoint(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	m := s.marketAt(wp)
+	if m == nil {
+		return 0, nil, notFound("Market not found at %s.", wp.Symbol)
+	}
+	return http.StatusOK, data(m.view(s.present(c.agent, wp.Symbol))), nil
+}
+
+func (s *Server) getShipyard(c *call) (int, any, error) {
+	wp, err := s.waypoint(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if wp.shipyard == nil {
+		return 0, nil, notFound("Shipyard not found at %s.", wp.Symbol)
+	}
+	return http.StatusOK, data(wp.shipyard.view(s.present(c.agent, wp.Symbol))), nil
+}
+
+func (s *Server) getJumpGate(c *call) (int, any, error) {
+	wp, err := s.waypoint(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if wp.jumpGate == nil {
+		return 0, nil, notFound("Jump gate not found at %s.", wp.Symbol)
+	}
+	return http.StatusOK, data(wp.jumpGate), nil
+}
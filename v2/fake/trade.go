@@ -0,0 +1,146 @@
+package fake
+
+import (
+	"net/http"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+)
+
+// dockedMarket returns the market where sh is docked.
+func (s *Server) dockedMarket(sh *ship) (*market, *apiError) {
+	if err := s.requireDocked(sh); err != nil {
+		return nil, err
+	}
+	wp := s.u.byWP[sh.Nav.WaypointSymbol]
+	m := s.marketAt(wp)
+	if m == nil {
+		return nil, badRequest(models.ErrCodeMarketNotFound, "Waypoint %s has no market.", wp.Symbol)
+	}
+	return m, nil
+}
+
+type tradeBody struct {
+	Symbol string `json:"symbol"`
+	Units  int    `json:"units"`
+}
+
+func (s *Server) sellCargo(c *call) (int, any, error) {
+	var body tradeBody
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	m, err := s.dockedMarket(sh)
+	if err != nil {
+		return 0, nil, err
+	}
+	g := m.good(body.Symbol)
+	if g == nil {
+		return 0, nil, badRequest(models.ErrCodeTradeNotSold, "Market %s does not trade %s.", m.symbol, body.Symbol)
+	}
+	if body.Units > g.volume {
+		return 0, nil, badRequest(models.ErrCodeTradeUnitLimit, "Market %s trades at most %d units of %s at a time.", m.symbol, g.volume, body.Symbol)
+	}
+	if err := removeCargo(sh, body.Symbol, body.Units); err != nil {
+		return 0, nil, err
+	}
+
+	tx := s.trade(m, g, sh, models.MarketTransactionTypeSell, body.Units)
+	c.agent.Credits += int64(tx.TotalPrice)
+	return http.StatusCreated, data(map[string]any{"agent": c.agent.Agent, "cargo": sh.Cargo, "transaction": tx}), nil
+}
+
+func (s *Server) purchaseCargo(c *call) (int, any, error) {
+	var body tradeBody
+	if err := c.decode(&body); err != nil {
+		return 0, nil, err
+	}
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	m, err := s.dockedMarket(sh)
+	if err != nil {
+		return 0, nil, err
+	}
+	g := m.good(body.Symbol)
+	if g == nil {
+		return 0, nil, badRequest(models.ErrCodeTradeNoPurchase, "Market %s does not sell %s.", m.symbol, body.Symbol)
+	}
+	if body.Units < 1 {
+		return 0, nil, errorf(http.StatusUnprocessableEntity, models.ErrCodeValidation, "units must be at least 1")
+	}
+	if body.Units > g.volume {
+		return 0, nil, badRequest(models.ErrCodeTradeUnitLimit, "Market %s trades at most %d units of %s at a time.", m.symbol, g.volume, body.Symbol)
+	}
+	price, _ := g.prices()
+	if cost := int64(price * body.Units); c.agent.Credits < cost {
+		return 0, nil, badRequest(models.ErrCodeInsufficientCredits, "Purchase costs %d credits, agent has %d.", cost, c.agent.Credits)
+	}
+	if err := addCargo(sh, body.Symbol, body.Units); err != nil {
+		return 0, nil, err
+	}
+
+	tx := s.trade(m, g, sh, models.MarketTransactionTypePurchase, body.Units)
+	c.agent.Credits -= int64(tx.TotalPrice)
+	return http.StatusCreated, data(map[string]any{"agent": c.agent.Agent, "cargo": sh.Cargo, "transaction": tx}), nil
+}
+
+// trade prices a transaction at the market's current price, records it and
+// moves the market's stock by the units traded.
+func (s *Server) trade(m *market, g *marketGood, sh *ship, kind models.MarketTransactionType, units int) models.MarketTransaction {
+	purchase, sell := g.prices()
+	price, moved := sell, units
+	if kind == models.MarketTransactionTypePurchase {
+		price, moved = purchase, -units
+	}
+	g.shift(moved)
+	tx := models.MarketTransaction{
+		WaypointSymbol: m.symbol,
+		ShipSymbol:     sh.Symbol,
+		TradeSymbol:    string(g.symbol),
+		Type:           kind,
+		Units:          units,
+		PricePerUnit:   price,
+		TotalPrice:     price * units,
+		Timestamp:      s.now(),
+	}
+	m.transactions = append(m.transactions, tx)
+	return tx
+}
+
+func (s *Server) refuelShip(c *call) (int, any, error) {
+	sh, err := s.agentShip(c)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.requireArrived(sh); err != nil {
+		return 0, nil, err
+	}
+	if sh.Nav.Status != models.ShipNavStatusDocked {
+		return 0, nil, badRequest(models.ErrCodeRefuelDocked, "Ship %s must be docked to refuel.", sh.Symbol)
+	}
+	wp := s.u.byWP[sh.Nav.WaypointSymbol]
+	m := s.marketAt(wp)
+	var g *marketGood
+	if m != nil {
+		g = m.good(string(models.TradeSymbolFuel))
+	}
+	if g == nil {
+		return 0, nil, badRequest(models.ErrCodeRefuelInvalidWaypoint, "Waypoint %s does not sell fuel.", wp.Symbol)
+	}
+
+	needed := sh.Fuel.Capacity - sh.Fuel.Current
+	units := (needed + navigation.FuelUnit - 1) / navigation.FuelUnit
+	price, _ := g.prices()
+	if cost := int64(price * units); c.agent.Credits < cost {
+		return 0, nil, badRequest(models.ErrCodeInsufficientCredits, "Refuelling costs %d credits, agent has %d.", cost, c.agent.Credits)
+	}
+	tx := s.trade(m, g, sh, models.MarketTransactionTypePurchase, units)
+	c.agent.Credits -= int64(tx.TotalPrice)
+	sh.Fuel.Current = sh.Fuel.Capacity
+	return http.StatusOK, data(map[string]any{"agent": c.agent.Agent, "fuel": sh.Fuel, "transaction": tx}), nil
+}
@@ -0,0 +1,60 @@
+package fake
+
+import (
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+	"time"
+)
+
+// Flights and jumps follow the game's formulas, as implemented by package
+// navigation.
+const (
+	extractCooldown = 70 * time.Second
+	surveyCooldown  = 70 * time.Second
+)
+
+// fly burns the fuel for a flight of dist and sends the ship to dest. Ships
+// without a fuel tank, such as probes, fly for free.
+func (s *Server) fly(sh *ship, dest *waypoint, dist int) *apiError {
+	cost := navigation.FuelCost(float64(dist), sh.Nav.FlightMode)
+	if sh.Fuel.Capacity > 0 {
+		if cost > sh.Fuel.Current {
+			err := badRequest(models.ErrCodeNavigateInsufficientFuel, "Ship %s needs %d fuel to reach %s in %s mode, it has %d.",
+				sh.Symbol, cost, dest.Symbol, sh.Nav.FlightMode, sh.Fuel.Current)
+			err.data = map[string]int{"fuelRequired": cost, "fuelAvailable": sh.Fuel.Current}
+			return err
+		}
+		sh.Fuel.Current -= cost
+		sh.Fuel.Consumed = &models.ShipFuelConsumed{Amount: cost, Timestamp: s.now()}
+	}
+	s.moveTo(sh, dest, navigation.TravelTime(float64(dist), sh.Engine.Speed, sh.Nav.FlightMode))
+	return nil
+}
+
+// settle lands every ship of a whose arrival time has passed. It runs
+// before each authenticated request, so ships arrive lazily as the clock
+// moves on.
+func (s *Server) settle(a *agent) {
+	now := s.now()
+	for _, sh := range a.ships {
+		if sh.Nav.Status == models.ShipNavStatusInTransit && !now.Before(sh.Nav.Route.Arrival) {
+			sh.Nav.Status = models.ShipNavStatusInOrbit
+		}
+	}
+}
+
+func (s *Server) startCooldown(sh *ship, d time.Duration) {
+	sh.cooldownTotal = int(d / time.Second)
+	sh.cooldownUntil = s.now().Add(d)
+}
+
+// requireCooldown fails with the ship's cooldown attached while it has one.
+func (s *Server) requireCooldown(sh *ship) *apiError {
+	cd := s.cooldown(sh)
+	if cd.RemainingSeconds == 0 {
+		return nil
+	}
+	err := conflict(models.ErrCodeCooldownConflict, "Ship %s is on cooldown for %d more seconds.", sh.Symbol, cd.RemainingSeconds)
+	err.data = map[string]any{"cooldown": cd}
+	return err
+}
@@ -0,0 +1,329 @@
+package fake
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"spacetradersgo/v2/models"
+	"time"
+)
+
+// universe is the static part of the game: everything that exists before
+// the first agent registers.
+type universe struct {
+	factions []models.Faction
+	systems  []*system
+	bySystem map[string]*system
+	byWP     map[string]*waypoint
+}
+
+type system struct {
+	models.System
+	waypoints []*waypoint
+	gate      *waypoint
+}
+
+type waypoint struct {
+	models.Waypoint
+	// deposits are the ores an asteroid field yields.
+	deposits []models.TradeSymbol
+	market   *market
+	shipyard *shipyard
+	jumpGate *models.JumpGate
+}
+
+func (wp *waypoint) routeWaypoint() models.ShipNavRouteWaypoint {
+	return models.ShipNavRouteWaypoint{Symbol: wp.Symbol, Type: wp.Type, SystemSymbol: wp.SystemSymbol, X: wp.X, Y: wp.Y}
+}
+
+func (wp *waypoint) hasTrait(symbol string) bool {
+	for _, t := range wp.Traits {
+		if t.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+type shipyard struct {
+	symbol       string
+	types        []models.ShipType
+	transactions []models.ShipyardTransaction
+}
+
+func (y *shipyard) view(present bool) models.Shipyard {
+	v := models.Shipyard{Symbol: y.symbol}
+	for _, t := range y.types {
+		v.ShipTypes = append(v.ShipTypes, models.ShipyardShipType{Type: t})
+		if present {
+			v.Ships = append(v.Ships, shipyardShip(t))
+		}
+	}
+	if present {
+		v.Transactions = append([]models.ShipyardTransaction{}, y.transactions...)
+	}
+	return v
+}
+
+func (y *shipyard) sells(t models.ShipType) bool {
+	for _, s := range y.types {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+var factionNames = []struct {
+	symbol, name, description string
+}{
+	{"COSMIC", "Cosmic Engineers", "A group of innovative and daring engineers pushing the boundaries of space travel."},
+	{"VOID", "Voidfarers", "A group of nomadic explorers who roam the voids between systems."},
+	{"GALACTIC", "Galactic Alliance", "A coalition of factions sharing resources and defending their territory."},
+	{"QUANTUM", "Quantum Federation", "A federation of researchers unlocking the secrets of quantum mechanics."},
+	{"DOMINION", "Stellar Dominion", "An expansionist empire seeking control over the galaxy."},
+}
+
+const (
+	sectorSymbol = "X1"
+	// universeRadius bounds system coordinates; systemRadius bounds
+	// waypoint coordinates within a system.
+	universeRadius = 2000
+	systemRadius   = 80
+	jumpRange      = 1500
+)
+
+var systemTypes = []models.SystemType{
+	models.SystemTypeRedStar, models.SystemTypeOrangeStar, models.SystemTypeBlueStar,
+	models.SystemTypeYoungStar, models.SystemTypeWhiteDwarf, models.SystemTypeNeutronStar,
+}
+
+// generate builds a universe of n systems from rng. The first systems are
+// the factions' home systems.
+func generate(rng *rand.Rand, n int) *universe {
+	if n < len(factionNames) {
+		n = len(factionNames)
+	}
+	u := &universe{bySystem: map[string]*system{}, byWP: map[string]*waypoint{}}
+
+	for len(u.systems) < n {
+		symbol := fmt.Sprintf("%s-%c%c%d", sectorSymbol, 'A'+rng.Intn(26), 'A'+rng.Intn(26), 10+rng.Intn(90))
+		if _, ok := u.bySystem[symbol]; ok {
+			continue
+		}
+		sys := &system{System: models.System{
+			Symbol:       symbol,
+			SectorSymbol: sectorSymbol,
+			Type:         systemTypes[rng.Intn(len(systemTypes))],
+			X:            rng.Intn(2*universeRadius+1) - universeRadius,
+			Y:            rng.Intn(2*universeRadius+1) - universeRadius,
+			Waypoints:    []models.SystemWaypoint{},
+			Factions:     []models.SystemFaction{},
+		}}
+		u.systems = append(u.systems, sys)
+		u.bySystem[symbol] = sys
+	}
+
+	for i, sys := range u.systems {
+		faction := ""
+		if i < len(factionNames) {
+			faction = factionNames[i].symbol
+			sys.Factions = append(sys.Factions, models.SystemFaction{Symbol: faction})
+		}
+		u.populate(rng, sys, faction)
+	}
+
+	for i, f := range factionNames {
+		hq := u.systems[i].waypoints[1]
+		u.factions = append(u.factions, models.Faction{
+			Symbol:       f.symbol,
+			Name:         f.name,
+			Description:  f.description,
+			Headquarters: hq.Symbol,
+			Traits:       []models.FactionTrait{{Symbol: "INNOVATIVE", Name: "Innovative", Description: "Willing to try new things."}},
+			IsRecruiting: true,
+		})
+	}
+
+	u.connectGates()
+	return u
+}
+
+// populate gives sys its waypoints. Every system has a planet with an
+// orbital station and a moon, an asteroid field, a gas giant and a jump
+// gate, plus a few extra bodies.
+func (u *universe) populate(rng *rand.Rand, sys *system, faction string) {
+	add := func(t models.WaypointType, x, y int, traits ...string) *waypoint {
+		wp := &waypoint{Waypoint: models.Waypoint{
+			Symbol:       fmt.Sprintf("%s-%c%d", sys.Symbol, 'A'+len(sys.waypoints), 10+rng.Intn(90)),
+			Type:         t,
+			SystemSymbol: sys.Symbol,
+			X:            x,
+			Y:            y,
+			Orbitals:     []models.WaypointOrbital{},
+			Traits:       []models.WaypointTrait{},
+		}}
+		for _, t := range traits {
+			wp.Traits = append(wp.Traits, trait(t))
+		}
+		if faction != "" {
+			wp.Faction = &models.WaypointFaction{Symbol: faction}
+			wp.Chart = &models.Chart{WaypointSymbol: wp.Symbol, SubmittedBy: faction, SubmittedOn: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+		} else {
+			wp.Traits = append(wp.Traits, trait("UNCHARTED"))
+		}
+		sys.waypoints = append(sys.waypoints, wp)
+		sys.Waypoints = append(sys.Waypoints, models.SystemWaypoint{Symbol: wp.Symbol, Type: wp.Type, X: wp.X, Y: wp.Y})
+		u.byWP[wp.Symbol] = wp
+		return wp
+	}
+	coord := func() (int, int) {
+		return rng.Intn(2*systemRadius+1) - systemRadius, rng.Intn(2*systemRadius+1) - systemRadius
+	}
+	orbit := func(parent, child *waypoint) {
+		parent.Orbitals = append(parent.Orbitals, models.WaypointOrbital{Symbol: child.Symbol})
+	}
+
+	x, y := coord()
+	planet := add(models.WaypointTypePlanet, x, y, "MARKETPLACE", "TEMPERATE")
+	station := add(models.WaypointTypeOrbitalStation, x, y, "MARKETPLACE", "SHIPYARD")
+	moon := add(models.WaypointTypeMoon, x, y, "MARKETPLACE", "BARREN")
+	orbit(planet, station)
+	orbit(planet, moon)
+
+	x, y = coord()
+	field := add(models.WaypointTypeAsteroidField, x, y, "MINERAL_DEPOSITS")
+	for _, i := range rng.Perm(len(ores))[:4] {
+		field.deposits = append(field.deposits, ores[i])
+	}
+
+	x, y = coord()
+	giant := add(models.WaypointTypeGasGiant, x, y, "MARKETPLACE", "VIBRANT_AURORAS")
+
+	x, y = coord()
+	sys.gate = add(models.WaypointTypeJumpGate, x, y)
+
+	for i := rng.Intn(3); i > 0; i-- {
+		x, y = coord()
+		switch rng.Intn(3) {
+		case 0:
+			add(models.WaypointTypePlanet, x, y, "BARREN")
+		case 1:
+			extra := add(models.WaypointTypeAsteroidField, x, y, "MINERAL_DEPOSITS")
+			for _, i := range rng.Perm(len(ores))[:3] {
+				extra.deposits = append(extra.deposits, ores[i])
+			}
+		default:
+			add(models.WaypointTypeDebrisField, x, y)
+		}
+	}
+
+	pick := func(pool []models.TradeSymbol, n int) []models.TradeSymbol {
+		var out []models.TradeSymbol
+		for _, i := range rng.Perm(len(pool))[:n] {
+			out = append(out, pool[i])
+		}
+		return out
+	}
+	planet.market = newMarket(rng, planet.Symbol, MarketProfile{
+		Exports: pick(refined, 2),
+		Imports: append(field.deposits[:2:2], pick(ores, 1)...),
+	})
+	station.market = newMarket(rng, station.Symbol, MarketProfile{
+		Exports:  pick(manufactured, 2),
+		Imports:  pick(refined, 3),
+		Exchange: []models.TradeSymbol{field.deposits[2]},
+	})
+	moon.market = newMarket(rng, moon.Symbol, MarketProfile{
+		Exports: pick(manufactured, 1),
+		Imports: append(pick(refined, 1), field.deposits[3]),
+	})
+	giant.market = newMarket(rng, giant.Symbol, MarketProfile{
+		Exports: pick(gases, 2),
+		Imports: pick(manufactured, 1),
+	})
+
+	station.shipyard = &shipyard{symbol: station.Symbol, types: []models.ShipType{
+		models.ShipTypeShipProbe, models.ShipTypeShipMiningDrone, models.ShipTypeShipOreHound, models.ShipTypeShipLightHauler,
+	}}
+	if faction != "" {
+		station.shipyard.types = append(station.shipyard.types, models.ShipTypeShipExplorer)
+	}
+}
+
+// connectGates links every jump gate to the gates of systems within
+// jumpRange, and to at least its two nearest neighbours so no system is
+// stranded.
+func (u *universe) connectGates() {
+	links := map[*system]map[*system]bool{}
+	link := func(a, b *system) {
+		if links[a] == nil {
+			links[a] = map[*system]bool{}
+		}
+		if links[b] == nil {
+			links[b] = map[*system]bool{}
+		}
+		links[a][b] = true
+		links[b][a] = true
+	}
+	for _, a := range u.systems {
+		var first, second *system
+		for _, b := range u.systems {
+			if a == b {
+				continue
+			}
+			d := systemDistance(a, b)
+			if d <= jumpRange {
+				link(a, b)
+			}
+			switch {
+			case first == nil || d < systemDistance(a, first):
+				first, second = b, first
+			case second == nil || d < systemDistance(a, second):
+				second = b
+			}
+		}
+		if first != nil {
+			link(a, first)
+		}
+		if second != nil {
+			link(a, second)
+		}
+	}
+
+	for _, a := range u.systems {
+		gate := &models.JumpGate{JumpRange: jumpRange, ConnectedSystems: []models.ConnectedSystem{}}
+		if len(a.Factions) > 0 {
+			gate.FactionSymbol = a.Factions[0].Symbol
+		}
+		for _, b := range u.systems {
+			if !links[a][b] {
+				continue
+			}
+			cs := models.ConnectedSystem{Symbol: b.Symbol, SectorSymbol: b.SectorSymbol, Type: b.Type,
+				X: b.X, Y: b.Y, Distance: systemDistance(a, b)}
+			if len(b.Factions) > 0 {
+				cs.FactionSymbol = b.Factions[0].Symbol
+			}
+			gate.ConnectedSystems = append(gate.ConnectedSystems, cs)
+		}
+		a.gate.jumpGate = gate
+	}
+}
+
+func systemDistance(a, b *system) int {
+	return distance(a.X, a.Y, b.X, b.Y)
+}
+
+func distance(x1, y1, x2, y2 int) int {
+	return int(math.Round(math.Hypot(float64(x2-x1), float64(y2-y1))))
+}
+
+func (u *universe) faction(symbol string) (models.Faction, bool) {
+	for _, f := range u.factions {
+		if f.Symbol == symbol {
+			return f, true
+		}
+	}
+	return models.Faction{}, false
+}
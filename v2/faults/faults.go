@@ -0,0 +1,303 @@
+// Package faults injects failures into SDK traffic so a bot's retry and
+// recovery paths can be exercised on demand, against the real API or a
+// local stand-in such as package fake.
+//
+//	inj := faults.New(
+//		faults.WithSeed(7),
+//		faults.WithOperation("NavigateShip", faults.Fault{RateLimit: 0.1}),
+//		faults.WithOperation("GetMarket", faults.Fault{Latency: 2 * time.Second}),
+//	)
+//	sdk := v2.NewSpaceTradersClient(v2.WithFaultInjection(inj))
+//
+// Faults are chosen per call from the operation's Fault, falling back to
+// the default one. Rate limits and server errors are answered without
+// reaching the API; truncated bodies are cut from a genuine response, so
+// the call has taken effect even though the bot can't read the result.
+package faults
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/internal/wait"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault sets how often each kind of failure strikes one operation. The
+// probabilities are per call and exclusive, so their sum should not exceed
+// 1.
+type Fault struct {
+	// RateLimit answers 429 Too Many Requests, as the API does when the
+	// request rate is exceeded.
+	RateLimit float64
+	// ServerError answers 503 Service Unavailable.
+	ServerError float64
+	// Timeout fails the call with an error whose Timeout method reports
+	// true, after TimeoutAfter or once the request context is done.
+	// TimeoutAfter is DefaultTimeoutAfter when it is not positive.
+	Timeout      float64
+	TimeoutAfter time.Duration
+	// Truncate sends the request and cuts the response body in half.
+	Truncate float64
+	// Latency delays every call, plus up to Jitter more at random.
+	Latency time.Duration
+	Jitter  time.Duration
+}
+
+// DefaultTimeoutAfter is how long an injected timeout hangs when its
+// Fault doesn't say, so a bot sees a timeout only after a wait like a
+// real one.
+const DefaultTimeoutAfter = 30 * time.Second
+
+// Counts tallies the calls an Injector has seen for one operation.
+type Counts struct {
+	Calls        int
+	RateLimited  int
+	ServerErrors int
+	Timeouts     int
+	Truncated    int
+}
+
+// Injector decides which calls fail. It is safe for concurrent use.
+type Injector struct {
+	def Fault
+	ops map[string]Fault
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	counts map[string]*Counts
+}
+
+type injectorOpts func(*Injector)
+
+var (
+	defaultOpts = []injectorOpts{
+		WithSeed(time.Now().UnixNano()),
+	}
+)
+
+// WithSeed makes the injected faults reproducible.
+func WithSeed(seed int64) injectorOpts {
+	return func(i *Injector) {
+		i.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithDefault sets the fault for operations without one of their own.
+func WithDefault(f Fault) injectorOpts {
+	return func(i *Injector) {
+		i.def = f
+	}
+}
+
+// WithOperation sets the fault for one SDK operation, e.g. "NavigateShip".
+func WithOperation(operation string, f Fault) injectorOpts {
+	return func(i *Injector) {
+		i.ops[operation] = f
+	}
+}
+
+// New returns an Injector that fails calls as opts say, and otherwise
+// passes them through.
+func New(opts ...injectorOpts) *Injector {
+	i := &Injector{ops: map[string]Fault{}, counts: map[string]*Counts{}}
+
+	opts = append(defaultOpts, opts...)
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Counts returns the tally for operation, or for requests not made by an
+// SDK method when operation is "".
+func (i *Injector) Counts(operation string) Counts {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if c, ok := i.counts[operation]; ok {
+		return *c
+	}
+	return Counts{}
+}
+
+// Transport wraps base, or http.DefaultTransport when base is nil.
+func (i *Injector) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{i: i, base: base}
+}
+
+// Client returns a copy of base, or of http.DefaultClient when base is nil,
+// whose transport injects faults.
+func (i *Injector) Client(base *http.Client) *http.Client {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	c := *base
+	c.Transport = i.Transport(base.Transport)
+	return &c
+}
+
+type kind int
+
+const (
+	none kind = iota
+	rateLimit
+	serverError
+	timeout
+	truncate
+)
+
+// roll picks the fault for one call to operation and how long to delay it.
+func (i *Injector) roll(operation string) (kind, Fault, time.Duration) {
+	f, ok := i.ops[operation]
+	if !ok {
+		f = i.def
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	c, ok := i.counts[operation]
+	if !ok {
+		c = &Counts{}
+		i.counts[operation] = c
+	}
+	c.Calls++
+
+	delay := f.Latency
+	if f.Jitter > 0 {
+		delay += time.Duration(i.rng.Int63n(int64(f.Jitter)))
+	}
+
+	p := i.rng.Float64()
+	for _, k := range []struct {
+		kind  kind
+		prob  float64
+		count *int
+	}{
+		{rateLimit, f.RateLimit, &c.RateLimited},
+		{serverError, f.ServerError, &c.ServerErrors},
+		{timeout, f.Timeout, &c.Timeouts},
+		{truncate, f.Truncate, &c.Truncated},
+	} {
+		if p < k.prob {
+			*k.count++
+			return k.kind, f, delay
+		}
+		p -= k.prob
+	}
+	return none, f, delay
+}
+
+type transport struct {
+	i    *Injector
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	operation := rest.Operation(ctx)
+	k, f, delay := t.i.roll(operation)
+
+	if err := sleep(ctx, delay); err != nil {
+		closeBody(req)
+		return nil, err
+	}
+
+	switch k {
+	case rateLimit:
+		closeBody(req)
+		resp := respond(req, http.StatusTooManyRequests, `{"error":{"message":"You have reached your API limit. Please wait before making another request.","code":429,"data":{"type":"IntervalLimit","retryAfter":1,"limitBurst":30,"limitPerSecond":2,"remaining":0}}}`)
+		resp.Header.Set("Retry-After", "1")
+		resp.Header.Set("X-Ratelimit-Type", "IntervalLimit")
+		return resp, nil
+	case serverError:
+		closeBody(req)
+		return respond(req, http.StatusServiceUnavailable, `{"error":{"message":"Service unavailable.","code":503}}`), nil
+	case timeout:
+		closeBody(req)
+		after := f.TimeoutAfter
+		if after <= 0 {
+			after = DefaultTimeoutAfter
+		}
+		if err := sleep(ctx, after); err != nil {
+			return nil, err
+		}
+		return nil, &TimeoutError{Operation: operation}
+	case truncate:
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		return truncated(resp)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// TimeoutError is the error of an injected timeout. Like a real network
+// timeout it satisfies net.Error with Timeout reporting true.
+type TimeoutError struct {
+	Operation string
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Operation == "" {
+		return "faults: injected timeout"
+	}
+	return fmt.Sprintf("faults: injected timeout in %s", e.Operation)
+}
+
+func (e *TimeoutError) Timeout() bool   { return true }
+func (e *TimeoutError) Temporary() bool { return true }
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	return wait.For(ctx, d)
+}
+
+// closeBody honours the RoundTripper contract for requests that are
+// answered without being sent.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+func respond(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func truncated(resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	body = body[:len(body)/2]
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header = resp.Header.Clone()
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
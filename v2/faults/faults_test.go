@@ -0,0 +1,150 @@
+package faults_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/fake"
+	"spacetradersgo/v2/faults"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/models"
+)
+
+// setup registers an agent on a fake server and returns a fleets client
+// whose traffic goes through inj, with the agent's token and first ship.
+func setup(t *testing.T, inj *faults.Injector) (fleets.FleetsClient, string, string) {
+	t.Helper()
+	srv := fake.NewServer(fake.WithSeed(7))
+	t.Cleanup(srv.Close)
+	token, err := srv.Register("FAULTY", "COSMIC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := fleets.NewFleets(fleets.WithHTTPClient(srv.Client())).ListShips(context.Background(), &fleets.ListShipsRequest{Token: token})
+	if err != nil {
+		t.Fatalf("ListShips: %v", err)
+	}
+	return fleets.NewFleets(fleets.WithHTTPClient(inj.Client(srv.Client()))), token, list.Ships[0].Symbol
+}
+
+func statusOf(err error) int {
+	var apiErr *models.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func TestPerOperationRates(t *testing.T) {
+	const n = 400
+	run := func() (ship, nav faults.Counts) {
+		inj := faults.New(
+			faults.WithSeed(1),
+			faults.WithDefault(faults.Fault{ServerError: 0.2}),
+			faults.WithOperation("GetShip", faults.Fault{RateLimit: 0.5}),
+		)
+		fl, token, id := setup(t, inj)
+		ctx := context.Background()
+		seen := map[string]map[int]int{"GetShip": {}, "GetShipNav": {}}
+		for i := 0; i < n; i++ {
+			_, err := fl.GetShip(ctx, &fleets.GetShipRequest{Token: token, ShipID: id})
+			seen["GetShip"][statusOf(err)]++
+			_, err = fl.GetShipNav(ctx, &fleets.GetShipNavRequest{Token: token, ShipID: id})
+			seen["GetShipNav"][statusOf(err)]++
+		}
+
+		ship, nav = inj.Counts("GetShip"), inj.Counts("GetShipNav")
+		// Every fault counted is one the caller saw, and nothing else.
+		if seen["GetShip"][http.StatusTooManyRequests] != ship.RateLimited || seen["GetShip"][0] != n-ship.RateLimited {
+			t.Errorf("GetShip answers %v, counted %+v", seen["GetShip"], ship)
+		}
+		if seen["GetShipNav"][http.StatusServiceUnavailable] != nav.ServerErrors || seen["GetShipNav"][0] != n-nav.ServerErrors {
+			t.Errorf("GetShipNav answers %v, counted %+v", seen["GetShipNav"], nav)
+		}
+		return ship, nav
+	}
+
+	ship, nav := run()
+	if ship.Calls != n || ship.ServerErrors != 0 || ship.RateLimited < n*4/10 || ship.RateLimited > n*6/10 {
+		t.Errorf("GetShip counts %+v, want about half of %d rate limited and nothing else", ship, n)
+	}
+	if nav.Calls != n || nav.RateLimited != 0 || nav.ServerErrors < n*1/10 || nav.ServerErrors > n*3/10 {
+		t.Errorf("GetShipNav counts %+v, want about a fifth of %d server errors from the default", nav, n)
+	}
+	if c := faults.New().Counts("GetShip"); c != (faults.Counts{}) {
+		t.Errorf("Counts of a fresh injector = %+v", c)
+	}
+
+	// The same seed fails the same calls.
+	if ship2, nav2 := run(); ship2 != ship || nav2 != nav {
+		t.Errorf("second run counted %+v and %+v, want %+v and %+v", ship2, nav2, ship, nav)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	inj := faults.New(
+		faults.WithSeed(1),
+		faults.WithOperation("GetShip", faults.Fault{Latency: 30 * time.Millisecond, Jitter: 20 * time.Millisecond}),
+	)
+	fl, token, id := setup(t, inj)
+
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		if _, err := fl.GetShip(context.Background(), &fleets.GetShipRequest{Token: token, ShipID: id}); err != nil {
+			t.Fatalf("GetShip: %v", err)
+		}
+		if took := time.Since(start); took < 30*time.Millisecond {
+			t.Errorf("GetShip took %v, want at least the 30ms latency", took)
+		}
+	}
+
+	// The delay gives up with the context.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := fl.GetShip(ctx, &fleets.GetShipRequest{Token: token, ShipID: id}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetShip err = %v, want the context's deadline", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	inj := faults.New(
+		faults.WithSeed(1),
+		faults.WithOperation("GetShip", faults.Fault{Timeout: 1, TimeoutAfter: 20 * time.Millisecond}),
+		faults.WithOperation("GetShipNav", faults.Fault{Timeout: 1}),
+	)
+	fl, token, id := setup(t, inj)
+
+	start := time.Now()
+	_, err := fl.GetShip(context.Background(), &fleets.GetShipRequest{Token: token, ShipID: id})
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("GetShip err = %v, want a timeout", err)
+	}
+	var timeoutErr *faults.TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Operation != "GetShip" {
+		t.Errorf("GetShip err = %v, want a TimeoutError in GetShip", err)
+	}
+	if took := time.Since(start); took < 20*time.Millisecond {
+		t.Errorf("timed out after %v, want at least TimeoutAfter", took)
+	}
+	if c := inj.Counts("GetShip"); c.Calls != 1 || c.Timeouts != 1 {
+		t.Errorf("counts %+v, want one timeout", c)
+	}
+
+	// Without TimeoutAfter the call hangs for DefaultTimeoutAfter rather
+	// than failing at once, so here the context runs out first.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = fl.GetShipNav(ctx, &fleets.GetShipNavRequest{Token: token, ShipID: id})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetShipNav err = %v, want the context's deadline", err)
+	}
+	if took := time.Since(start); took < 50*time.Millisecond {
+		t.Errorf("timed out after %v, want to wait for the context", took)
+	}
+}
@@ -0,0 +1,115 @@
+package fleets
+
+import (
+	"context"
+	"spacetradersgo/v2/internal/wait"
+	"spacetradersgo/v2/models"
+	"time"
+)
+
+// Arrival is the outcome of NavigateAndWait.
+type Arrival struct {
+	// Navigation is the NavigateShip response, with the fuel used and the
+	// route taken.
+	Navigation *NavigateShipResponse
+	// Nav is the ship's nav once it has arrived, after any requested
+	// transition.
+	Nav models.ShipNav
+}
+
+type arrivalConfig struct {
+	status models.ShipNavStatus
+	margin time.Duration
+}
+
+type arrivalOpts func(*arrivalConfig)
+
+var (
+	defaultArrivalOpts = []arrivalOpts{
+		WithArrivalMargin(500 * time.Millisecond),
+	}
+)
+
+// WithArrivalStatus docks or orbits the ship once it has arrived. Ships
+// arrive in orbit, so ShipNavStatusDocked is the useful choice; with
+// ShipNavStatusInOrbit the ship is only put back in orbit if something
+// docked it in the meantime.
+func WithArrivalStatus(status models.ShipNavStatus) arrivalOpts {
+	return func(c *arrivalConfig) {
+		c.status = status
+	}
+}
+
+// WithArrivalMargin sets how long to wait past the expected arrival before
+// checking on the ship.
+func WithArrivalMargin(margin time.Duration) arrivalOpts {
+	return func(c *arrivalConfig) {
+		c.margin = margin
+	}
+}
+
+// NavigateAndWait navigates the ship and blocks until it has arrived,
+// replacing a GetShipNav polling loop.
+//
+// The wait is timed from the route's departure and arrival times, which are
+// both server time, so a skewed local clock doesn't matter. Afterwards the
+// ship's nav is fetched to confirm it has left IN_TRANSIT, waiting again if
+// it hasn't.
+//
+// If ctx is done while the ship is in flight, the Arrival returned
+// alongside ctx's error still holds the Navigation, since the ship is on
+// its way regardless.
+func NavigateAndWait(ctx context.Context, client FleetsClient, req *NavigateShipRequest, opts ...arrivalOpts) (*Arrival, error) {
+	cfg := &arrivalConfig{}
+	for _, opt := range append(defaultArrivalOpts, opts...) {
+		opt(cfg)
+	}
+
+	nav, err := client.NavigateShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	received := time.Now()
+	arrival := &Arrival{Navigation: nav, Nav: nav.Data.Nav}
+
+	route := nav.Data.Nav.Route
+	flight := route.Arrival.Sub(route.DepartureTime)
+	if route.DepartureTime.IsZero() {
+		flight = time.Until(route.Arrival)
+	}
+	deadline := received.Add(flight)
+
+	for arrival.Nav.Status == models.ShipNavStatusInTransit {
+		if err := wait.Until(ctx, deadline.Add(cfg.margin)); err != nil {
+			return arrival, err
+		}
+		resp, err := client.GetShipNav(ctx, &GetShipNavRequest{Token: req.Token, ShipID: req.ShipID})
+		if err != nil {
+			return arrival, err
+		}
+		arrival.Nav = resp.Nav
+		// Still in transit means the arrival moved or the margin was too
+		// small; try again a little later.
+		deadline = time.Now()
+		cfg.margin *= 2
+		if cfg.margin <= 0 {
+			cfg.margin = time.Second
+		}
+	}
+
+	switch {
+	case cfg.status == models.ShipNavStatusDocked && arrival.Nav.Status != models.ShipNavStatusDocked:
+		resp, err := client.DockShip(ctx, &DockShipRequest{Token: req.Token, ShipID: req.ShipID})
+		if err != nil {
+			return arrival, err
+		}
+		arrival.Nav = resp.Data.Nav
+	case cfg.status == models.ShipNavStatusInOrbit && arrival.Nav.Status != models.ShipNavStatusInOrbit:
+		resp, err := client.OrbitShip(ctx, &OrbitShipRequest{Token: req.Token, ShipID: req.ShipID})
+		if err != nil {
+			return arrival, err
+		}
+		arrival.Nav = resp.Data.Nav
+	}
+	return arrival, nil
+}
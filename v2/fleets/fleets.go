@@ -1,17 +1,16 @@
 package fleets
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
-	"strconv"
+	"net/url"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/strict"
 )
 
 type fleetClient struct {
 	httpClient *http.Client
+	strict     *strict.Recorder
 }
 
 type fleetClientOpts func(*fleetClient)
@@ -28,6 +27,14 @@ func WithHTTPClient(httpClient *http.Client) fleetClientOpts {
 	}
 }
 
+// WithStrictDecoding checks every response against the SDK types and
+// records unknown or missing fields in recorder.
+func WithStrictDecoding(recorder *strict.Recorder) fleetClientOpts {
+	return func(c *fleetClient) {
+		c.strict = recorder
+	}
+}
+
 func NewFleets(opts ...fleetClientOpts) *fleetClient {
 	c := &fleetClient{}
 
@@ -40,345 +47,29 @@ func NewFleets(opts ...fleetClientOpts) *fleetClient {
 	return c
 }
 
-func (c *fleetClient) ListShips(ctx context.Context, req *ListShipsRequest) (*ListShipsResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/my/ships", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-	values := httpReq.URL.Query()
-	if req.NumPerPage != 0 {
-		values.Set("limit", strconv.Itoa(req.NumPerPage))
-	}
-	if req.Page != 0 {
-		values.Set("page", strconv.Itoa(req.Page))
-	}
-	httpReq.URL.RawQuery = values.Encode()
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &ListShipsResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (c *fleetClient) rest() *rest.Client {
+	return &rest.Client{HTTPClient: c.httpClient, Strict: c.strict}
 }
 
-func (c *fleetClient) GetShip(ctx context.Context, req *GetShipRequest) (*GetShipResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID, nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetShipResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *fleetClient) GetShipCargo(ctx context.Context, req *GetShipCargoRequest) (*GetShipCargoResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/cargo", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetShipCargoResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *fleetClient) GetShipNav(ctx context.Context, req *GetShipNavRequest) (*GetShipNavResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/nav", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetShipNavResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (c *fleetClient) do(ctx context.Context, req *rest.Request, out any) error {
+	_, err := c.rest().Do(ctx, req, out)
+	return err
 }
 
+// GetShipCooldown is written by hand because the API answers 204 No Content
+// when the ship has no cooldown, which is reported as IsOnCooldown false.
 func (c *fleetClient) GetShipCooldown(ctx context.Context, req *GetShipCooldownRequest) (*GetShipCooldownResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/cooldown", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	if httpResp.StatusCode == http.StatusNoContent {
-		return &GetShipCooldownResponse{
-			IsOnCooldown: false,
-			Cooldown:     Cooldown{},
-		}, nil
-	}
-
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetShipCooldownResponse{
-		IsOnCooldown: true,
-	}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *fleetClient) OrbitShip(ctx context.Context, req *OrbitShipRequest) (*OrbitShipResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodPost, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/orbit", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &OrbitShipResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *fleetClient) DockShip(ctx context.Context, req *DockShipRequest) (*DockShipResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodPost, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/dock", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &DockShipResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *fleetClient) CreateChart(ctx context.Context, req *CreateChartRequest) (*CreateChartResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodPost, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/chart", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(body))
-
-	resp := &CreateChartResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *fleetClient) CreateSurvey(ctx context.Context, req *CreateSurveyRequest) (*CreateSurveyResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodPost, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/survey", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(body))
-
-	resp := &CreateSurveyResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *fleetClient) NavigateShip(ctx context.Context, req *NavagateShipRequest) (*NavagateShipResponse, error) {
-	reqBody, err := json.Marshal(map[string]string{"waypointSymbol": req.WaypointSymbol})
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(reqBody))
-	httpReq, err := http.NewRequest(http.MethodPost, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/navigate", bytes.NewReader(reqBody))
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(body))
-
-	resp := &NavagateShipResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *fleetClient) ExtractResource(ctx context.Context, req *ExtractResourceRequest) (*ExtractResourceResponse, error) {
-	reqBody, err := json.Marshal(req.Survey)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(reqBody))
-	httpReq, err := http.NewRequest(http.MethodPost, "https://api.spacetraders.io/v2/my/ships/"+req.ShipID+"/extract", bytes.NewReader(reqBody))
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Println(string(body))
-
-	resp := &ExtractResourceResponse{}
-	err = json.Unmarshal(body, resp)
+	resp := &GetShipCooldownResponse{}
+	httpResp, err := c.rest().Do(ctx, &rest.Request{
+		Operation: "GetShipCooldown",
+		Method:    http.MethodGet,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/cooldown",
+		Token:     req.Token,
+	}, resp)
 	if err != nil {
 		return nil, err
 	}
+	resp.IsOnCooldown = httpResp.StatusCode != http.StatusNoContent
 
 	return resp, nil
 }
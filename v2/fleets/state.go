@@ -0,0 +1,424 @@
+package fleets
+
+import (
+	"context"
+	"sort"
+	"spacetradersgo/v2/models"
+	"sync"
+	"time"
+)
+
+// ShipState is the mirrored state of one ship.
+type ShipState struct {
+	Ship Ship
+	// Cooldown is the last cooldown reported for the ship. It is zero if
+	// none has been seen or the ship has none.
+	Cooldown Cooldown
+	// Updated is when the entry last changed.
+	Updated time.Time
+}
+
+// FleetState keeps a local mirror of the agent's fleet. It wraps a
+// FleetsClient and is one itself: every response passing through it is
+// merged into the mirror, so the partial nav, fuel, cargo and cooldown data
+// the mutating endpoints return keep each ship current without re-fetching
+// it. Install it with v2.WithFleetClient to cover every fleet call.
+//
+// Ships enter the mirror through Sync, ListShips or GetShip; partial
+// updates for ships it doesn't hold yet are ignored. Ships bought with
+// systems.PurchaseShip and cargo delivered with contracts.DeliverContract
+// pass through other clients, so feed those in with Put and SetCargo.
+//
+// With WithAutoTransition, operations that need the ship docked or in
+// orbit dock or orbit it first.
+//
+// FleetState is safe for concurrent use. Snapshots are copies the caller
+// may keep and modify.
+type FleetState struct {
+	FleetsClient
+	auto bool
+
+	mu       sync.Mutex
+	ships    map[string]*ShipState
+	watchers map[int]func(ShipState)
+	nextID   int
+}
+
+type fleetStateOpts func(*FleetState)
+
+// WithAutoTransition makes SellCargo, PurchaseCargo, RefuelShip and
+// NegotiateContract dock the ship, and NavigateShip, WarpShip, JumpShip and
+// ExtractResource put it in orbit, before making the call. The mirrored
+// status decides whether a transition is needed, so it costs no extra
+// request when the ship is already where it should be.
+func WithAutoTransition() fleetStateOpts {
+	return func(s *FleetState) {
+		s.auto = true
+	}
+}
+
+func NewFleetState(client FleetsClient, opts ...fleetStateOpts) *FleetState {
+	s := &FleetState{
+		FleetsClient: client,
+		ships:        map[string]*ShipState{},
+		watchers:     map[int]func(ShipState){},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Sync replaces the mirror with every ship ListShips returns.
+func (s *FleetState) Sync(ctx context.Context, token string) error {
+	var ships []Ship
+	for page := 1; ; page++ {
+		resp, err := s.FleetsClient.ListShips(ctx, &ListShipsRequest{Token: token, Page: page, NumPerPage: 20})
+		if err != nil {
+			return err
+		}
+		ships = append(ships, resp.Ships...)
+		if len(resp.Ships) == 0 || len(ships) >= resp.Meta.Total {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	old := s.ships
+	s.ships = map[string]*ShipState{}
+	var changed []ShipState
+	for _, ship := range ships {
+		st := &ShipState{Ship: ship, Updated: time.Now()}
+		if prev, ok := old[ship.Symbol]; ok {
+			st.Cooldown = prev.Cooldown
+		}
+		s.ships[ship.Symbol] = st
+		changed = append(changed, st.snapshot())
+	}
+	s.mu.Unlock()
+	s.notify(changed...)
+	return nil
+}
+
+// Ship returns a snapshot of one ship.
+func (s *FleetState) Ship(shipID string) (ShipState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.ships[shipID]
+	if !ok {
+		return ShipState{}, false
+	}
+	return st.snapshot(), true
+}
+
+// Ships returns a snapshot of every ship, ordered by symbol.
+func (s *FleetState) Ships() []ShipState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ShipState, 0, len(s.ships))
+	for _, st := range s.ships {
+		out = append(out, st.snapshot())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Ship.Symbol < out[j].Ship.Symbol })
+	return out
+}
+
+// Watch calls fn with a snapshot of every ship that changes, after the
+// change is applied, until stop is called. Calls are made synchronously on
+// the goroutine that made the change, so fn must not block for long.
+func (s *FleetState) Watch(fn func(ShipState)) (stop func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextID
+	s.nextID++
+	s.watchers[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.watchers, id)
+	}
+}
+
+// Put adds or replaces a whole ship, keeping any known cooldown.
+func (s *FleetState) Put(ship Ship) {
+	s.mu.Lock()
+	st, ok := s.ships[ship.Symbol]
+	if !ok {
+		st = &ShipState{}
+		s.ships[ship.Symbol] = st
+	}
+	st.Ship = ship
+	st.Updated = time.Now()
+	snap := st.snapshot()
+	s.mu.Unlock()
+	s.notify(snap)
+}
+
+// SetCargo records a ship's cargo hold.
+func (s *FleetState) SetCargo(shipID string, cargo Cargo) {
+	s.update(shipID, func(st *ShipState) { st.Ship.Cargo = cargo })
+}
+
+// update applies fn to a known ship and notifies watchers.
+func (s *FleetState) update(shipID string, fn func(*ShipState)) {
+	s.mu.Lock()
+	st, ok := s.ships[shipID]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	fn(st)
+	st.Updated = time.Now()
+	snap := st.snapshot()
+	s.mu.Unlock()
+	s.notify(snap)
+}
+
+func (s *FleetState) notify(changed ...ShipState) {
+	s.mu.Lock()
+	ids := make([]int, 0, len(s.watchers))
+	for id := range s.watchers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	watchers := make([]func(ShipState), 0, len(ids))
+	for _, id := range ids {
+		watchers = append(watchers, s.watchers[id])
+	}
+	s.mu.Unlock()
+
+	for _, st := range changed {
+		for _, fn := range watchers {
+			fn(st)
+		}
+	}
+}
+
+// snapshot copies the entry, with a ship whose arrival time has passed
+// shown in orbit at its destination, as the API would report it.
+func (st *ShipState) snapshot() ShipState {
+	c := *st
+	c.Ship.Modules = append([]models.ShipModule(nil), st.Ship.Modules...)
+	c.Ship.Mounts = append([]models.ShipMount(nil), st.Ship.Mounts...)
+	c.Ship.Cargo.Inventory = append([]models.ShipCargoItem(nil), st.Ship.Cargo.Inventory...)
+	if c.Ship.Nav.Status == models.ShipNavStatusInTransit && !c.Ship.Nav.Route.Arrival.After(time.Now()) {
+		c.Ship.Nav.Status = models.ShipNavStatusInOrbit
+	}
+	return c
+}
+
+func (s *FleetState) ListShips(ctx context.Context, req *ListShipsRequest) (*ListShipsResponse, error) {
+	resp, err := s.FleetsClient.ListShips(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, ship := range resp.Ships {
+		s.Put(ship)
+	}
+	return resp, nil
+}
+
+func (s *FleetState) GetShip(ctx context.Context, req *GetShipRequest) (*GetShipResponse, error) {
+	resp, err := s.FleetsClient.GetShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.Put(resp.Ship)
+	return resp, nil
+}
+
+func (s *FleetState) GetShipCargo(ctx context.Context, req *GetShipCargoRequest) (*GetShipCargoResponse, error) {
+	resp, err := s.FleetsClient.GetShipCargo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.SetCargo(req.ShipID, resp.Cargo)
+	return resp, nil
+}
+
+func (s *FleetState) GetShipNav(ctx context.Context, req *GetShipNavRequest) (*GetShipNavResponse, error) {
+	resp, err := s.FleetsClient.GetShipNav(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.setNav(req.ShipID, resp.Nav)
+	return resp, nil
+}
+
+func (s *FleetState) PatchShipNav(ctx context.Context, req *PatchShipNavRequest) (*PatchShipNavResponse, error) {
+	resp, err := s.FleetsClient.PatchShipNav(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.setNav(req.ShipID, resp.Nav)
+	return resp, nil
+}
+
+func (s *FleetState) OrbitShip(ctx context.Context, req *OrbitShipRequest) (*OrbitShipResponse, error) {
+	resp, err := s.FleetsClient.OrbitShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.setNav(req.ShipID, resp.Data.Nav)
+	return resp, nil
+}
+
+func (s *FleetState) DockShip(ctx context.Context, req *DockShipRequest) (*DockShipResponse, error) {
+	resp, err := s.FleetsClient.DockShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.setNav(req.ShipID, resp.Data.Nav)
+	return resp, nil
+}
+
+func (s *FleetState) NavigateShip(ctx context.Context, req *NavigateShipRequest) (*NavigateShipResponse, error) {
+	if err := s.transition(ctx, req.Token, req.ShipID, models.ShipNavStatusInOrbit); err != nil {
+		return nil, err
+	}
+	resp, err := s.FleetsClient.NavigateShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.update(req.ShipID, func(st *ShipState) {
+		st.Ship.Nav = resp.Data.Nav
+		st.Ship.Fuel = resp.Data.Fuel
+	})
+	return resp, nil
+}
+
+func (s *FleetState) WarpShip(ctx context.Context, req *WarpShipRequest) (*WarpShipResponse, error) {
+	if err := s.transition(ctx, req.Token, req.ShipID, models.ShipNavStatusInOrbit); err != nil {
+		return nil, err
+	}
+	resp, err := s.FleetsClient.WarpShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.update(req.ShipID, func(st *ShipState) {
+		st.Ship.Nav = resp.Data.Nav
+		st.Ship.Fuel = resp.Data.Fuel
+	})
+	return resp, nil
+}
+
+func (s *FleetState) JumpShip(ctx context.Context, req *JumpShipRequest) (*JumpShipResponse, error) {
+	if err := s.transition(ctx, req.Token, req.ShipID, models.ShipNavStatusInOrbit); err != nil {
+		return nil, err
+	}
+	resp, err := s.FleetsClient.JumpShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.update(req.ShipID, func(st *ShipState) {
+		st.Ship.Nav = resp.Data.Nav
+		st.Cooldown = resp.Data.Cooldown
+	})
+	return resp, nil
+}
+
+func (s *FleetState) GetShipCooldown(ctx context.Context, req *GetShipCooldownRequest) (*GetShipCooldownResponse, error) {
+	resp, err := s.FleetsClient.GetShipCooldown(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.update(req.ShipID, func(st *ShipState) {
+		st.Cooldown = resp.Cooldown
+		if !resp.IsOnCooldown {
+			st.Cooldown = Cooldown{ShipSymbol: req.ShipID}
+		}
+	})
+	return resp, nil
+}
+
+func (s *FleetState) CreateSurvey(ctx context.Context, req *CreateSurveyRequest) (*CreateSurveyResponse, error) {
+	resp, err := s.FleetsClient.CreateSurvey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.update(req.ShipID, func(st *ShipState) { st.Cooldown = resp.Data.Cooldown })
+	return resp, nil
+}
+
+func (s *FleetState) ExtractResource(ctx context.Context, req *ExtractResourceRequest) (*ExtractResourceResponse, error) {
+	if err := s.transition(ctx, req.Token, req.ShipID, models.ShipNavStatusInOrbit); err != nil {
+		return nil, err
+	}
+	resp, err := s.FleetsClient.ExtractResource(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.update(req.ShipID, func(st *ShipState) {
+		st.Ship.Cargo = resp.Data.Cargo
+		st.Cooldown = resp.Data.Cooldown
+	})
+	return resp, nil
+}
+
+func (s *FleetState) Jettison(ctx context.Context, req *JettisonRequest) (*JettisonResponse, error) {
+	resp, err := s.FleetsClient.Jettison(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.SetCargo(req.ShipID, resp.Data.Cargo)
+	return resp, nil
+}
+
+func (s *FleetState) SellCargo(ctx context.Context, req *SellCargoRequest) (*SellCargoResponse, error) {
+	if err := s.transition(ctx, req.Token, req.ShipID, models.ShipNavStatusDocked); err != nil {
+		return nil, err
+	}
+	resp, err := s.FleetsClient.SellCargo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.SetCargo(req.ShipID, resp.Data.Cargo)
+	return resp, nil
+}
+
+func (s *FleetState) PurchaseCargo(ctx context.Context, req *PurchaseCargoRequest) (*PurchaseCargoResponse, error) {
+	if err := s.transition(ctx, req.Token, req.ShipID, models.ShipNavStatusDocked); err != nil {
+		return nil, err
+	}
+	resp, err := s.FleetsClient.PurchaseCargo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.SetCargo(req.ShipID, resp.Data.Cargo)
+	return resp, nil
+}
+
+func (s *FleetState) RefuelShip(ctx context.Context, req *RefuelShipRequest) (*RefuelShipResponse, error) {
+	if err := s.transition(ctx, req.Token, req.ShipID, models.ShipNavStatusDocked); err != nil {
+		return nil, err
+	}
+	resp, err := s.FleetsClient.RefuelShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.update(req.ShipID, func(st *ShipState) { st.Ship.Fuel = resp.Data.Fuel })
+	return resp, nil
+}
+
+func (s *FleetState) NegotiateContract(ctx context.Context, req *NegotiateContractRequest) (*NegotiateContractResponse, error) {
+	if err := s.transition(ctx, req.Token, req.ShipID, models.ShipNavStatusDocked); err != nil {
+		return nil, err
+	}
+	return s.FleetsClient.NegotiateContract(ctx, req)
+}
+
+// transition moves the ship into status first when auto transitions are
+// on.
+func (s *FleetState) transition(ctx context.Context, token, shipID string, status models.ShipNavStatus) error {
+	if !s.auto {
+		return nil
+	}
+	_, err := ensureStatus(ctx, s, token, shipID, status)
+	return err
+}
+
+func (s *FleetState) setNav(shipID string, nav models.ShipNav) {
+	s.update(shipID, func(st *ShipState) { st.Ship.Nav = nav })
+}
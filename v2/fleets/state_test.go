@@ -0,0 +1,182 @@
+package fleets_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/mocks"
+	"spacetradersgo/v2/models"
+)
+
+func docked(symbol, waypoint string) fleets.Ship {
+	var ship fleets.Ship
+	ship.Symbol = symbol
+	ship.Nav = models.ShipNav{SystemSymbol: "X1-T", WaypointSymbol: waypoint, Status: models.ShipNavStatusDocked}
+	ship.Fuel = models.ShipFuel{Current: 100, Capacity: 400}
+	ship.Cargo = models.ShipCargo{Capacity: 40}
+	return ship
+}
+
+func TestFleetStateMergesResponses(t *testing.T) {
+	ctx := context.Background()
+	m := mocks.NewFleetsClient()
+	s := fleets.NewFleetState(m)
+	var changes []string
+	stop := s.Watch(func(st fleets.ShipState) { changes = append(changes, st.Ship.Symbol) })
+
+	get := &fleets.GetShipResponse{}
+	get.Ship = docked("SHIP-1", "X1-T-A")
+	m.OnGetShip().Return(get, nil)
+	if _, err := s.GetShip(ctx, &fleets.GetShipRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Navigating updates the nav and fuel it reports and nothing else.
+	nav := &fleets.NavigateShipResponse{}
+	nav.Data.Nav = models.ShipNav{SystemSymbol: "X1-T", WaypointSymbol: "X1-T-B", Status: models.ShipNavStatusInTransit}
+	nav.Data.Nav.Route.Arrival = time.Now().Add(time.Hour)
+	nav.Data.Fuel = models.ShipFuel{Current: 60, Capacity: 400}
+	m.OnNavigateShip().Return(nav, nil)
+	if _, err := s.NavigateShip(ctx, &fleets.NavigateShipRequest{ShipID: "SHIP-1", WaypointSymbol: "X1-T-B"}); err != nil {
+		t.Fatal(err)
+	}
+	st, _ := s.Ship("SHIP-1")
+	if st.Ship.Nav.Status != models.ShipNavStatusInTransit || st.Ship.Nav.WaypointSymbol != "X1-T-B" || st.Ship.Fuel.Current != 60 || st.Ship.Cargo.Capacity != 40 {
+		t.Errorf("after NavigateShip: nav %+v, fuel %+v, cargo %+v", st.Ship.Nav, st.Ship.Fuel, st.Ship.Cargo)
+	}
+
+	// Once the arrival time has passed the ship shows in orbit.
+	landed := &fleets.GetShipNavResponse{}
+	landed.Nav = nav.Data.Nav
+	landed.Nav.Route.Arrival = time.Now().Add(-time.Second)
+	m.OnGetShipNav().Return(landed, nil)
+	if _, err := s.GetShipNav(ctx, &fleets.GetShipNavRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := s.Ship("SHIP-1"); st.Ship.Nav.Status != models.ShipNavStatusInOrbit || st.Ship.Nav.WaypointSymbol != "X1-T-B" {
+		t.Errorf("after arriving: nav %+v, want in orbit at X1-T-B", st.Ship.Nav)
+	}
+
+	// Extracting updates the cargo and the cooldown.
+	extract := &fleets.ExtractResourceResponse{}
+	extract.Data.Cargo = models.ShipCargo{Capacity: 40, Units: 5, Inventory: []models.ShipCargoItem{{Symbol: "IRON_ORE", Units: 5}}}
+	extract.Data.Cooldown = fleets.Cooldown{ShipSymbol: "SHIP-1", TotalSeconds: 70, RemainingSeconds: 70}
+	m.OnExtractResource().Return(extract, nil)
+	if _, err := s.ExtractResource(ctx, &fleets.ExtractResourceRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+	st, _ = s.Ship("SHIP-1")
+	if st.Ship.Cargo.Units != 5 || st.Cooldown.RemainingSeconds != 70 || st.Ship.Fuel.Current != 60 || st.Ship.Nav.WaypointSymbol != "X1-T-B" {
+		t.Errorf("after ExtractResource: cargo %+v, cooldown %+v, fuel %+v, nav %+v", st.Ship.Cargo, st.Cooldown, st.Ship.Fuel, st.Ship.Nav)
+	}
+	// Snapshots are copies.
+	st.Ship.Cargo.Inventory[0].Units = 99
+	if st, _ := s.Ship("SHIP-1"); st.Ship.Cargo.Inventory[0].Units != 5 {
+		t.Errorf("changing a snapshot changed the mirror: %+v", st.Ship.Cargo)
+	}
+
+	refuel := &fleets.RefuelShipResponse{}
+	refuel.Data.Fuel = models.ShipFuel{Current: 400, Capacity: 400}
+	m.OnRefuelShip().Return(refuel, nil)
+	if _, err := s.RefuelShip(ctx, &fleets.RefuelShipRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+	sell := &fleets.SellCargoResponse{}
+	sell.Data.Cargo = models.ShipCargo{Capacity: 40}
+	m.OnSellCargo().Return(sell, nil)
+	if _, err := s.SellCargo(ctx, &fleets.SellCargoRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+	m.OnGetShipCooldown().Return(&fleets.GetShipCooldownResponse{}, nil)
+	if _, err := s.GetShipCooldown(ctx, &fleets.GetShipCooldownRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+	st, _ = s.Ship("SHIP-1")
+	if st.Ship.Fuel.Current != 400 || st.Ship.Cargo.Units != 0 || st.Cooldown != (fleets.Cooldown{ShipSymbol: "SHIP-1"}) {
+		t.Errorf("after refuelling, selling and cooling down: fuel %+v, cargo %+v, cooldown %+v", st.Ship.Fuel, st.Ship.Cargo, st.Cooldown)
+	}
+
+	// Partial updates for ships the mirror doesn't hold are ignored.
+	if _, err := s.GetShipNav(ctx, &fleets.GetShipNavRequest{ShipID: "SHIP-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Ship("SHIP-2"); ok {
+		t.Error("a nav response added SHIP-2 to the mirror")
+	}
+
+	stop()
+	s.Put(docked("SHIP-3", "X1-T-A"))
+	want := []string{"SHIP-1", "SHIP-1", "SHIP-1", "SHIP-1", "SHIP-1", "SHIP-1", "SHIP-1"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("watched changes = %v, want %v", changes, want)
+	}
+}
+
+func TestFleetStateSyncKeepsCooldowns(t *testing.T) {
+	ctx := context.Background()
+	m := mocks.NewFleetsClient()
+	s := fleets.NewFleetState(m)
+	s.Put(docked("SHIP-1", "X1-T-A"))
+	s.Put(docked("GONE", "X1-T-A"))
+	survey := &fleets.CreateSurveyResponse{}
+	survey.Data.Cooldown = fleets.Cooldown{ShipSymbol: "SHIP-1", RemainingSeconds: 30}
+	m.OnCreateSurvey().Return(survey, nil)
+	if _, err := s.CreateSurvey(ctx, &fleets.CreateSurveyRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	page := func(total int, ships ...fleets.Ship) *fleets.ListShipsResponse {
+		resp := &fleets.ListShipsResponse{Ships: ships}
+		resp.Meta.Total = total
+		return resp
+	}
+	moved := docked("SHIP-1", "X1-T-C")
+	m.OnListShips().When(func(r *fleets.ListShipsRequest) bool { return r.Page == 1 }).Return(page(2, moved), nil)
+	m.OnListShips().When(func(r *fleets.ListShipsRequest) bool { return r.Page == 2 }).Return(page(2, docked("SHIP-2", "X1-T-A")), nil)
+	if err := s.Sync(ctx, "token"); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	var got []string
+	for _, st := range s.Ships() {
+		got = append(got, st.Ship.Symbol+"@"+st.Ship.Nav.WaypointSymbol)
+	}
+	if want := []string{"SHIP-1@X1-T-C", "SHIP-2@X1-T-A"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ships after Sync = %v, want %v", got, want)
+	}
+	if st, _ := s.Ship("SHIP-1"); st.Cooldown.RemainingSeconds != 30 {
+		t.Errorf("cooldown after Sync = %+v, want the one seen before", st.Cooldown)
+	}
+}
+
+func TestFleetStateAutoTransition(t *testing.T) {
+	ctx := context.Background()
+	m := mocks.NewFleetsClient()
+	s := fleets.NewFleetState(m, fleets.WithAutoTransition())
+	s.Put(docked("SHIP-1", "X1-T-A"))
+
+	orbit := &fleets.OrbitShipResponse{}
+	orbit.Data.Nav = models.ShipNav{SystemSymbol: "X1-T", WaypointSymbol: "X1-T-A", Status: models.ShipNavStatusInOrbit}
+	m.OnOrbitShip().Return(orbit, nil).Once()
+	m.OnExtractResource().Expect().Times(2)
+	for i := 0; i < 2; i++ {
+		if _, err := s.ExtractResource(ctx, &fleets.ExtractResourceRequest{ShipID: "SHIP-1"}); err != nil {
+			t.Fatalf("ExtractResource: %v", err)
+		}
+	}
+
+	var methods []string
+	for _, c := range m.Calls() {
+		methods = append(methods, c.Method)
+	}
+	// The mirrored orbit spares the second extraction a transition.
+	if want := []string{"OrbitShip", "ExtractResource", "ExtractResource"}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("calls = %v, want %v", methods, want)
+	}
+	if err := m.Verify(); err != nil {
+		t.Error(err)
+	}
+}
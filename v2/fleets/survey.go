@@ -0,0 +1,218 @@
+package fleets
+
+import (
+	"context"
+	"sort"
+	"spacetradersgo/v2/models"
+	"sync"
+	"time"
+)
+
+// SizeWeights weigh survey scores by size, since larger deposits last for
+// more extractions before they are exhausted.
+var SizeWeights = map[models.SurveySize]float64{
+	models.SurveySizeSmall:    1,
+	models.SurveySizeModerate: 2,
+	models.SurveySizeLarge:    3,
+}
+
+// SurveyExpiryMargin is how long before its expiration a survey stops being
+// handed out, so an extraction doesn't race the expiry.
+const SurveyExpiryMargin = 5 * time.Second
+
+// SurveyScore rates a survey for mining targets: the fraction of its
+// deposits that are one of the targets, weighted by its size. Without
+// targets every deposit counts.
+func SurveyScore(survey Survey, targets ...string) float64 {
+	if len(survey.Deposits) == 0 {
+		return 0
+	}
+	hits := 0
+	for _, d := range survey.Deposits {
+		if len(targets) == 0 || contains(targets, d.Symbol) {
+			hits++
+		}
+	}
+	weight, ok := SizeWeights[survey.Size]
+	if !ok {
+		weight = 1
+	}
+	return float64(hits) / float64(len(survey.Deposits)) * weight
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SurveyStore collects surveys by waypoint and hands miners the best one
+// still valid for what they are after. Surveys are dropped once they
+// expire, or when an extraction reports them expired, exhausted or
+// invalid.
+//
+// SurveyStore is safe for concurrent use, so miners sharing a waypoint can
+// share its surveys.
+type SurveyStore struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	surveys map[string][]Survey
+}
+
+type surveyStoreOpts func(*SurveyStore)
+
+// WithSurveyClock sets where the store reads the time to expire surveys
+// by, e.g. a fake.ManualClock's Now, so expiry follows a simulated clock.
+// It is time.Now by default.
+func WithSurveyClock(now func() time.Time) surveyStoreOpts {
+	return func(s *SurveyStore) {
+		s.now = now
+	}
+}
+
+// NewSurveyStore returns an empty SurveyStore.
+func NewSurveyStore(opts ...surveyStoreOpts) *SurveyStore {
+	s := &SurveyStore{now: time.Now, surveys: map[string][]Survey{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add stores surveys under their waypoints. A survey already held is
+// replaced.
+func (s *SurveyStore) Add(surveys ...Survey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sv := range surveys {
+		s.remove(sv.Signature)
+		s.surveys[sv.Symbol] = append(s.surveys[sv.Symbol], sv)
+	}
+}
+
+// Surveys returns the valid surveys of waypoint, best for any deposit
+// first.
+func (s *SurveyStore) Surveys(waypoint string) []Survey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(waypoint)
+	out := append([]Survey(nil), s.surveys[waypoint]...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return SurveyScore(out[i]) > SurveyScore(out[j])
+	})
+	return out
+}
+
+// Best returns the valid survey of waypoint with the highest SurveyScore
+// for targets, preferring the one expiring last among equals. Surveys
+// without any of the targets are never returned.
+func (s *SurveyStore) Best(waypoint string, targets ...string) (Survey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(waypoint)
+	var best Survey
+	bestScore := 0.0
+	for _, sv := range s.surveys[waypoint] {
+		score := SurveyScore(sv, targets...)
+		if score > bestScore || (score == bestScore && score > 0 && sv.Expiration.After(best.Expiration)) {
+			best, bestScore = sv, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// Remove drops the survey with signature and reports whether it was held.
+func (s *SurveyStore) Remove(signature string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.remove(signature)
+}
+
+func (s *SurveyStore) remove(signature string) bool {
+	for wp, list := range s.surveys {
+		for i, sv := range list {
+			if sv.Signature == signature {
+				s.surveys[wp] = append(list[:i:i], list[i+1:]...)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Prune drops every expired survey and returns how many there were.
+func (s *SurveyStore) Prune() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for wp := range s.surveys {
+		n += s.prune(wp)
+	}
+	return n
+}
+
+func (s *SurveyStore) prune(waypoint string) int {
+	deadline := s.now().Add(SurveyExpiryMargin)
+	list := s.surveys[waypoint]
+	kept := list[:0]
+	for _, sv := range list {
+		if sv.Expiration.After(deadline) {
+			kept = append(kept, sv)
+		}
+	}
+	if len(kept) == 0 {
+		delete(s.surveys, waypoint)
+	} else {
+		s.surveys[waypoint] = kept
+	}
+	return len(list) - len(kept)
+}
+
+// Discard drops survey if err, from an extraction with it, says the survey
+// is expired, exhausted or not valid, and reports whether it did.
+func (s *SurveyStore) Discard(survey Survey, err error) bool {
+	if !models.IsCode(err, models.ErrCodeSurveyExpired) &&
+		!models.IsCode(err, models.ErrCodeSurveyExhausted) &&
+		!models.IsCode(err, models.ErrCodeSurveyVerification) {
+		return false
+	}
+	s.Remove(survey.Signature)
+	return true
+}
+
+// Survey surveys the ship's waypoint with CreateSurvey and adds the
+// results.
+func (s *SurveyStore) Survey(ctx context.Context, client FleetsClient, req *CreateSurveyRequest) (*CreateSurveyResponse, error) {
+	resp, err := client.CreateSurvey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.Add(resp.Data.Surveys...)
+	return resp, nil
+}
+
+// Extract extracts at the ship's waypoint with the best survey for
+// targets. If the API turns the survey down, it is dropped and the next
+// best is tried, down to extracting without one.
+func (s *SurveyStore) Extract(ctx context.Context, client FleetsClient, token, shipID string, targets ...string) (*ExtractResourceResponse, error) {
+	nav, err := currentNav(ctx, client, token, shipID)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		req := &ExtractResourceRequest{Token: token, ShipID: shipID}
+		survey, ok := s.Best(nav.WaypointSymbol, targets...)
+		if ok {
+			req.Survey = &survey
+		}
+		resp, err := client.ExtractResource(ctx, req)
+		if ok && s.Discard(survey, err) {
+			continue
+		}
+		return resp, err
+	}
+}
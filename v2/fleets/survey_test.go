@@ -0,0 +1,161 @@
+package fleets_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/fake"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/mocks"
+	"spacetradersgo/v2/models"
+)
+
+var surveyNow = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func survey(signature string, size models.SurveySize, expires time.Duration, deposits ...string) fleets.Survey {
+	sv := fleets.Survey{Signature: signature, Symbol: "X1-T-F", Size: size, Expiration: surveyNow.Add(expires)}
+	for _, d := range deposits {
+		sv.Deposits = append(sv.Deposits, models.SurveyDeposit{Symbol: d})
+	}
+	return sv
+}
+
+func TestSurveyScore(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		survey  fleets.Survey
+		targets []string
+		want    float64
+	}{
+		{"no deposits", survey("a", models.SurveySizeLarge, time.Hour), nil, 0},
+		{"every deposit without targets", survey("a", models.SurveySizeSmall, time.Hour, "IRON_ORE", "ICE_WATER"), nil, 1},
+		{"half the deposits, small", survey("a", models.SurveySizeSmall, time.Hour, "IRON_ORE", "ICE_WATER"), []string{"IRON_ORE"}, 0.5},
+		{"half the deposits, moderate", survey("a", models.SurveySizeModerate, time.Hour, "IRON_ORE", "ICE_WATER"), []string{"IRON_ORE"}, 1},
+		{"a third of the deposits, large", survey("a", models.SurveySizeLarge, time.Hour, "IRON_ORE", "ICE_WATER", "QUARTZ_SAND"), []string{"IRON_ORE"}, 1},
+		{"any of several targets", survey("a", models.SurveySizeLarge, time.Hour, "IRON_ORE", "ICE_WATER"), []string{"IRON_ORE", "ICE_WATER"}, 3},
+		{"unknown size weighs one", survey("a", "HUGE", time.Hour, "IRON_ORE"), []string{"IRON_ORE"}, 1},
+		{"no target deposits", survey("a", models.SurveySizeLarge, time.Hour, "ICE_WATER"), []string{"IRON_ORE"}, 0},
+	} {
+		if got := fleets.SurveyScore(tc.survey, tc.targets...); got != tc.want {
+			t.Errorf("%s: SurveyScore = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSurveyStoreBest(t *testing.T) {
+	clock := fake.NewManualClock(surveyNow)
+	s := fleets.NewSurveyStore(fleets.WithSurveyClock(clock.Now))
+	s.Add(
+		survey("small", models.SurveySizeSmall, time.Hour, "IRON_ORE", "ICE_WATER"),
+		// Equal scores for iron: the later expiration wins.
+		survey("soon", models.SurveySizeModerate, 10*time.Minute, "IRON_ORE", "ICE_WATER"),
+		survey("late", models.SurveySizeModerate, 20*time.Minute, "IRON_ORE", "ICE_WATER"),
+		survey("ice", models.SurveySizeLarge, time.Hour, "ICE_WATER"),
+	)
+	for _, tc := range []struct {
+		targets []string
+		want    string
+	}{
+		{[]string{"IRON_ORE"}, "late"},
+		{[]string{"ICE_WATER"}, "ice"},
+		{nil, "ice"},
+		{[]string{"GOLD_ORE"}, ""},
+	} {
+		got, ok := s.Best("X1-T-F", tc.targets...)
+		if ok != (tc.want != "") || got.Signature != tc.want {
+			t.Errorf("Best(%v) = %q, %v, want %q", tc.targets, got.Signature, ok, tc.want)
+		}
+	}
+
+	// Within the expiry margin of its expiration a survey is done with.
+	clock.Set(surveyNow.Add(20*time.Minute - fleets.SurveyExpiryMargin))
+	if got, ok := s.Best("X1-T-F", "IRON_ORE"); !ok || got.Signature != "small" {
+		t.Errorf("Best after the moderate surveys expire = %q, %v, want small", got.Signature, ok)
+	}
+}
+
+func TestSurveyStorePrune(t *testing.T) {
+	clock := fake.NewManualClock(surveyNow)
+	s := fleets.NewSurveyStore(fleets.WithSurveyClock(clock.Now))
+	s.Add(
+		survey("a", models.SurveySizeSmall, 10*time.Minute, "IRON_ORE"),
+		survey("b", models.SurveySizeSmall, 20*time.Minute, "IRON_ORE"),
+		survey("c", models.SurveySizeLarge, 30*time.Minute, "IRON_ORE"),
+	)
+	if n := s.Prune(); n != 0 {
+		t.Errorf("Prune before anything expires = %d", n)
+	}
+	clock.Advance(20 * time.Minute)
+	if n := s.Prune(); n != 2 {
+		t.Errorf("Prune = %d, want 2", n)
+	}
+	var left []string
+	for _, sv := range s.Surveys("X1-T-F") {
+		left = append(left, sv.Signature)
+	}
+	if !reflect.DeepEqual(left, []string{"c"}) {
+		t.Errorf("Surveys after pruning = %v, want [c]", left)
+	}
+}
+
+func TestSurveyStoreExtractFallsBack(t *testing.T) {
+	for _, code := range []int{models.ErrCodeSurveyExhausted, models.ErrCodeSurveyExpired} {
+		s := fleets.NewSurveyStore(fleets.WithSurveyClock(func() time.Time { return surveyNow }))
+		s.Add(
+			survey("best", models.SurveySizeLarge, time.Hour, "IRON_ORE"),
+			survey("next", models.SurveySizeSmall, time.Hour, "IRON_ORE"),
+		)
+		m := mocks.NewFleetsClient()
+		nav := &fleets.GetShipNavResponse{}
+		nav.Nav.WaypointSymbol = "X1-T-F"
+		m.OnGetShipNav().Return(nav, nil)
+		turnedDown := &models.APIError{Code: code}
+		m.OnExtractResource().When(func(r *fleets.ExtractResourceRequest) bool { return r.Survey != nil }).
+			Times(2).Return(nil, turnedDown)
+		extracted := &fleets.ExtractResourceResponse{}
+		m.OnExtractResource().Return(extracted, nil)
+
+		resp, err := s.Extract(context.Background(), m, "token", "SHIP-1", "IRON_ORE")
+		if err != nil || resp != extracted {
+			t.Fatalf("code %d: Extract = %v, %v", code, resp, err)
+		}
+		var used []string
+		for _, r := range m.OnExtractResource().Calls() {
+			if r.Survey == nil {
+				used = append(used, "")
+			} else {
+				used = append(used, r.Survey.Signature)
+			}
+		}
+		if want := []string{"best", "next", ""}; !reflect.DeepEqual(used, want) {
+			t.Errorf("code %d: surveys used = %q, want %q", code, used, want)
+		}
+		if left := s.Surveys("X1-T-F"); len(left) != 0 {
+			t.Errorf("code %d: surveys left = %v, want both discarded", code, left)
+		}
+	}
+}
+
+func TestSurveyStoreExtractKeepsSurveyOnOtherErrors(t *testing.T) {
+	s := fleets.NewSurveyStore(fleets.WithSurveyClock(func() time.Time { return surveyNow }))
+	s.Add(survey("best", models.SurveySizeLarge, time.Hour, "IRON_ORE"))
+	m := mocks.NewFleetsClient()
+	nav := &fleets.GetShipNavResponse{}
+	nav.Nav.WaypointSymbol = "X1-T-F"
+	m.OnGetShipNav().Return(nav, nil)
+	cooldown := &models.APIError{Code: models.ErrCodeCooldownConflict}
+	m.OnExtractResource().Fail(cooldown)
+
+	if _, err := s.Extract(context.Background(), m, "token", "SHIP-1", "IRON_ORE"); !errors.Is(err, cooldown) {
+		t.Errorf("Extract err = %v, want the cooldown", err)
+	}
+	if n := len(m.OnExtractResource().Calls()); n != 1 {
+		t.Errorf("ExtractResource called %d times, want 1", n)
+	}
+	if _, ok := s.Best("X1-T-F", "IRON_ORE"); !ok {
+		t.Error("survey dropped on an error that says nothing about it")
+	}
+}
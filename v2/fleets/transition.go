@@ -0,0 +1,62 @@
+package fleets
+
+import (
+	"context"
+	"spacetradersgo/v2/models"
+)
+
+// ShipLookup is implemented by clients that know a ship's state without
+// asking the API: FleetState, and clients wrapping one that forward Ship to
+// it.
+type ShipLookup interface {
+	Ship(shipID string) (ShipState, bool)
+}
+
+// EnsureDocked docks the ship unless it is docked already and returns its
+// nav. When client is a ShipLookup holding the ship, such as a FleetState,
+// the mirrored status is trusted instead of fetching the nav.
+func EnsureDocked(ctx context.Context, client FleetsClient, token, shipID string) (models.ShipNav, error) {
+	return ensureStatus(ctx, client, token, shipID, models.ShipNavStatusDocked)
+}
+
+// EnsureInOrbit puts the ship in orbit unless it is in orbit already and
+// returns its nav, like EnsureDocked.
+func EnsureInOrbit(ctx context.Context, client FleetsClient, token, shipID string) (models.ShipNav, error) {
+	return ensureStatus(ctx, client, token, shipID, models.ShipNavStatusInOrbit)
+}
+
+func ensureStatus(ctx context.Context, client FleetsClient, token, shipID string, status models.ShipNavStatus) (models.ShipNav, error) {
+	nav, err := currentNav(ctx, client, token, shipID)
+	if err != nil {
+		return models.ShipNav{}, err
+	}
+	if nav.Status == status {
+		return nav, nil
+	}
+
+	if status == models.ShipNavStatusDocked {
+		resp, err := client.DockShip(ctx, &DockShipRequest{Token: token, ShipID: shipID})
+		if err != nil {
+			return nav, err
+		}
+		return resp.Data.Nav, nil
+	}
+	resp, err := client.OrbitShip(ctx, &OrbitShipRequest{Token: token, ShipID: shipID})
+	if err != nil {
+		return nav, err
+	}
+	return resp.Data.Nav, nil
+}
+
+func currentNav(ctx context.Context, client FleetsClient, token, shipID string) (models.ShipNav, error) {
+	if lookup, ok := client.(ShipLookup); ok {
+		if st, ok := lookup.Ship(shipID); ok {
+			return st.Ship.Nav, nil
+		}
+	}
+	resp, err := client.GetShipNav(ctx, &GetShipNavRequest{Token: token, ShipID: shipID})
+	if err != nil {
+		return models.ShipNav{}, err
+	}
+	return resp.Nav, nil
+}
@@ -0,0 +1,635 @@
+// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.
+
+package fleets
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/models"
+	"strconv"
+)
+
+type FleetsClient interface {
+	// Retrieve all of your ships.
+	ListShips(ctx context.Context, req *ListShipsRequest) (*ListShipsResponse, error)
+	// Retrieve the details of your ship.
+	GetShip(ctx context.Context, req *GetShipRequest) (*GetShipResponse, error)
+	// Retrieve the cargo of your ship.
+	GetShipCargo(ctx context.Context, req *GetShipCargoRequest) (*GetShipCargoResponse, error)
+	// Attempt to move your ship into orbit at it's current location.
+	OrbitShip(ctx context.Context, req *OrbitShipRequest) (*OrbitShipResponse, error)
+	// Command a ship to chart the current waypoint.
+	CreateChart(ctx context.Context, req *CreateChartRequest) (*CreateChartResponse, error)
+	// Retrieve the details of your ship's reactor cooldown.
+	GetShipCooldown(ctx context.Context, req *GetShipCooldownRequest) (*GetShipCooldownResponse, error)
+	// Attempt to dock your ship at it's current location.
+	DockShip(ctx context.Context, req *DockShipRequest) (*DockShipResponse, error)
+	// If you want to target specific yields for an extraction, you can survey a waypoint, such as an asteroid field, and send the survey in the body of the extract request.
+	CreateSurvey(ctx context.Context, req *CreateSurveyRequest) (*CreateSurveyResponse, error)
+	// Extract resources from the waypoint into your ship.
+	ExtractResource(ctx context.Context, req *ExtractResourceRequest) (*ExtractResourceResponse, error)
+	// Jettison cargo from your ship's cargo hold.
+	Jettison(ctx context.Context, req *JettisonRequest) (*JettisonResponse, error)
+	// Jump your ship instantly to a target system.
+	JumpShip(ctx context.Context, req *JumpShipRequest) (*JumpShipResponse, error)
+	// Navigate to a target destination.
+	NavigateShip(ctx context.Context, req *NavigateShipRequest) (*NavigateShipResponse, error)
+	// Get the current nav status of a ship.
+	GetShipNav(ctx context.Context, req *GetShipNavRequest) (*GetShipNavResponse, error)
+	// Update the nav configuration of a ship.
+	PatchShipNav(ctx context.Context, req *PatchShipNavRequest) (*PatchShipNavResponse, error)
+	// Warp your ship to a target destination in another system.
+	WarpShip(ctx context.Context, req *WarpShipRequest) (*WarpShipResponse, error)
+	// Sell cargo.
+	SellCargo(ctx context.Context, req *SellCargoRequest) (*SellCargoResponse, error)
+	// Purchase cargo.
+	PurchaseCargo(ctx context.Context, req *PurchaseCargoRequest) (*PurchaseCargoResponse, error)
+	// Refuel your ship from the local market.
+	RefuelShip(ctx context.Context, req *RefuelShipRequest) (*RefuelShipResponse, error)
+	// Negotiate a new contract with the HQ.
+	NegotiateContract(ctx context.Context, req *NegotiateContractRequest) (*NegotiateContractResponse, error)
+}
+
+type ListShipsRequest struct {
+	Token string
+	// What entry offset to request
+	// >= 1
+	Page int
+	// How many entries to return per page
+	// >= 1 <= 20
+	NumPerPage int
+}
+type ListShipsResponse struct {
+	models.RawResponse
+
+	Ships []models.Ship `json:"data"`
+	Meta  models.Meta   `json:"meta"`
+}
+
+type GetShipRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type GetShipResponse struct {
+	models.RawResponse
+
+	Ship models.Ship `json:"data"`
+}
+
+type GetShipCargoRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type GetShipCargoResponse struct {
+	models.RawResponse
+
+	Cargo models.ShipCargo `json:"data"`
+}
+
+type OrbitShipRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type OrbitShipResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Nav models.ShipNav `json:"nav"`
+	} `json:"data"`
+}
+
+type CreateChartRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type CreateChartResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Chart    models.Chart    `json:"chart"`
+		Waypoint models.Waypoint `json:"waypoint"`
+	} `json:"data"`
+}
+
+type DockShipRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type DockShipResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Nav models.ShipNav `json:"nav"`
+	} `json:"data"`
+}
+
+type CreateSurveyRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type CreateSurveyResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Cooldown models.Cooldown `json:"cooldown"`
+		Surveys  []models.Survey `json:"surveys"`
+	} `json:"data"`
+}
+
+type ExtractResourceRequest struct {
+	Token string `json:"-"`
+	// The symbol of the ship
+	ShipID string         `json:"-"`
+	Survey *models.Survey `json:"survey,omitempty"`
+}
+type ExtractResourceResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Cooldown   models.Cooldown   `json:"cooldown"`
+		Extraction models.Extraction `json:"extraction"`
+		Cargo      models.ShipCargo  `json:"cargo"`
+	} `json:"data"`
+}
+
+type JettisonRequest struct {
+	Token string `json:"-"`
+	// The symbol of the ship
+	ShipID string `json:"-"`
+	Symbol string `json:"symbol"`
+	// >= 1
+	Units int `json:"units"`
+}
+type JettisonResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Cargo models.ShipCargo `json:"cargo"`
+	} `json:"data"`
+}
+
+type JumpShipRequest struct {
+	Token string `json:"-"`
+	// The symbol of the ship
+	ShipID string `json:"-"`
+	// SystemSymbol The system symbol to jump to.
+	SystemSymbol string `json:"systemSymbol"`
+}
+type JumpShipResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Cooldown models.Cooldown `json:"cooldown"`
+		Nav      models.ShipNav  `json:"nav"`
+	} `json:"data"`
+}
+
+type NavigateShipRequest struct {
+	Token string `json:"-"`
+	// The symbol of the ship
+	ShipID string `json:"-"`
+	// WaypointSymbol The target destination.
+	WaypointSymbol string `json:"waypointSymbol"`
+}
+type NavigateShipResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Fuel models.ShipFuel `json:"fuel"`
+		Nav  models.ShipNav  `json:"nav"`
+	} `json:"data"`
+}
+
+type GetShipNavRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type GetShipNavResponse struct {
+	models.RawResponse
+
+	Nav models.ShipNav `json:"data"`
+}
+
+type PatchShipNavRequest struct {
+	Token string `json:"-"`
+	// The symbol of the ship
+	ShipID     string                   `json:"-"`
+	FlightMode models.ShipNavFlightMode `json:"flightMode,omitempty"`
+}
+type PatchShipNavResponse struct {
+	models.RawResponse
+
+	Nav models.ShipNav `json:"data"`
+}
+
+type WarpShipRequest struct {
+	Token string `json:"-"`
+	// The symbol of the ship
+	ShipID string `json:"-"`
+	// WaypointSymbol The target destination.
+	WaypointSymbol string `json:"waypointSymbol"`
+}
+type WarpShipResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Fuel models.ShipFuel `json:"fuel"`
+		Nav  models.ShipNav  `json:"nav"`
+	} `json:"data"`
+}
+
+type SellCargoRequest struct {
+	Token string `json:"-"`
+	// The symbol of the ship
+	ShipID string `json:"-"`
+	Symbol string `json:"symbol"`
+	Units  int    `json:"units"`
+}
+type SellCargoResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Agent       models.Agent             `json:"agent"`
+		Cargo       models.ShipCargo         `json:"cargo"`
+		Transaction models.MarketTransaction `json:"transaction"`
+	} `json:"data"`
+}
+
+type PurchaseCargoRequest struct {
+	Token string `json:"-"`
+	// The symbol of the ship
+	ShipID string `json:"-"`
+	Symbol string `json:"symbol"`
+	Units  int    `json:"units"`
+}
+type PurchaseCargoResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Agent       models.Agent             `json:"agent"`
+		Cargo       models.ShipCargo         `json:"cargo"`
+		Transaction models.MarketTransaction `json:"transaction"`
+	} `json:"data"`
+}
+
+type RefuelShipRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type RefuelShipResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Agent       models.Agent             `json:"agent"`
+		Fuel        models.ShipFuel          `json:"fuel"`
+		Transaction models.MarketTransaction `json:"transaction"`
+	} `json:"data"`
+}
+
+type NegotiateContractRequest struct {
+	Token string
+	// The symbol of the ship
+	ShipID string
+}
+type NegotiateContractResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Contract models.Contract `json:"contract"`
+	} `json:"data"`
+}
+
+// Retrieve all of your ships.
+func (c *fleetClient) ListShips(ctx context.Context, req *ListShipsRequest) (*ListShipsResponse, error) {
+	query := url.Values{}
+	if req.Page != 0 {
+		query.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.NumPerPage != 0 {
+		query.Set("limit", strconv.Itoa(req.NumPerPage))
+	}
+
+	resp := &ListShipsResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "ListShips",
+		Method:    http.MethodGet,
+		Path:      "/my/ships",
+		Query:     query,
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Retrieve the details of your ship.
+func (c *fleetClient) GetShip(ctx context.Context, req *GetShipRequest) (*GetShipResponse, error) {
+	resp := &GetShipResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetShip",
+		Method:    http.MethodGet,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID),
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Retrieve the cargo of your ship.
+func (c *fleetClient) GetShipCargo(ctx context.Context, req *GetShipCargoRequest) (*GetShipCargoResponse, error) {
+	resp := &GetShipCargoResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetShipCargo",
+		Method:    http.MethodGet,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/cargo",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Attempt to move your ship into orbit at it's current location. The request will only succeed if your ship is capable of moving into orbit at the time of the request.
+//
+// The endpoint is idempotent - successive calls will succeed even if the ship is already in orbit.
+func (c *fleetClient) OrbitShip(ctx context.Context, req *OrbitShipRequest) (*OrbitShipResponse, error) {
+	resp := &OrbitShipResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "OrbitShip",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/orbit",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Command a ship to chart the current waypoint.
+func (c *fleetClient) CreateChart(ctx context.Context, req *CreateChartRequest) (*CreateChartResponse, error) {
+	resp := &CreateChartResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "CreateChart",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/chart",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Attempt to dock your ship at it's current location. Docking will only succeed if the waypoint is a dockable location, and your ship is capable of docking at the time of the request.
+//
+// The endpoint is idempotent - successive calls will succeed even if the ship is already docked.
+func (c *fleetClient) DockShip(ctx context.Context, req *DockShipRequest) (*DockShipResponse, error) {
+	resp := &DockShipResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "DockShip",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/dock",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// If you want to target specific yields for an extraction, you can survey a waypoint, such as an asteroid field, and send the survey in the body of the extract request. Each survey may have multiple deposits, and if a symbol shows up more than once, that indicates a higher chance of extracting that resource.
+//
+// Your ship will enter a cooldown between consecutive survey requests. Surveys will eventually expire after a period of time. Multiple ships can use the same survey for extraction.
+func (c *fleetClient) CreateSurvey(ctx context.Context, req *CreateSurveyRequest) (*CreateSurveyResponse, error) {
+	resp := &CreateSurveyResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "CreateSurvey",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/survey",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Extract resources from the waypoint into your ship. Send an optional survey as the payload to target specific yields.
+func (c *fleetClient) ExtractResource(ctx context.Context, req *ExtractResourceRequest) (*ExtractResourceResponse, error) {
+	resp := &ExtractResourceResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "ExtractResource",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/extract",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Jettison cargo from your ship's cargo hold.
+func (c *fleetClient) Jettison(ctx context.Context, req *JettisonRequest) (*JettisonResponse, error) {
+	resp := &JettisonResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "Jettison",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/jettison",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Jump your ship instantly to a target system. When used while in orbit or docked to a jump gate waypoint, any ship can use this command. When used elsewhere, jumping requires a jump drive unit and consumes a unit of antimatter (which needs to be in your cargo).
+func (c *fleetClient) JumpShip(ctx context.Context, req *JumpShipRequest) (*JumpShipResponse, error) {
+	resp := &JumpShipResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "JumpShip",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/jump",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Navigate to a target destination. The destination must be located within the same system as the ship. Navigating will consume the necessary fuel and supplies from the ship's manifest, and will pay out crew wages from the agent's account.
+//
+// The returned response will detail the route information including the expected time of arrival. Most ship actions are unavailable until the ship has arrived at it's destination.
+//
+// To travel between systems, see the ship's warp or jump actions.
+func (c *fleetClient) NavigateShip(ctx context.Context, req *NavigateShipRequest) (*NavigateShipResponse, error) {
+	resp := &NavigateShipResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "NavigateShip",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/navigate",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get the current nav status of a ship.
+func (c *fleetClient) GetShipNav(ctx context.Context, req *GetShipNavRequest) (*GetShipNavResponse, error) {
+	resp := &GetShipNavResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetShipNav",
+		Method:    http.MethodGet,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/nav",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Update the nav configuration of a ship.
+//
+// Currently only supports configuring the Flight Mode of the ship, which affects its speed and fuel consumption.
+func (c *fleetClient) PatchShipNav(ctx context.Context, req *PatchShipNavRequest) (*PatchShipNavResponse, error) {
+	resp := &PatchShipNavResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "PatchShipNav",
+		Method:    http.MethodPatch,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/nav",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Warp your ship to a target destination in another system. Warping will consume the necessary fuel and supplies from the ship's manifest, and will pay out crew wages from the agent's account.
+//
+// The returned response will detail the route information including the expected time of arrival. Most ship actions are unavailable until the ship has arrived at it's destination.
+func (c *fleetClient) WarpShip(ctx context.Context, req *WarpShipRequest) (*WarpShipResponse, error) {
+	resp := &WarpShipResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "WarpShip",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/warp",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Sell cargo.
+func (c *fleetClient) SellCargo(ctx context.Context, req *SellCargoRequest) (*SellCargoResponse, error) {
+	resp := &SellCargoResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "SellCargo",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/sell",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Purchase cargo.
+func (c *fleetClient) PurchaseCargo(ctx context.Context, req *PurchaseCargoRequest) (*PurchaseCargoResponse, error) {
+	resp := &PurchaseCargoResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "PurchaseCargo",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/purchase",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Refuel your ship from the local market.
+func (c *fleetClient) RefuelShip(ctx context.Context, req *RefuelShipRequest) (*RefuelShipResponse, error) {
+	resp := &RefuelShipResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "RefuelShip",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/refuel",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Negotiate a new contract with the HQ.
+//
+// In order to negotiate a new contract, an agent must not have ongoing or offered contracts over the allowed maximum amount. Currently the maximum contracts an agent can have at a time is 1.
+//
+// Once a contract is negotiated, it is added to the list of contracts offered to the agent, which the agent can then accept.
+//
+// The ship must be present at a faction's HQ waypoint to negotiate a contract with that faction.
+func (c *fleetClient) NegotiateContract(ctx context.Context, req *NegotiateContractRequest) (*NegotiateContractResponse, error) {
+	resp := &NegotiateContractResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "NegotiateContract",
+		Method:    http.MethodPost,
+		Path:      "/my/ships/" + url.PathEscape(req.ShipID) + "/negotiate/contract",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,3 @@
+package v2
+
+//go:generate go run ./internal/gen -spec openapi/spacetraders.json -out .
@@ -0,0 +1,48 @@
+package apispec
+
+import (
+	"strings"
+	"unicode"
+)
+
+var initialisms = map[string]string{
+	"id":  "ID",
+	"url": "URL",
+}
+
+// GoName turns a camelCase or kebab-case spec identifier into an exported
+// Go identifier.
+func GoName(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		if v, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(v)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+func splitWords(s string) []string {
+	var words []string
+	var cur []rune
+	for _, r := range s {
+		switch {
+		case r == '-' || r == '_' || r == ' ':
+			if len(cur) > 0 {
+				words = append(words, string(cur))
+			}
+			cur = nil
+		case unicode.IsUpper(r) && len(cur) > 0:
+			words = append(words, string(cur))
+			cur = []rune{r}
+		default:
+			cur = append(cur, r)
+		}
+	}
+	if len(cur) > 0 {
+		words = append(words, string(cur))
+	}
+	return words
+}
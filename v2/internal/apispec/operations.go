@@ -0,0 +1,106 @@
+package apispec
+
+import "fmt"
+
+// The spec does not say which Go package an operation belongs to or what
+// the SDK calls it, so those decisions live here. Anything not listed
+// falls back to the tag mapping and a name derived from the operationId.
+
+// Tags maps spec tags onto SDK package names.
+var Tags = map[string]string{
+	"Agents":    "agents",
+	"Contracts": "contracts",
+	"Factions":  "factions",
+	"Fleet":     "fleets",
+	"Systems":   "systems",
+	"Status":    "status",
+}
+
+type OperationConfig struct {
+	// Name is the Go method name.
+	Name string
+	// Package is the SDK package; set it to override the tag mapping.
+	Package string
+	// DataField names the response field holding a $ref "data" payload
+	// when the schema name isn't what callers expect.
+	DataField string
+	// Manual operations get an interface method but no request, response
+	// or implementation; those are hand-written in the package.
+	Manual bool
+}
+
+// Operations is keyed by operationId.
+var Operations = map[string]OperationConfig{
+	"get-status":           {Name: "GetStatus"},
+	"register":             {Name: "NewAgent"},
+	"get-my-agent":         {Name: "GetAgent"},
+	"get-factions":         {Name: "ListFactions"},
+	"get-faction":          {Name: "GetFaction"},
+	"get-contracts":        {Name: "ListContracts"},
+	"get-contract":         {Name: "GetContract"},
+	"accept-contract":      {Name: "AcceptContract"},
+	"deliver-contract":     {Name: "DeliverContract"},
+	"fulfill-contract":     {Name: "FulfillContract"},
+	"get-my-ships":         {Name: "ListShips"},
+	"purchase-ship":        {Name: "PurchaseShip", Package: "systems"},
+	"get-my-ship":          {Name: "GetShip"},
+	"get-my-ship-cargo":    {Name: "GetShipCargo", DataField: "Cargo"},
+	"orbit-ship":           {Name: "OrbitShip"},
+	"create-chart":         {Name: "CreateChart"},
+	"get-ship-cooldown":    {Name: "GetShipCooldown", Manual: true},
+	"dock-ship":            {Name: "DockShip"},
+	"create-survey":        {Name: "CreateSurvey"},
+	"extract-resources":    {Name: "ExtractResource"},
+	"jettison":             {Name: "Jettison"},
+	"jump-ship":            {Name: "JumpShip"},
+	"navigate-ship":        {Name: "NavigateShip"},
+	"get-ship-nav":         {Name: "GetShipNav", DataField: "Nav"},
+	"patch-ship-nav":       {Name: "PatchShipNav", DataField: "Nav"},
+	"warp-ship":            {Name: "WarpShip"},
+	"sell-cargo":           {Name: "SellCargo"},
+	"purchase-cargo":       {Name: "PurchaseCargo"},
+	"refuel-ship":          {Name: "RefuelShip"},
+	"negotiateContract":    {Name: "NegotiateContract"},
+	"get-systems":          {Name: "ListSystems"},
+	"get-system":           {Name: "GetSystem"},
+	"get-system-waypoints": {Name: "ListWaypoints"},
+	"get-waypoint":         {Name: "GetWaypoint"},
+	"get-market":           {Name: "GetMarket"},
+	"get-shipyard":         {Name: "GetShipyard"},
+	"get-jump-gate":        {Name: "GetJumpGate"},
+}
+
+// ParamNames maps path and query parameters onto the request field names
+// the SDK has always used.
+var ParamNames = map[string]string{
+	"shipSymbol":     "ShipID",
+	"systemSymbol":   "SystemID",
+	"waypointSymbol": "WaypointID",
+	"contractId":     "ContractID",
+	"factionSymbol":  "FactionSymbol",
+	"limit":          "NumPerPage",
+	"page":           "Page",
+}
+
+// Endpoint resolves the SDK package and method name for op.
+func Endpoint(op *Operation) (OperationConfig, error) {
+	cfg := Operations[op.OperationID]
+	if cfg.Name == "" {
+		cfg.Name = GoName(op.OperationID)
+	}
+	if cfg.Package == "" && len(op.Tags) > 0 {
+		cfg.Package = Tags[op.Tags[0]]
+	}
+	if cfg.Package == "" {
+		return cfg, fmt.Errorf("operation %s: no package for tags %v", op.OperationID, op.Tags)
+	}
+	return cfg, nil
+}
+
+// ParamName returns the request field name for a path or query parameter.
+func ParamName(p *Parameter) string {
+	if n, ok := ParamNames[p.Name]; ok {
+		return n
+	}
+	return GoName(p.Name)
+}
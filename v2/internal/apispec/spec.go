@@ -0,0 +1,207 @@
+// Package apispec loads the vendored SpaceTraders OpenAPI document and
+// records how its operations map onto the SDK. It is shared by the code
+// generator and the spec drift checker so both agree on names.
+package apispec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Ordered is a JSON object that remembers the order its keys appeared in,
+// so generated code follows the layout of the spec rather than map order.
+type Ordered[T any] struct {
+	Keys   []string
+	Values map[string]T
+}
+
+func (o *Ordered[T]) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected object, got %v", tok)
+	}
+	o.Values = map[string]T{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		o.Keys = append(o.Keys, key)
+		o.Values[key] = v
+	}
+	_, err = dec.Token()
+	return err
+}
+
+type Spec struct {
+	Paths      Ordered[Ordered[*Operation]] `json:"paths"`
+	Components struct {
+		Schemas Ordered[*Schema] `json:"schemas"`
+	} `json:"components"`
+}
+
+type Operation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary"`
+	Description string                 `json:"description"`
+	Tags        []string               `json:"tags"`
+	Parameters  []*Parameter           `json:"parameters"`
+	RequestBody *Content               `json:"requestBody"`
+	Responses   Ordered[*Content]      `json:"responses"`
+	Security    *[]map[string][]string `json:"security"`
+
+	// Filled in while loading.
+	Path   string `json:"-"`
+	Method string `json:"-"`
+}
+
+// Public reports whether the operation overrides the global security
+// requirement with an empty one, i.e. needs no bearer token.
+func (o *Operation) Public() bool {
+	return o.Security != nil && len(*o.Security) == 0
+}
+
+// Success returns the status code and schema of the first 2xx response
+// that carries a body.
+func (o *Operation) Success() (string, *Schema) {
+	for _, code := range o.Responses.Keys {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if s := o.Responses.Values[code].Schema(); s != nil {
+			return code, s
+		}
+	}
+	return "", nil
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Content struct {
+	Description string `json:"description"`
+	Content     map[string]struct {
+		Schema *Schema `json:"schema"`
+	} `json:"content"`
+}
+
+func (c *Content) Schema() *Schema {
+	if c == nil {
+		return nil
+	}
+	return c.Content["application/json"].Schema
+}
+
+type Schema struct {
+	Ref         string           `json:"$ref"`
+	Type        string           `json:"type"`
+	Format      string           `json:"format"`
+	Description string           `json:"description"`
+	Enum        []string         `json:"enum"`
+	Properties  Ordered[*Schema] `json:"properties"`
+	Required    []string         `json:"required"`
+	Items       *Schema          `json:"items"`
+	MinLength   *int             `json:"minLength"`
+	MaxLength   *int             `json:"maxLength"`
+	Minimum     *float64         `json:"minimum"`
+	Maximum     *float64         `json:"maximum"`
+}
+
+// RefName returns the component name a $ref points at.
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+}
+
+func (s *Schema) IsRequired(prop string) bool {
+	for _, r := range s.Required {
+		if r == prop {
+			return true
+		}
+	}
+	return false
+}
+
+// Constraints renders the validation keywords in the same shorthand the
+// hand-written types used, e.g. ">= 1 characters" or ">= 1 <= 20".
+func (s *Schema) Constraints() string {
+	var parts []string
+	if s.MinLength != nil {
+		parts = append(parts, fmt.Sprintf(">= %d characters", *s.MinLength))
+	}
+	if s.MaxLength != nil {
+		parts = append(parts, fmt.Sprintf("<= %d characters", *s.MaxLength))
+	}
+	if s.Minimum != nil {
+		parts = append(parts, fmt.Sprintf(">= %v", *s.Minimum))
+	}
+	if s.Maximum != nil {
+		parts = append(parts, fmt.Sprintf("<= %v", *s.Maximum))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Load reads and parses the OpenAPI document at path.
+func Load(path string) (*Spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Parse parses an OpenAPI document.
+func Parse(b []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := json.Unmarshal(b, spec); err != nil {
+		return nil, err
+	}
+	for _, p := range spec.Paths.Keys {
+		item := spec.Paths.Values[p]
+		for _, m := range item.Keys {
+			op := item.Values[m]
+			op.Path = p
+			op.Method = m
+		}
+	}
+	return spec, nil
+}
+
+// Operations returns every operation in spec order.
+func (s *Spec) Operations() []*Operation {
+	var ops []*Operation
+	for _, p := range s.Paths.Keys {
+		item := s.Paths.Values[p]
+		for _, m := range item.Keys {
+			ops = append(ops, item.Values[m])
+		}
+	}
+	return ops
+}
+
+func (s *Spec) Resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.Components.Schemas.Values[schema.RefName()]
+	}
+	return schema
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"spacetradersgo/v2/internal/apispec"
+	"strings"
+)
+
+// endpoint is an operation together with the SDK-facing names decided by
+// the config.
+type endpoint struct {
+	*apispec.Operation
+	apispec.OperationConfig
+}
+
+func endpoints(spec *apispec.Spec) (map[string][]endpoint, error) {
+	byPkg := map[string][]endpoint{}
+	for _, op := range spec.Operations() {
+		cfg, err := apispec.Endpoint(op)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := packages[cfg.Package]; !ok {
+			return nil, fmt.Errorf("operation %s: unknown package %q", op.OperationID, cfg.Package)
+		}
+		byPkg[cfg.Package] = append(byPkg[cfg.Package], endpoint{Operation: op, OperationConfig: cfg})
+	}
+	return byPkg, nil
+}
+
+// firstSentence returns the leading sentence of a description, used for
+// the one-line interface comments.
+func firstSentence(s string) string {
+	s = strings.TrimSpace(strings.SplitN(s, "\n", 2)[0])
+	if i := strings.Index(s, ". "); i >= 0 {
+		s = s[:i+1]
+	}
+	return s
+}
+
+func genClient(spec *apispec.Spec, pkg string, eps []endpoint) string {
+	cfg := packages[pkg]
+	t := &typer{spec: spec, qual: "models."}
+
+	var decls, methods strings.Builder
+	var usesURL, usesStrconv, usesRest bool
+
+	for _, ep := range eps {
+		if ep.Manual {
+			continue
+		}
+		usesRest = true
+		params, body := ep.Parameters, ep.RequestBody.Schema()
+
+		// Request.
+		fmt.Fprintf(&decls, "type %sRequest struct {\n", ep.Name)
+		skip := ""
+		if body != nil {
+			skip = " `json:\"-\"`"
+		}
+		if !ep.Public() {
+			fmt.Fprintf(&decls, "Token string%s\n", skip)
+		}
+		for _, p := range params {
+			if p.Description != "" || p.Schema.Constraints() != "" {
+				decls.WriteString(comment("", strings.TrimSpace(p.Description+"\n"+p.Schema.Constraints())))
+			}
+			fmt.Fprintf(&decls, "%s %s%s\n", apispec.ParamName(p), t.typeOf(p.Schema, ""), skip)
+		}
+		if body != nil {
+			t.fields(&decls, body, ep.Name)
+		}
+		decls.WriteString("}\n")
+
+		// Response.
+		_, success := ep.Success()
+		fmt.Fprintf(&decls, "type %sResponse struct {\n", ep.Name)
+		decls.WriteString("models.RawResponse\n\n")
+		t.usesModels = true
+		if success != nil {
+			responseFields(&decls, t, ep, success)
+		}
+		decls.WriteString("}\n\n")
+
+		// Implementation.
+		var path strings.Builder
+		path.WriteString(`"`)
+		for _, seg := range strings.Split(strings.TrimPrefix(ep.Path, "/"), "/") {
+			path.WriteString("/")
+			if strings.HasPrefix(seg, "{") {
+				usesURL = true
+				name := strings.Trim(seg, "{}")
+				fmt.Fprintf(&path, `" + url.PathEscape(req.%s) + "`, paramNameFor(params, name))
+				continue
+			}
+			path.WriteString(seg)
+		}
+		path.WriteString(`"`)
+		pathExpr := strings.TrimSuffix(path.String(), ` + ""`)
+
+		methods.WriteString(comment("", ep.Description))
+		fmt.Fprintf(&methods, "func (c *%s) %s(ctx context.Context, req *%sRequest) (*%sResponse, error) {\n", cfg.Receiver, ep.Name, ep.Name, ep.Name)
+		var query []*apispec.Parameter
+		for _, p := range params {
+			if p.In == "query" {
+				query = append(query, p)
+			}
+		}
+		if len(query) > 0 {
+			usesURL = true
+			methods.WriteString("query := url.Values{}\n")
+			for _, p := range query {
+				f := apispec.ParamName(p)
+				if p.Schema.Type == "integer" {
+					usesStrconv = true
+					fmt.Fprintf(&methods, "if req.%s != 0 {\nquery.Set(%q, strconv.Itoa(req.%s))\n}\n", f, p.Name, f)
+				} else {
+					fmt.Fprintf(&methods, "if req.%s != \"\" {\nquery.Set(%q, req.%s)\n}\n", f, p.Name, f)
+				}
+			}
+			methods.WriteString("\n")
+		}
+		fmt.Fprintf(&methods, "resp := &%sResponse{}\n", ep.Name)
+		methods.WriteString("err := c.do(ctx, &rest.Request{\n")
+		fmt.Fprintf(&methods, "Operation: %q,\n", ep.Name)
+		fmt.Fprintf(&methods, "Method: http.Method%s,\n", apispec.GoName(ep.Method))
+		fmt.Fprintf(&methods, "Path: %s,\n", pathExpr)
+		if len(query) > 0 {
+			methods.WriteString("Query: query,\n")
+		}
+		if !ep.Public() {
+			methods.WriteString("Token: req.Token,\n")
+		}
+		if body != nil {
+			methods.WriteString("Body: req,\n")
+		}
+		methods.WriteString("}, resp)\n")
+		methods.WriteString("if err != nil {\nreturn nil, err\n}\n\nreturn resp, nil\n}\n\n")
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n\"context\"\n")
+	if usesRest {
+		b.WriteString("\"net/http\"\n")
+	}
+	if usesURL {
+		b.WriteString("\"net/url\"\n")
+	}
+	if t.usesModels {
+		b.WriteString("\"spacetradersgo/v2/models\"\n")
+	}
+	if usesRest {
+		b.WriteString("\"spacetradersgo/v2/internal/rest\"\n")
+	}
+	if usesStrconv {
+		b.WriteString("\"strconv\"\n")
+	}
+	if t.usesTime {
+		b.WriteString("\"time\"\n")
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "type %s interface {\n", cfg.Interface)
+	for _, ep := range eps {
+		b.WriteString(comment("", firstSentence(ep.Description)))
+		fmt.Fprintf(&b, "%s(ctx context.Context, req *%sRequest) (*%sResponse, error)\n", ep.Name, ep.Name, ep.Name)
+	}
+	b.WriteString("}\n\n")
+	b.WriteString(decls.String())
+	b.WriteString(methods.String())
+	return b.String()
+}
+
+func responseFields(b *strings.Builder, t *typer, ep endpoint, s *apispec.Schema) {
+	data, ok := s.Properties.Values["data"]
+	if !ok {
+		t.fields(b, s, ep.Name)
+		return
+	}
+	switch {
+	case data.Ref != "":
+		name := ep.DataField
+		if name == "" {
+			name = data.RefName()
+		}
+		fmt.Fprintf(b, "%s %s `json:\"data\"`\n", name, t.typeOf(data, ""))
+	case data.Type == "array" && data.Items.Ref != "":
+		fmt.Fprintf(b, "%ss %s `json:\"data\"`\n", data.Items.RefName(), t.typeOf(data, ""))
+	default:
+		fmt.Fprintf(b, "Data %s `json:\"data\"`\n", t.typeOf(data, ep.Name))
+	}
+	for _, prop := range s.Properties.Keys {
+		if prop != "data" {
+			t.field(b, s, ep.Name, prop)
+		}
+	}
+}
+
+func paramNameFor(params []*apispec.Parameter, name string) string {
+	for _, p := range params {
+		if p.Name == name {
+			return apispec.ParamName(p)
+		}
+	}
+	panic("gen: path parameter " + name + " is not declared")
+}
+
+func sortedPackages(byPkg map[string][]endpoint) []string {
+	var pkgs []string
+	for pkg := range byPkg {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return order(pkgs[i]) < order(pkgs[j]) })
+	return pkgs
+}
+
+func order(pkg string) int {
+	for i, p := range packageOrder {
+		if p == pkg {
+			return i
+		}
+	}
+	return len(packageOrder)
+}
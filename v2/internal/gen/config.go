@@ -0,0 +1,26 @@
+package main
+
+// Which directory and receiver type each SDK package uses. Operation names
+// and package assignments live in internal/apispec.
+
+type pkgConfig struct {
+	// Dir is the package directory relative to the v2 root.
+	Dir string
+	// Interface is the name of the exported client interface.
+	Interface string
+	// Receiver is the unexported type implementing Interface, declared
+	// in the package's hand-written client file.
+	Receiver string
+}
+
+var packages = map[string]pkgConfig{
+	"agents":    {Dir: "agents", Interface: "AgentsClient", Receiver: "agentsClient"},
+	"contracts": {Dir: "contracts", Interface: "ContractsClient", Receiver: "contractsClient"},
+	"factions":  {Dir: "factions", Interface: "FactionsClient", Receiver: "factionsClient"},
+	"fleets":    {Dir: "fleets", Interface: "FleetsClient", Receiver: "fleetClient"},
+	"systems":   {Dir: "systems", Interface: "SystemsClient", Receiver: "systemsClient"},
+	"status":    {Dir: "status", Interface: "StatusClient", Receiver: "statusClient"},
+}
+
+// packageOrder fixes the order packages are generated in.
+var packageOrder = []string{"agents", "contracts", "factions", "fleets", "systems", "status"}
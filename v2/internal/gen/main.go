@@ -0,0 +1,69 @@
+// Command gen generates the SpaceTraders models and clients from the
+// vendored OpenAPI document. It is run through go generate from the v2
+// root:
+//
+//	go generate ./...
+//
+// Models are written to models/zz_generated.go and each client package
+// gets a zz_generated.go holding its interface, request and response types
+// and method implementations. mocks/zz_generated.go gets a programmable
+// fake of every interface. Hand-written code in the same packages
+// layers on top: client constructors and options, aliases kept for
+// compatibility, and the operations marked Manual in config.go.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"spacetradersgo/v2/internal/apispec"
+	"strings"
+)
+
+const header = "// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.\n\n"
+
+func main() {
+	specPath := flag.String("spec", "openapi/spacetraders.json", "path to the OpenAPI document")
+	out := flag.String("out", ".", "root of the v2 module tree to write into")
+	flag.Parse()
+
+	if err := run(*specPath, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, out string) error {
+	spec, err := apispec.Load(specPath)
+	if err != nil {
+		return err
+	}
+
+	if err := write(filepath.Join(out, "models", "zz_generated.go"), genModels(spec)); err != nil {
+		return err
+	}
+
+	byPkg, err := endpoints(spec)
+	if err != nil {
+		return err
+	}
+	for _, pkg := range sortedPackages(byPkg) {
+		src := genClient(spec, pkg, byPkg[pkg])
+		if err := write(filepath.Join(out, packages[pkg].Dir, "zz_generated.go"), src); err != nil {
+			return err
+		}
+	}
+	return write(filepath.Join(out, "mocks", "zz_generated.go"), genMocks(byPkg))
+}
+
+func write(path, src string) error {
+	// Operations without parameters still get a request type.
+	src = strings.ReplaceAll(src, "struct {\n}", "struct{}")
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return fmt.Errorf("format %s: %w\n%s", path, err, src)
+	}
+	return os.WriteFile(path, formatted, 0o644)
+}
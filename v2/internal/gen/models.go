@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"spacetradersgo/v2/internal/apispec"
+	"strings"
+)
+
+// genModels renders every component schema as a declaration in the models
+// package.
+func genModels(spec *apispec.Spec) string {
+	t := &typer{spec: spec, named: true}
+
+	var body strings.Builder
+	for _, name := range spec.Components.Schemas.Keys {
+		t.pending = append(t.pending, namedSchema{name, spec.Components.Schemas.Values[name]})
+		// Inline schemas discovered while rendering are declared right
+		// after their parent.
+		for len(t.pending) > 0 {
+			n := t.pending[0]
+			t.pending = t.pending[1:]
+			declare(&body, t, n.Name, n.Schema)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString("package models\n\n")
+	if t.usesTime {
+		b.WriteString("import \"time\"\n\n")
+	}
+	b.WriteString(body.String())
+	return b.String()
+}
+
+func declare(b *strings.Builder, t *typer, name string, s *apispec.Schema) {
+	if s.Description != "" {
+		b.WriteString(comment("", describe(name, s.Description)))
+	}
+	switch {
+	case len(s.Enum) > 0:
+		fmt.Fprintf(b, "type %s string\n\n", name)
+		b.WriteString("const (\n")
+		for _, v := range s.Enum {
+			fmt.Fprintf(b, "%s%s %s = %q\n", name, enumName(v), name, v)
+		}
+		b.WriteString(")\n\n")
+	case s.Type == "object":
+		fmt.Fprintf(b, "type %s struct {\n", name)
+		t.fields(b, s, name)
+		b.WriteString("}\n\n")
+	default:
+		fmt.Fprintf(b, "type %s %s\n\n", name, t.typeOf(s, name))
+	}
+}
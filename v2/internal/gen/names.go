@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+)
+
+// enumName turns an enum value such as IN_TRANSIT into InTransit.
+func enumName(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		word = strings.ToLower(word)
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// singular strips a plural "s" so array items get a sensible type name.
+func singular(s string) string {
+	return strings.TrimSuffix(s, "s")
+}
+
+// comment wraps text as a Go comment, one "//" line per source line.
+func comment(indent, text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(indent)
+		b.WriteString("//")
+		if line != "" {
+			b.WriteString(" ")
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"spacetradersgo/v2/internal/apispec"
+	"strings"
+)
+
+// typer maps schemas onto Go types. In the models package inline objects
+// and enums become named types; everywhere else they stay anonymous so a
+// response doesn't leak one-off types into the package API.
+type typer struct {
+	spec *apispec.Spec
+	// qual prefixes references to component schemas, e.g. "models.".
+	qual  string
+	named bool
+
+	// pending holds inline schemas that still need a named declaration.
+	pending []namedSchema
+
+	usesTime   bool
+	usesModels bool
+}
+
+type namedSchema struct {
+	Name   string
+	Schema *apispec.Schema
+}
+
+func (t *typer) typeOf(s *apispec.Schema, name string) string {
+	if s.Ref != "" {
+		if t.qual != "" {
+			t.usesModels = true
+		}
+		return t.qual + s.RefName()
+	}
+	switch s.Type {
+	case "string":
+		if len(s.Enum) > 0 && t.named {
+			t.pending = append(t.pending, namedSchema{name, s})
+			return name
+		}
+		if s.Format == "date-time" {
+			t.usesTime = true
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + t.typeOf(s.Items, singular(name))
+	case "object":
+		if t.named {
+			t.pending = append(t.pending, namedSchema{name, s})
+			return name
+		}
+		var b strings.Builder
+		b.WriteString("struct {\n")
+		t.fields(&b, s, name)
+		b.WriteString("}")
+		return b.String()
+	}
+	panic(fmt.Sprintf("gen: unsupported schema type %q for %s", s.Type, name))
+}
+
+// isObject reports whether s (after resolving refs) is a struct type, in
+// which case optional occurrences are generated as pointers.
+func (t *typer) isObject(s *apispec.Schema) bool {
+	r := t.spec.Resolve(s)
+	return r != nil && r.Type == "object"
+}
+
+// fields writes one struct field per property of s.
+func (t *typer) fields(b *strings.Builder, s *apispec.Schema, owner string) {
+	for _, prop := range s.Properties.Keys {
+		t.field(b, s, owner, prop)
+	}
+}
+
+func (t *typer) field(b *strings.Builder, parent *apispec.Schema, owner, prop string) {
+	s := parent.Properties.Values[prop]
+	name := apispec.GoName(prop)
+	typ := t.typeOf(s, owner+name)
+	tag := prop
+	if !parent.IsRequired(prop) {
+		if t.isObject(s) {
+			typ = "*" + typ
+		}
+		tag += ",omitempty"
+	}
+	b.WriteString(doc(name, s))
+	fmt.Fprintf(b, "%s %s `json:%q`\n", name, typ, tag)
+}
+
+// doc renders a field's description and constraints in the style of the
+// original hand-written types: the field name, then the description.
+func doc(name string, s *apispec.Schema) string {
+	text := describe(name, s.Description)
+	if c := s.Constraints(); c != "" {
+		if text != "" {
+			text += "\n"
+		}
+		text += c
+	}
+	return comment("", text)
+}
+
+// describe prefixes a description with the identifier it documents, unless
+// the description already starts with it ("Ship details.").
+func describe(name, desc string) string {
+	if desc == "" || strings.HasPrefix(desc, name+" ") {
+		return desc
+	}
+	return name + " " + desc
+}
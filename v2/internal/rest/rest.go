@@ -0,0 +1,136 @@
+// Package rest performs the HTTP round trip shared by every generated
+// client method: building the request, attaching the bearer token and
+// decoding either the payload or the API error.
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/strict"
+)
+
+const BaseURL = "https://api.spacetraders.io/v2"
+
+type Request struct {
+	// Operation is the SDK method name, e.g. "NavigateShip".
+	Operation string
+	Method    string
+	// Path is relative to BaseURL and already escaped.
+	Path  string
+	Query url.Values
+	// Token is sent as a bearer token when set.
+	Token string
+	// Body is marshalled as the JSON request body when set.
+	Body any
+}
+
+// Client holds the per-client settings every call needs.
+type Client struct {
+	HTTPClient *http.Client
+	// Strict, when set, checks every successful response body against
+	// the type it is decoded into.
+	Strict *strict.Recorder
+}
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Do sends req and decodes a successful response into out. Responses with
+// a status of 300 or above are returned as a *models.APIError, and a 204 No
+// Content leaves out untouched. If out has a SetRaw method, as every
+// response embedding models.RawResponse does, it receives the body.
+func (c *Client) Do(ctx context.Context, req *Request, out any) (*Response, error) {
+	var body io.Reader
+	if req.Body != nil {
+		reqBody, err := json.Marshal(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(reqBody)
+	}
+
+	u := BaseURL + req.Path
+	if len(req.Query) > 0 {
+		u += "?" + req.Query.Encode()
+	}
+	ctx = context.WithValue(ctx, operationKey{}, req.Operation)
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if req.Body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if req.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+req.Token)
+	}
+
+	httpResp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp := &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Body:       respBody,
+	}
+
+	if httpResp.StatusCode >= http.StatusMultipleChoices {
+		return resp, decodeError(resp)
+	}
+	if httpResp.StatusCode == http.StatusNoContent || out == nil {
+		return resp, nil
+	}
+
+	err = json.Unmarshal(respBody, out)
+	if err != nil {
+		return resp, err
+	}
+	if r, ok := out.(interface{ SetRaw([]byte) }); ok {
+		r.SetRaw(respBody)
+	}
+	if c.Strict != nil {
+		err = c.Strict.Observe(req.Operation, respBody, out)
+		if err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, nil
+}
+
+type operationKey struct{}
+
+// Operation returns the SDK method name of the call that made an HTTP
+// request, read from the request's context, or "" for requests the SDK
+// didn't make.
+func Operation(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+func decodeError(resp *Response) error {
+	envelope := struct {
+		Error *models.APIError `json:"error"`
+	}{}
+	if err := json.Unmarshal(resp.Body, &envelope); err != nil || envelope.Error == nil {
+		envelope.Error = &models.APIError{Message: http.StatusText(resp.StatusCode)}
+	}
+	envelope.Error.StatusCode = resp.StatusCode
+	return envelope.Error
+}
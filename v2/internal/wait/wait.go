@@ -0,0 +1,28 @@
+// Package wait sleeps for the packages that wait on the API, whether for a
+// ship to arrive, a cooldown to pass or a reset to be checked for, giving
+// up early when the context is done.
+package wait
+
+import (
+	"context"
+	"time"
+)
+
+// Until returns once t has passed, or with ctx's error if ctx is done
+// first.
+func Until(ctx context.Context, t time.Time) error {
+	return For(ctx, time.Until(t))
+}
+
+// For returns once d has elapsed, or with ctx's error if ctx is done
+// first.
+func For(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,210 @@
+package markets
+
+import (
+	"sort"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+	"time"
+)
+
+// Opportunity is buying a good at one market and selling it at another.
+type Opportunity struct {
+	Symbol string
+	// Buy is where the good is bought, at Buy.PurchasePrice, and Sell
+	// where it is sold, at Sell.SellPrice.
+	Buy  Price
+	Sell Price
+	// Units is what one trip carries: the cargo space, limited by the
+	// trade volume of both markets.
+	Units int
+	// Distance, Time and Fuel cover flying from the start to Buy and on to
+	// Sell.
+	Distance float64
+	Time     time.Duration
+	Fuel     int
+	// FuelCost is what Fuel costs at the fuel price, in credits.
+	FuelCost int
+	// Profit is the credits one trip makes after fuel.
+	Profit int
+	// PerHour is Profit over Time, the ranking used by Arbitrage.
+	PerHour float64
+}
+
+type arbitrage struct {
+	mode         models.ShipNavFlightMode
+	start        string
+	space        int
+	transactions int
+	fuelPrice    float64
+	minProfit    int
+}
+
+type arbitrageOpts func(*arbitrage)
+
+// WithArbitrageFlightMode sets the flight mode trips are timed and fuelled
+// for. The default is CRUISE.
+func WithArbitrageFlightMode(mode models.ShipNavFlightMode) arbitrageOpts {
+	return func(a *arbitrage) {
+		a.mode = mode
+	}
+}
+
+// WithStart sets where the ship sets off from, instead of its current
+// waypoint. An empty start leaves out the flight to the buying market.
+func WithStart(waypoint string) arbitrageOpts {
+	return func(a *arbitrage) {
+		a.start = waypoint
+	}
+}
+
+// WithCargoSpace sets the units the ship can carry, instead of its cargo
+// capacity, e.g. its free space.
+func WithCargoSpace(units int) arbitrageOpts {
+	return func(a *arbitrage) {
+		a.space = units
+	}
+}
+
+// WithTransactions sets how many trade volumes' worth of a good a trip may
+// buy and sell. Prices move against the trader with every transaction, so
+// the default is 1.
+func WithTransactions(n int) arbitrageOpts {
+	return func(a *arbitrage) {
+		a.transactions = n
+	}
+}
+
+// WithFuelPrice sets the price of FUEL, per market unit of
+// navigation.FuelUnit ship fuel. By default it is the cheapest FUEL sold
+// at the markets among waypoints, and fuel costs nothing if there is none.
+func WithFuelPrice(credits int) arbitrageOpts {
+	return func(a *arbitrage) {
+		a.fuelPrice = float64(credits)
+	}
+}
+
+// WithMinProfit leaves out opportunities making less than credits a trip.
+// The default is 1, any profit at all.
+func WithMinProfit(credits int) arbitrageOpts {
+	return func(a *arbitrage) {
+		a.minProfit = credits
+	}
+}
+
+// Arbitrage ranks buy-here-sell-there opportunities for ship among the
+// prices of the markets at waypoints, best profit per hour first. Ties go
+// to the bigger profit, then by symbol and the buying and selling
+// waypoints, so equal inputs always rank the same. Markets not among
+// waypoints are left out, as are trips a full tank can't fly.
+//
+// Prices come from MarketPrices for fresh GetMarket snapshots, or from a
+// History for the latest known prices.
+func Arbitrage(ship models.Ship, prices []Price, waypoints []models.Waypoint, opts ...arbitrageOpts) []Opportunity {
+	a := &arbitrage{
+		mode:         models.ShipNavFlightModeCruise,
+		start:        ship.Nav.WaypointSymbol,
+		space:        ship.Cargo.Capacity,
+		transactions: 1,
+		fuelPrice:    -1,
+		minProfit:    1,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	byWaypoint := map[string]models.Waypoint{}
+	for _, wp := range waypoints {
+		byWaypoint[wp.Symbol] = wp
+	}
+	bySymbol := map[string][]Price{}
+	for _, p := range prices {
+		if _, ok := byWaypoint[p.Waypoint]; ok {
+			bySymbol[p.Symbol] = append(bySymbol[p.Symbol], p)
+		}
+	}
+	if a.fuelPrice < 0 {
+		a.fuelPrice = 0
+		for _, p := range bySymbol["FUEL"] {
+			if p.PurchasePrice > 0 && (a.fuelPrice == 0 || float64(p.PurchasePrice) < a.fuelPrice) {
+				a.fuelPrice = float64(p.PurchasePrice)
+			}
+		}
+	}
+	start, hasStart := byWaypoint[a.start]
+
+	var out []Opportunity
+	for symbol, at := range bySymbol {
+		for _, buy := range at {
+			if buy.PurchasePrice <= 0 {
+				continue
+			}
+			for _, sell := range at {
+				if sell.Waypoint == buy.Waypoint || sell.SellPrice <= buy.PurchasePrice {
+					continue
+				}
+				o := Opportunity{Symbol: symbol, Buy: buy, Sell: sell, Units: a.units(buy, sell)}
+				if o.Units <= 0 {
+					continue
+				}
+				var legs []navigation.Estimate
+				if hasStart && start.Symbol != buy.Waypoint {
+					legs = append(legs, navigation.Flight(ship, start, byWaypoint[buy.Waypoint], a.mode))
+				}
+				legs = append(legs, navigation.Flight(ship, byWaypoint[buy.Waypoint], byWaypoint[sell.Waypoint], a.mode))
+				fits := true
+				for _, leg := range legs {
+					fits = fits && (ship.Fuel.Capacity == 0 || leg.Fuel <= ship.Fuel.Capacity)
+					o.Distance += leg.Distance
+					o.Time += leg.Time
+					o.Fuel += leg.Fuel
+				}
+				if !fits {
+					continue
+				}
+				o.FuelCost = int(float64(o.Fuel)*a.fuelPrice/navigation.FuelUnit + 0.5)
+				o.Profit = o.Units*(sell.SellPrice-buy.PurchasePrice) - o.FuelCost
+				if o.Profit < a.minProfit {
+					continue
+				}
+				o.PerHour = float64(o.Profit) / o.Time.Hours()
+				out = append(out, o)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		x, y := out[i], out[j]
+		switch {
+		case x.PerHour != y.PerHour:
+			return x.PerHour > y.PerHour
+		case x.Profit != y.Profit:
+			return x.Profit > y.Profit
+		case x.Symbol != y.Symbol:
+			return x.Symbol < y.Symbol
+		case x.Buy.Waypoint != y.Buy.Waypoint:
+			return x.Buy.Waypoint < y.Buy.Waypoint
+		}
+		return x.Sell.Waypoint < y.Sell.Waypoint
+	})
+	return out
+}
+
+func (a *arbitrage) units(buy, sell Price) int {
+	units := a.space
+	for _, volume := range []int{buy.TradeVolume, sell.TradeVolume} {
+		if volume > 0 && volume*a.transactions < units {
+			units = volume * a.transactions
+		}
+	}
+	return units
+}
+
+// MarketPrices turns GetMarket snapshots into prices for Arbitrage, all
+// observed at time at. Snapshots taken without a ship present have no
+// trade goods and give no prices.
+func MarketPrices(markets []models.Market, at time.Time) []Price {
+	h := NewHistory()
+	for _, m := range markets {
+		h.RecordMarket(models.Market{Symbol: m.Symbol, Exports: m.Exports, Imports: m.Imports, Exchange: m.Exchange, TradeGoods: m.TradeGoods}, at)
+	}
+	return h.Markets("")
+}
@@ -0,0 +1,174 @@
+package markets_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"spacetradersgo/v2/markets"
+	"spacetradersgo/v2/models"
+)
+
+// Waypoints of X1-T on a line and a corner, so cruising at speed 25 takes
+// the distance plus 15 seconds and burns the distance in fuel.
+var arbitrageWaypoints = []models.Waypoint{
+	{Symbol: "X1-T-A", X: 0, Y: 0},
+	{Symbol: "X1-T-B", X: 30, Y: 0},
+	{Symbol: "X1-T-C", X: 0, Y: 60},
+	{Symbol: "X1-T-D", X: 0, Y: 30},
+}
+
+func trader(fuel int) models.Ship {
+	var ship models.Ship
+	ship.Nav.WaypointSymbol = "X1-T-A"
+	ship.Cargo.Capacity = 40
+	ship.Fuel.Capacity = fuel
+	ship.Fuel.Current = fuel
+	ship.Engine.Speed = 25
+	return ship
+}
+
+// at is a price at X1-T-<waypoint>; a zero buy or sell price means the
+// market doesn't trade that side.
+func at(waypoint, symbol string, buy, sell, volume int) markets.Price {
+	return markets.Price{System: "X1-T", Waypoint: "X1-T-" + waypoint, Symbol: symbol, PurchasePrice: buy, SellPrice: sell, TradeVolume: volume}
+}
+
+func describe(opportunities []markets.Opportunity) []string {
+	var out []string
+	for _, o := range opportunities {
+		out = append(out, fmt.Sprintf("%s %s>%s units=%d dist=%.0f fuel=%d/%dc profit=%d",
+			o.Symbol, o.Buy.Waypoint[5:], o.Sell.Waypoint[5:], o.Units, o.Distance, o.Fuel, o.FuelCost, o.Profit))
+	}
+	return out
+}
+
+func TestArbitrage(t *testing.T) {
+	iron := []markets.Price{at("A", "IRON_ORE", 10, 0, 100), at("B", "IRON_ORE", 0, 20, 100)}
+	both := append([]markets.Price{at("A", "COPPER_ORE", 10, 0, 100), at("C", "COPPER_ORE", 0, 25, 100)}, iron...)
+	fuelAt := func(prices []markets.Price, fuel ...markets.Price) []markets.Price {
+		return append(append([]markets.Price(nil), prices...), fuel...)
+	}
+
+	for _, tc := range []struct {
+		name string
+		got  func() []markets.Opportunity
+		want []string
+	}{
+		{
+			// Iron makes 400 in 45s, copper 600 in 75s.
+			name: "ranked by profit per hour",
+			got:  func() []markets.Opportunity { return markets.Arbitrage(trader(400), both, arbitrageWaypoints) },
+			want: []string{
+				"IRON_ORE A>B units=40 dist=30 fuel=30/0c profit=400",
+				"COPPER_ORE A>C units=40 dist=60 fuel=60/0c profit=600",
+			},
+		},
+		{
+			name: "min profit",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), both, arbitrageWaypoints, markets.WithMinProfit(500))
+			},
+			want: []string{"COPPER_ORE A>C units=40 dist=60 fuel=60/0c profit=600"},
+		},
+		{
+			name: "trade volume caps units",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), []markets.Price{at("A", "IRON_ORE", 10, 0, 10), at("B", "IRON_ORE", 0, 20, 100)}, arbitrageWaypoints)
+			},
+			want: []string{"IRON_ORE A>B units=10 dist=30 fuel=30/0c profit=100"},
+		},
+		{
+			name: "transactions multiply the volume",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), []markets.Price{at("A", "IRON_ORE", 10, 0, 100), at("B", "IRON_ORE", 0, 20, 10)}, arbitrageWaypoints, markets.WithTransactions(3))
+			},
+			want: []string{"IRON_ORE A>B units=30 dist=30 fuel=30/0c profit=300"},
+		},
+		{
+			name: "cargo space caps transactions",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), []markets.Price{at("A", "IRON_ORE", 10, 0, 10), at("B", "IRON_ORE", 0, 20, 100)}, arbitrageWaypoints, markets.WithTransactions(5))
+			},
+			want: []string{"IRON_ORE A>B units=40 dist=30 fuel=30/0c profit=400"},
+		},
+		{
+			name: "cargo space",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), iron, arbitrageWaypoints, markets.WithCargoSpace(25))
+			},
+			want: []string{"IRON_ORE A>B units=25 dist=30 fuel=30/0c profit=250"},
+		},
+		{
+			// 30 fuel at 5 credits per 100 is 1.5 credits.
+			name: "fuel cost rounds half up",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), fuelAt(iron, at("A", "FUEL", 5, 0, 100)), arbitrageWaypoints)
+			},
+			want: []string{"IRON_ORE A>B units=40 dist=30 fuel=30/2c profit=398"},
+		},
+		{
+			// 30 fuel at 4 credits per 100 is 1.2 credits.
+			name: "fuel cost rounds down",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), iron, arbitrageWaypoints, markets.WithFuelPrice(4))
+			},
+			want: []string{"IRON_ORE A>B units=40 dist=30 fuel=30/1c profit=399"},
+		},
+		{
+			name: "default fuel price ignores markets not among waypoints",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), fuelAt(iron, at("A", "FUEL", 5, 0, 100), at("Z", "FUEL", 1, 0, 100)), arbitrageWaypoints)
+			},
+			want: []string{"IRON_ORE A>B units=40 dist=30 fuel=30/2c profit=398"},
+		},
+		{
+			name: "flight from the start",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), iron, arbitrageWaypoints, markets.WithStart("X1-T-C"))
+			},
+			want: []string{"IRON_ORE A>B units=40 dist=90 fuel=90/0c profit=400"},
+		},
+		{
+			name: "legs longer than a full tank",
+			got:  func() []markets.Opportunity { return markets.Arbitrage(trader(50), both, arbitrageWaypoints) },
+			want: []string{"IRON_ORE A>B units=40 dist=30 fuel=30/0c profit=400"},
+		},
+		{
+			name: "ties by symbol then waypoints",
+			got: func() []markets.Opportunity {
+				return markets.Arbitrage(trader(400), []markets.Price{
+					at("A", "IRON_ORE", 10, 0, 100), at("B", "IRON_ORE", 0, 20, 100), at("D", "IRON_ORE", 0, 20, 100),
+					at("A", "COPPER_ORE", 10, 0, 100), at("B", "COPPER_ORE", 0, 20, 100), at("D", "COPPER_ORE", 0, 20, 100),
+				}, arbitrageWaypoints)
+			},
+			want: []string{
+				"COPPER_ORE A>B units=40 dist=30 fuel=30/0c profit=400",
+				"COPPER_ORE A>D units=40 dist=30 fuel=30/0c profit=400",
+				"IRON_ORE A>B units=40 dist=30 fuel=30/0c profit=400",
+				"IRON_ORE A>D units=40 dist=30 fuel=30/0c profit=400",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arbitrage walks maps, so run it enough to catch an unstable
+			// order.
+			for i := 0; i < 20; i++ {
+				if got := describe(tc.got()); !reflect.DeepEqual(got, tc.want) {
+					t.Fatalf("Arbitrage =\n%q\nwant\n%q", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestArbitragePerHour(t *testing.T) {
+	o := markets.Arbitrage(trader(400), []markets.Price{at("A", "IRON_ORE", 10, 0, 100), at("B", "IRON_ORE", 0, 20, 100)}, arbitrageWaypoints)
+	if len(o) != 1 {
+		t.Fatalf("got %d opportunities, want 1", len(o))
+	}
+	// 400 credits in 45 seconds.
+	if o[0].Time.Seconds() != 45 || o[0].PerHour != 32000 {
+		t.Errorf("Time = %v, PerHour = %v, want 45s and 32000", o[0].Time, o[0].PerHour)
+	}
+}
@@ -0,0 +1,392 @@
+// Package markets keeps the history of market prices seen by the agent.
+// The API only shows a market's trade goods while a ship is there, so every
+// snapshot and transaction is worth keeping for deciding trades elsewhere.
+//
+//	history, err := markets.OpenHistory("markets.jsonl")
+//	client := v2.NewSpaceTradersClient(
+//		v2.WithSystemsClient(markets.NewRecordingSystems(systems.NewSystems(), history)),
+//		v2.WithFleetClient(markets.NewRecordingFleets(fleets.NewFleets(), history)),
+//	)
+//	...
+//	for _, p := range history.LatestInSystem("X1-DF55", "IRON_ORE") {
+//		fmt.Println(p.Waypoint, p.SellPrice, p.SellObserved)
+//	}
+package markets
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"spacetradersgo/v2/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source says where an observation came from.
+type Source string
+
+const (
+	// SourceMarket is a trade good of a GetMarket snapshot.
+	SourceMarket Source = "MARKET"
+	// SourcePurchase and SourceSell are transactions, from the agent's own
+	// trades or a market's recent transactions.
+	SourcePurchase Source = "PURCHASE"
+	SourceSell     Source = "SELL"
+)
+
+// Trade is how a market deals in a good.
+type Trade string
+
+const (
+	TradeExport   Trade = "EXPORT"
+	TradeImport   Trade = "IMPORT"
+	TradeExchange Trade = "EXCHANGE"
+)
+
+// Observation is a price of one good at one market at one time. Snapshots
+// carry both prices; a transaction carries the price of its side only.
+type Observation struct {
+	Time     time.Time `json:"time"`
+	System   string    `json:"system"`
+	Waypoint string    `json:"waypoint"`
+	Symbol   string    `json:"symbol"`
+	Source   Source    `json:"source"`
+	Trade    Trade     `json:"trade,omitempty"`
+
+	PurchasePrice int                `json:"purchasePrice,omitempty"`
+	SellPrice     int                `json:"sellPrice,omitempty"`
+	TradeVolume   int                `json:"tradeVolume,omitempty"`
+	Supply        models.SupplyLevel `json:"supply,omitempty"`
+
+	// Units and Ship are set for transactions.
+	Units int    `json:"units,omitempty"`
+	Ship  string `json:"ship,omitempty"`
+}
+
+// Price is the latest known state of a good at a market, merging the last
+// snapshot with any transactions since.
+type Price struct {
+	System        string
+	Waypoint      string
+	Symbol        string
+	Trade         Trade
+	PurchasePrice int
+	SellPrice     int
+	TradeVolume   int
+	Supply        models.SupplyLevel
+	// PurchaseObserved and SellObserved are when each price was last seen.
+	PurchaseObserved time.Time
+	SellObserved     time.Time
+}
+
+// Observed is when anything about the price was last seen.
+func (p Price) Observed() time.Time {
+	if p.SellObserved.After(p.PurchaseObserved) {
+		return p.SellObserved
+	}
+	return p.PurchaseObserved
+}
+
+type seriesKey struct {
+	waypoint string
+	symbol   string
+}
+
+// History is a time series of observations by market and good. It is safe
+// for concurrent use.
+type History struct {
+	mu     sync.RWMutex
+	series map[seriesKey][]Observation
+	seen   map[string]bool
+	log    *os.File
+	err    error
+}
+
+// NewHistory returns a History kept in memory only.
+func NewHistory() *History {
+	return &History{series: map[seriesKey][]Observation{}, seen: map[string]bool{}}
+}
+
+// OpenHistory loads the observations in the JSON lines file at path, which
+// need not exist, and appends every new observation to it. A final line cut
+// short, as a crash while recording leaves it, is dropped from the file;
+// a bad line anywhere else is an error.
+func OpenHistory(path string) (*History, error) {
+	h := NewHistory()
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		r      = bufio.NewReader(f)
+		offset int64
+		// torn is the offset of a bad line, which must be the last.
+		torn     = int64(-1)
+		tornLine int
+		tornErr  error
+	)
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			f.Close()
+			return nil, err
+		}
+		if len(bytes.TrimSpace(b)) > 0 {
+			if torn >= 0 {
+				f.Close()
+				return nil, fmt.Errorf("markets: %s:%d: %w", path, tornLine, tornErr)
+			}
+			var obs Observation
+			if uerr := json.Unmarshal(b, &obs); uerr != nil {
+				torn, tornLine, tornErr = offset, line, uerr
+			} else if b[len(b)-1] != '\n' {
+				// Whole but unterminated: end it before appending.
+				if _, err := f.Write([]byte{'\n'}); err != nil {
+					f.Close()
+					return nil, err
+				}
+				h.add(obs)
+			} else {
+				h.add(obs)
+			}
+		}
+		offset += int64(len(b))
+		if err == io.EOF {
+			break
+		}
+	}
+	if torn >= 0 {
+		if err := f.Truncate(torn); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	h.log = f
+	return h, nil
+}
+
+// Close closes the history's file, if it has one.
+func (h *History) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.log == nil {
+		return nil
+	}
+	err := h.log.Close()
+	h.log = nil
+	return err
+}
+
+// Err returns the first error recording to the history's file. The
+// recording clients can't return it without failing the call that was
+// observed, so check it now and then.
+func (h *History) Err() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.err
+}
+
+// Record adds observations. Transactions already recorded are ignored, so a
+// market's recent transactions can be recorded on every visit.
+func (h *History) Record(observations ...Observation) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var lines []byte
+	for _, obs := range observations {
+		if !h.add(obs) || h.log == nil {
+			continue
+		}
+		b, err := json.Marshal(obs)
+		if err != nil {
+			if h.err == nil {
+				h.err = err
+			}
+			return err
+		}
+		lines = append(append(lines, b...), '\n')
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	if _, err := h.log.Write(lines); err != nil {
+		if h.err == nil {
+			h.err = err
+		}
+		return err
+	}
+	return nil
+}
+
+// RecordMarket records the trade goods and transactions of a GetMarket
+// response seen at time at. Markets seen without a ship present have no
+// prices and add nothing.
+func (h *History) RecordMarket(market models.Market, at time.Time) error {
+	trades := map[string]Trade{}
+	for _, g := range market.Exports {
+		trades[string(g.Symbol)] = TradeExport
+	}
+	for _, g := range market.Imports {
+		trades[string(g.Symbol)] = TradeImport
+	}
+	for _, g := range market.Exchange {
+		trades[string(g.Symbol)] = TradeExchange
+	}
+
+	var observations []Observation
+	for _, g := range market.TradeGoods {
+		observations = append(observations, Observation{
+			Time:          at,
+			System:        SystemOf(market.Symbol),
+			Waypoint:      market.Symbol,
+			Symbol:        g.Symbol,
+			Source:        SourceMarket,
+			Trade:         trades[g.Symbol],
+			PurchasePrice: g.PurchasePrice,
+			SellPrice:     g.SellPrice,
+			TradeVolume:   g.TradeVolume,
+			Supply:        g.Supply,
+		})
+	}
+	for _, t := range market.Transactions {
+		observations = append(observations, transaction(t))
+	}
+	return h.Record(observations...)
+}
+
+// RecordTransaction records a purchase or sale.
+func (h *History) RecordTransaction(t models.MarketTransaction) error {
+	return h.Record(transaction(t))
+}
+
+func transaction(t models.MarketTransaction) Observation {
+	obs := Observation{
+		Time:     t.Timestamp,
+		System:   SystemOf(t.WaypointSymbol),
+		Waypoint: t.WaypointSymbol,
+		Symbol:   t.TradeSymbol,
+		Units:    t.Units,
+		Ship:     t.ShipSymbol,
+	}
+	if t.Type == models.MarketTransactionTypeSell {
+		obs.Source = SourceSell
+		obs.SellPrice = t.PricePerUnit
+	} else {
+		obs.Source = SourcePurchase
+		obs.PurchasePrice = t.PricePerUnit
+	}
+	return obs
+}
+
+// add stores obs in time order and reports whether it was new.
+func (h *History) add(obs Observation) bool {
+	if obs.Source != SourceMarket {
+		id := fmt.Sprintf("%s|%s|%s|%s|%d|%s", obs.Waypoint, obs.Ship, obs.Symbol, obs.Source, obs.Units, obs.Time.Format(time.RFC3339Nano))
+		if h.seen[id] {
+			return false
+		}
+		h.seen[id] = true
+	}
+	key := seriesKey{obs.Waypoint, obs.Symbol}
+	s := append(h.series[key], obs)
+	for i := len(s) - 1; i > 0 && s[i].Time.Before(s[i-1].Time); i-- {
+		s[i], s[i-1] = s[i-1], s[i]
+	}
+	h.series[key] = s
+	return true
+}
+
+// Series returns the observations of symbol at waypoint, oldest first.
+func (h *History) Series(waypoint, symbol string) []Observation {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]Observation(nil), h.series[seriesKey{waypoint, symbol}]...)
+}
+
+// Latest returns the latest known price of symbol at waypoint.
+func (h *History) Latest(waypoint, symbol string) (Price, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	s, ok := h.series[seriesKey{waypoint, symbol}]
+	if !ok {
+		return Price{}, false
+	}
+	return latest(s), true
+}
+
+// LatestInSystem returns the latest known price of symbol at every market
+// in system where it has been seen, by waypoint. An empty system means
+// everywhere.
+func (h *History) LatestInSystem(system, symbol string) []Price {
+	return h.prices(func(k seriesKey, s []Observation) bool {
+		return k.symbol == symbol && (system == "" || s[0].System == system)
+	})
+}
+
+// Market returns the latest known price of every good seen at waypoint, by
+// symbol.
+func (h *History) Market(waypoint string) []Price {
+	return h.prices(func(k seriesKey, _ []Observation) bool {
+		return k.waypoint == waypoint
+	})
+}
+
+// Markets returns the latest known price of every good at every market in
+// system, or everywhere for an empty system.
+func (h *History) Markets(system string) []Price {
+	return h.prices(func(_ seriesKey, s []Observation) bool {
+		return system == "" || s[0].System == system
+	})
+}
+
+func (h *History) prices(match func(seriesKey, []Observation) bool) []Price {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	var out []Price
+	for k, s := range h.series {
+		if match(k, s) {
+			out = append(out, latest(s))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Waypoint != out[j].Waypoint {
+			return out[i].Waypoint < out[j].Waypoint
+		}
+		return out[i].Symbol < out[j].Symbol
+	})
+	return out
+}
+
+// latest folds a time ordered series into a Price: each price is the last
+// one seen from any source, the rest comes from the last snapshot.
+func latest(s []Observation) Price {
+	p := Price{System: s[0].System, Waypoint: s[0].Waypoint, Symbol: s[0].Symbol}
+	for _, obs := range s {
+		if obs.PurchasePrice > 0 {
+			p.PurchasePrice, p.PurchaseObserved = obs.PurchasePrice, obs.Time
+		}
+		if obs.SellPrice > 0 {
+			p.SellPrice, p.SellObserved = obs.SellPrice, obs.Time
+		}
+		if obs.Source == SourceMarket {
+			p.TradeVolume, p.Supply = obs.TradeVolume, obs.Supply
+			if obs.Trade != "" {
+				p.Trade = obs.Trade
+			}
+		}
+	}
+	return p
+}
+
+// SystemOf returns the system symbol of a waypoint symbol, e.g. X1-DF55 for
+// X1-DF55-20250Z.
+func SystemOf(waypoint string) string {
+	if i := strings.LastIndex(waypoint, "-"); i > 0 {
+		return waypoint[:i]
+	}
+	return waypoint
+}
@@ -0,0 +1,212 @@
+package markets_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/markets"
+	"spacetradersgo/v2/mocks"
+	"spacetradersgo/v2/models"
+)
+
+var t0 = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// market is a snapshot of X1-DF55-A1 trading iron ore and fuel, with one
+// recent sale of iron ore.
+func market(at time.Time, ironBuy, ironSell int) models.Market {
+	return models.Market{
+		Symbol:   "X1-DF55-A1",
+		Exports:  []models.TradeGood{{Symbol: "IRON_ORE"}},
+		Exchange: []models.TradeGood{{Symbol: "FUEL"}},
+		TradeGoods: []models.MarketTradeGood{
+			{Symbol: "IRON_ORE", TradeVolume: 100, Supply: models.SupplyLevelAbundant, PurchasePrice: ironBuy, SellPrice: ironSell},
+			{Symbol: "FUEL", TradeVolume: 50, Supply: models.SupplyLevelModerate, PurchasePrice: 80, SellPrice: 70},
+		},
+		Transactions: []models.MarketTransaction{
+			{WaypointSymbol: "X1-DF55-A1", ShipSymbol: "OTHER-1", TradeSymbol: "IRON_ORE", Type: models.MarketTransactionTypeSell, Units: 10, PricePerUnit: ironSell, Timestamp: at.Add(-time.Minute)},
+		},
+	}
+}
+
+func sale(waypoint, symbol string, price int, at time.Time) models.MarketTransaction {
+	return models.MarketTransaction{WaypointSymbol: waypoint, ShipSymbol: "SHIP-1", TradeSymbol: symbol, Type: models.MarketTransactionTypeSell, Units: 5, PricePerUnit: price, TotalPrice: 5 * price, Timestamp: at}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "markets.jsonl")
+	h, err := markets.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.RecordMarket(market(t0, 30, 25), t0); err != nil {
+		t.Fatal(err)
+	}
+	// A second visit reports the same recent sale; it is kept once.
+	if err := h.RecordMarket(market(t0, 30, 25), t0.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.RecordTransaction(sale("X1-ZZ9-B2", "IRON_ORE", 40, t0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := markets.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	for _, key := range [][2]string{{"X1-DF55-A1", "IRON_ORE"}, {"X1-DF55-A1", "FUEL"}, {"X1-ZZ9-B2", "IRON_ORE"}} {
+		want, got := h.Series(key[0], key[1]), again.Series(key[0], key[1])
+		if len(want) == 0 || !reflect.DeepEqual(got, want) {
+			t.Errorf("Series(%s, %s) reloaded = %+v, want %+v", key[0], key[1], got, want)
+		}
+	}
+	if n := len(again.Series("X1-DF55-A1", "IRON_ORE")); n != 3 {
+		t.Errorf("iron ore series has %d observations, want 2 snapshots and 1 sale", n)
+	}
+}
+
+func TestOpenHistoryDropsTornFinalLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "markets.jsonl")
+	h, err := markets.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.RecordTransaction(sale("X1-DF55-A1", "IRON_ORE", 25, t0))
+	h.Close()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2023-06-01T01:00:00Z","system":"X1-DF`)
+	f.Close()
+
+	h, err = markets.OpenHistory(path)
+	if err != nil {
+		t.Fatalf("OpenHistory with a torn final line: %v", err)
+	}
+	if n := len(h.Series("X1-DF55-A1", "IRON_ORE")); n != 1 {
+		t.Errorf("loaded %d observations, want the 1 whole one", n)
+	}
+	// Recording after the torn line must leave a file that loads.
+	h.RecordTransaction(sale("X1-DF55-A1", "IRON_ORE", 26, t0.Add(time.Hour)))
+	h.Close()
+	h, err = markets.OpenHistory(path)
+	if err != nil {
+		t.Fatalf("OpenHistory after recording past a torn line: %v", err)
+	}
+	defer h.Close()
+	if n := len(h.Series("X1-DF55-A1", "IRON_ORE")); n != 2 {
+		t.Errorf("loaded %d observations, want 2", n)
+	}
+}
+
+func TestOpenHistoryAppendsAfterUnterminatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "markets.jsonl")
+	os.WriteFile(path, []byte(`{"time":"2023-06-01T00:00:00Z","system":"X1-DF55","waypoint":"X1-DF55-A1","symbol":"FUEL","source":"MARKET","purchasePrice":80}`), 0o644)
+	h, err := markets.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.RecordTransaction(sale("X1-DF55-A1", "FUEL", 70, t0.Add(time.Hour)))
+	h.Close()
+	h, err = markets.OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	if n := len(h.Series("X1-DF55-A1", "FUEL")); n != 2 {
+		t.Errorf("loaded %d observations, want 2", n)
+	}
+}
+
+func TestOpenHistoryRejectsCorruptionMidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "markets.jsonl")
+	good := `{"time":"2023-06-01T00:00:00Z","system":"X1-DF55","waypoint":"X1-DF55-A1","symbol":"FUEL","source":"MARKET","purchasePrice":80}`
+	os.WriteFile(path, []byte(good+"\nnot json\n"+good+"\n"), 0o644)
+	_, err := markets.OpenHistory(path)
+	if err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Fatalf("OpenHistory = %v, want an error at line 2", err)
+	}
+}
+
+func TestHistoryQueries(t *testing.T) {
+	h := markets.NewHistory()
+	h.RecordMarket(market(t0, 30, 25), t0)
+	// A purchase an hour later moves only the purchase price.
+	later := t0.Add(time.Hour)
+	h.RecordTransaction(models.MarketTransaction{WaypointSymbol: "X1-DF55-A1", ShipSymbol: "SHIP-1", TradeSymbol: "IRON_ORE", Type: models.MarketTransactionTypePurchase, Units: 10, PricePerUnit: 32, Timestamp: later})
+	h.RecordTransaction(sale("X1-DF55-B7", "IRON_ORE", 45, t0))
+	h.RecordTransaction(sale("X1-ZZ9-C3", "IRON_ORE", 60, t0))
+
+	p, ok := h.Latest("X1-DF55-A1", "IRON_ORE")
+	want := markets.Price{
+		System: "X1-DF55", Waypoint: "X1-DF55-A1", Symbol: "IRON_ORE", Trade: markets.TradeExport,
+		PurchasePrice: 32, SellPrice: 25, TradeVolume: 100, Supply: models.SupplyLevelAbundant,
+		PurchaseObserved: later, SellObserved: t0,
+	}
+	if !ok || p != want {
+		t.Errorf("Latest = %+v, %v, want %+v", p, ok, want)
+	}
+	if !p.Observed().Equal(later) {
+		t.Errorf("Observed = %v, want %v", p.Observed(), later)
+	}
+	if _, ok := h.Latest("X1-DF55-A1", "GOLD"); ok {
+		t.Error("Latest found a good never seen")
+	}
+
+	if s := h.Series("X1-DF55-A1", "IRON_ORE"); len(s) != 3 || s[0].Source != markets.SourceSell || s[2].Source != markets.SourcePurchase {
+		t.Errorf("Series = %+v, want the recent sale, the snapshot and the purchase in time order", s)
+	}
+
+	waypoints := func(prices []markets.Price) []string {
+		var out []string
+		for _, p := range prices {
+			out = append(out, p.Waypoint+"/"+p.Symbol)
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		name string
+		got  []markets.Price
+		want []string
+	}{
+		{"LatestInSystem", h.LatestInSystem("X1-DF55", "IRON_ORE"), []string{"X1-DF55-A1/IRON_ORE", "X1-DF55-B7/IRON_ORE"}},
+		{"LatestInSystem everywhere", h.LatestInSystem("", "IRON_ORE"), []string{"X1-DF55-A1/IRON_ORE", "X1-DF55-B7/IRON_ORE", "X1-ZZ9-C3/IRON_ORE"}},
+		{"Market", h.Market("X1-DF55-A1"), []string{"X1-DF55-A1/FUEL", "X1-DF55-A1/IRON_ORE"}},
+		{"Markets", h.Markets("X1-DF55"), []string{"X1-DF55-A1/FUEL", "X1-DF55-A1/IRON_ORE", "X1-DF55-B7/IRON_ORE"}},
+		{"Markets everywhere", h.Markets(""), []string{"X1-DF55-A1/FUEL", "X1-DF55-A1/IRON_ORE", "X1-DF55-B7/IRON_ORE", "X1-ZZ9-C3/IRON_ORE"}},
+	} {
+		if got := waypoints(tc.got); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRecordingFleetsRecordsRefuels(t *testing.T) {
+	h := markets.NewHistory()
+	m := mocks.NewFleetsClient()
+	refuel := &fleets.RefuelShipResponse{}
+	refuel.Data.Transaction = models.MarketTransaction{WaypointSymbol: "X1-DF55-A1", ShipSymbol: "SHIP-1", TradeSymbol: "FUEL", Type: models.MarketTransactionTypePurchase, Units: 100, PricePerUnit: 2, TotalPrice: 200, Timestamp: t0}
+	m.OnRefuelShip().Return(refuel, nil)
+	client := markets.NewRecordingFleets(m, h)
+
+	if _, err := client.RefuelShip(context.Background(), &fleets.RefuelShipRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+	p, ok := h.Latest("X1-DF55-A1", "FUEL")
+	if !ok || p.PurchasePrice != 2 || !p.PurchaseObserved.Equal(t0) {
+		t.Errorf("Latest fuel after refuelling = %+v, %v, want bought at 2", p, ok)
+	}
+	if err := h.Err(); err != nil {
+		t.Errorf("Err = %v", err)
+	}
+}
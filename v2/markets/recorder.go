@@ -0,0 +1,88 @@
+package markets
+
+import (
+	"context"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/systems"
+	"time"
+)
+
+// RecordingSystems is a SystemsClient that records every GetMarket
+// response into a History. Recording is best-effort: a failure doesn't fail
+// the call, and shows up in the History's Err.
+type RecordingSystems struct {
+	systems.SystemsClient
+	history *History
+}
+
+// NewRecordingSystems wraps client to record markets into history.
+func NewRecordingSystems(client systems.SystemsClient, history *History) *RecordingSystems {
+	return &RecordingSystems{SystemsClient: client, history: history}
+}
+
+func (c *RecordingSystems) GetMarket(ctx context.Context, req *systems.GetMarketRequest) (*systems.GetMarketResponse, error) {
+	resp, err := c.SystemsClient.GetMarket(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.history.RecordMarket(resp.Market, time.Now())
+	return resp, nil
+}
+
+// RecordingFleets is a FleetsClient that records the transactions of
+// SellCargo, PurchaseCargo and RefuelShip into a History. Like
+// RecordingSystems, it records on a best-effort basis and leaves failures
+// to the History's Err.
+type RecordingFleets struct {
+	fleets.FleetsClient
+	history *History
+}
+
+// NewRecordingFleets wraps client to record trades into history.
+func NewRecordingFleets(client fleets.FleetsClient, history *History) *RecordingFleets {
+	return &RecordingFleets{FleetsClient: client, history: history}
+}
+
+func (c *RecordingFleets) SellCargo(ctx context.Context, req *fleets.SellCargoRequest) (*fleets.SellCargoResponse, error) {
+	resp, err := c.FleetsClient.SellCargo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.history.RecordTransaction(resp.Data.Transaction)
+	return resp, nil
+}
+
+func (c *RecordingFleets) PurchaseCargo(ctx context.Context, req *fleets.PurchaseCargoRequest) (*fleets.PurchaseCargoResponse, error) {
+	resp, err := c.FleetsClient.PurchaseCargo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.history.RecordTransaction(resp.Data.Transaction)
+	return resp, nil
+}
+
+func (c *RecordingFleets) RefuelShip(ctx context.Context, req *fleets.RefuelShipRequest) (*fleets.RefuelShipResponse, error) {
+	resp, err := c.FleetsClient.RefuelShip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.history.RecordTransaction(resp.Data.Transaction)
+	return resp, nil
+}
+
+// Ship forwards to the wrapped client if it is a fleets.ShipLookup, such as
+// a FleetState, so fleets.EnsureDocked and the like keep using its mirror.
+func (c *RecordingFleets) Ship(shipID string) (fleets.ShipState, bool) {
+	if lookup, ok := c.FleetsClient.(fleets.ShipLookup); ok {
+		return lookup.Ship(shipID)
+	}
+	return fleets.ShipState{}, false
+}
+
+// SetCargo forwards to the wrapped client if it keeps cargo, as a
+// FleetState does.
+func (c *RecordingFleets) SetCargo(shipID string, cargo fleets.Cargo) {
+	if state, ok := c.FleetsClient.(interface{ SetCargo(string, fleets.Cargo) }); ok {
+		state.SetCargo(shipID, cargo)
+	}
+}
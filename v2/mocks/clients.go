@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	v2 "spacetradersgo/v2"
+)
+
+// Clients bundles a mock of every client interface.
+type Clients struct {
+	Agents    *AgentsClient
+	Contracts *ContractsClient
+	Factions  *FactionsClient
+	Fleets    *FleetsClient
+	Systems   *SystemsClient
+	Status    *StatusClient
+}
+
+func NewClients() *Clients {
+	return &Clients{
+		Agents:    NewAgentsClient(),
+		Contracts: NewContractsClient(),
+		Factions:  NewFactionsClient(),
+		Fleets:    NewFleetsClient(),
+		Systems:   NewSystemsClient(),
+		Status:    NewStatusClient(),
+	}
+}
+
+// SDK returns a SpcaeTradersClient backed entirely by the mocks.
+func (c *Clients) SDK() *v2.SpcaeTradersClient {
+	return v2.NewSpaceTradersClient(
+		v2.WithAgentsClient(c.Agents),
+		v2.WithContractsClient(c.Contracts),
+		v2.WithFactionsClient(c.Factions),
+		v2.WithFleetClient(c.Fleets),
+		v2.WithSystemsClient(c.Systems),
+		v2.WithStatusClient(c.Status),
+	)
+}
+
+// Verify checks the expectations of every mock.
+func (c *Clients) Verify() error {
+	for _, v := range []interface{ Verify() error }{c.Agents, c.Contracts, c.Factions, c.Fleets, c.Systems, c.Status} {
+		if err := v.Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
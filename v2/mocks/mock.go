@@ -0,0 +1,211 @@
+// Package mocks provides programmable test doubles for every client
+// interface, so bot logic written against SpcaeTradersClient can be unit
+// tested without a server.
+//
+// Each mock has an On method per operation. Expectations are tried in the
+// order they were added; the first one that matches the request and hasn't
+// used up its Times answers the call:
+//
+//	m := mocks.NewFleetsClient()
+//	m.OnNavigateShip().Times(1).Return(nil, &models.APIError{Code: models.ErrCodeShipInTransit})
+//	m.OnNavigateShip().Return(&fleets.NavigateShipResponse{}, nil)
+//	m.OnGetShip().When(func(r *fleets.GetShipRequest) bool { return r.ShipID == "SHIP-1" }).
+//		Do(func(ctx context.Context, r *fleets.GetShipRequest) (*fleets.GetShipResponse, error) { ... })
+//
+//	sdk := v2.NewSpaceTradersClient(v2.WithFleetClient(m))
+//	... run the bot ...
+//	if err := m.Verify(); err != nil {
+//		t.Fatal(err)
+//	}
+//	navs := m.OnNavigateShip().Calls()
+//
+// A call no expectation answers fails with ErrUnexpectedCall. The mocks
+// are generated from the same spec as the interfaces, so they never fall
+// out of step.
+package mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrUnexpectedCall is returned, wrapped, from a call no expectation
+// answers.
+var ErrUnexpectedCall = errors.New("mocks: unexpected call")
+
+// Call is one recorded invocation.
+type Call struct {
+	Method string
+	Req    any
+}
+
+// recorder keeps the calls made to every method of one mock, in order.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns every call made to the mock, across all its methods, in
+// the order they were made.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call{}, r.calls...)
+}
+
+// Method programs and records one operation of a mock.
+type Method[Req, Resp any] struct {
+	name string
+	rec  *recorder
+
+	expectations []*Expectation[Req, Resp]
+	calls        []*Req
+}
+
+func newMethod[Req, Resp any](name string, rec *recorder) *Method[Req, Resp] {
+	return &Method[Req, Resp]{name: name, rec: rec}
+}
+
+// Expect adds an expectation that, until told otherwise, matches every
+// request and answers with an empty response, a new one for each call so
+// callers may change what they get.
+func (m *Method[Req, Resp]) Expect() *Expectation[Req, Resp] {
+	m.rec.mu.Lock()
+	defer m.rec.mu.Unlock()
+	e := &Expectation[Req, Resp]{}
+	e.Do(func(context.Context, *Req) (*Resp, error) { return new(Resp), nil })
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// Return adds an expectation answering every request with resp and err.
+func (m *Method[Req, Resp]) Return(resp *Resp, err error) *Expectation[Req, Resp] {
+	return m.Expect().Return(resp, err)
+}
+
+// Fail adds an expectation answering every request with err.
+func (m *Method[Req, Resp]) Fail(err error) *Expectation[Req, Resp] {
+	return m.Expect().Return(nil, err)
+}
+
+// When adds an expectation for requests match accepts.
+func (m *Method[Req, Resp]) When(match func(*Req) bool) *Expectation[Req, Resp] {
+	return m.Expect().When(match)
+}
+
+// Times adds an expectation that answers at most n calls.
+func (m *Method[Req, Resp]) Times(n int) *Expectation[Req, Resp] {
+	return m.Expect().Times(n)
+}
+
+// Do adds an expectation answered by fn.
+func (m *Method[Req, Resp]) Do(fn func(context.Context, *Req) (*Resp, error)) *Expectation[Req, Resp] {
+	return m.Expect().Do(fn)
+}
+
+// Calls returns the requests this method has received, in order.
+func (m *Method[Req, Resp]) Calls() []*Req {
+	m.rec.mu.Lock()
+	defer m.rec.mu.Unlock()
+	return append([]*Req{}, m.calls...)
+}
+
+// Reset drops every expectation and recorded call.
+func (m *Method[Req, Resp]) Reset() {
+	m.rec.mu.Lock()
+	defer m.rec.mu.Unlock()
+	m.expectations = nil
+	m.calls = nil
+}
+
+func (m *Method[Req, Resp]) call(ctx context.Context, req *Req) (*Resp, error) {
+	m.rec.mu.Lock()
+	m.calls = append(m.calls, req)
+	m.rec.calls = append(m.rec.calls, Call{Method: m.name, Req: req})
+	var fn func(context.Context, *Req) (*Resp, error)
+	for _, e := range m.expectations {
+		if (e.times > 0 && e.calls >= e.times) || (e.match != nil && !e.match(req)) {
+			continue
+		}
+		e.calls++
+		fn = e.fn
+		break
+	}
+	m.rec.mu.Unlock()
+
+	if fn == nil {
+		return nil, fmt.Errorf("%w: %s(%+v)", ErrUnexpectedCall, m.name, req)
+	}
+	return fn(ctx, req)
+}
+
+// unmet describes expectations with a Times count still to be reached.
+func (m *Method[Req, Resp]) unmet() []string {
+	m.rec.mu.Lock()
+	defer m.rec.mu.Unlock()
+	var out []string
+	for i, e := range m.expectations {
+		if e.times > 0 && e.calls < e.times {
+			out = append(out, fmt.Sprintf("%s expectation %d called %d of %d times", m.name, i+1, e.calls, e.times))
+		}
+	}
+	return out
+}
+
+type verifier interface {
+	unmet() []string
+}
+
+func verify(methods ...verifier) error {
+	var unmet []string
+	for _, m := range methods {
+		unmet = append(unmet, m.unmet()...)
+	}
+	if len(unmet) > 0 {
+		return fmt.Errorf("mocks: unmet expectations: %s", strings.Join(unmet, "; "))
+	}
+	return nil
+}
+
+// Expectation is one programmed answer. Its setters return it so they can
+// be chained.
+type Expectation[Req, Resp any] struct {
+	match func(*Req) bool
+	fn    func(context.Context, *Req) (*Resp, error)
+	// times limits how many calls this answers; zero means no limit.
+	times int
+	calls int
+}
+
+// When restricts the expectation to requests match accepts.
+func (e *Expectation[Req, Resp]) When(match func(*Req) bool) *Expectation[Req, Resp] {
+	e.match = match
+	return e
+}
+
+// Times limits the expectation to n calls, after which later expectations
+// are tried. Verify reports it if it was called fewer times.
+func (e *Expectation[Req, Resp]) Times(n int) *Expectation[Req, Resp] {
+	e.times = n
+	return e
+}
+
+// Once is Times(1).
+func (e *Expectation[Req, Resp]) Once() *Expectation[Req, Resp] {
+	return e.Times(1)
+}
+
+// Return answers with resp and err.
+func (e *Expectation[Req, Resp]) Return(resp *Resp, err error) *Expectation[Req, Resp] {
+	e.fn = func(context.Context, *Req) (*Resp, error) { return resp, err }
+	return e
+}
+
+// Do answers by calling fn.
+func (e *Expectation[Req, Resp]) Do(fn func(context.Context, *Req) (*Resp, error)) *Expectation[Req, Resp] {
+	e.fn = fn
+	return e
+}
@@ -0,0 +1,141 @@
+package mocks_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/mocks"
+	"spacetradersgo/v2/models"
+)
+
+func getShip(t *testing.T, m *mocks.FleetsClient, id string) (*fleets.GetShipResponse, error) {
+	t.Helper()
+	return m.GetShip(context.Background(), &fleets.GetShipRequest{ShipID: id})
+}
+
+func TestExpectationsInOrder(t *testing.T) {
+	m := mocks.NewFleetsClient()
+	inTransit := &models.APIError{Code: models.ErrCodeShipInTransit}
+	m.OnNavigateShip().Times(2).Return(nil, inTransit)
+	ok := &fleets.NavigateShipResponse{}
+	m.OnNavigateShip().Return(ok, nil)
+
+	for i, want := range []error{inTransit, inTransit, nil, nil} {
+		resp, err := m.NavigateShip(context.Background(), &fleets.NavigateShipRequest{ShipID: "SHIP-1"})
+		if err != want {
+			t.Fatalf("call %d: err = %v, want %v", i+1, err, want)
+		}
+		if want == nil && resp != ok {
+			t.Errorf("call %d: resp = %p, want the programmed %p", i+1, resp, ok)
+		}
+	}
+	if n := len(m.OnNavigateShip().Calls()); n != 4 {
+		t.Errorf("Calls = %d, want 4", n)
+	}
+}
+
+func TestWhenMatches(t *testing.T) {
+	m := mocks.NewFleetsClient()
+	one, two := &fleets.GetShipResponse{}, &fleets.GetShipResponse{}
+	one.Ship.Symbol, two.Ship.Symbol = "SHIP-1", "SHIP-2"
+	m.OnGetShip().When(func(r *fleets.GetShipRequest) bool { return r.ShipID == "SHIP-1" }).Return(one, nil)
+	m.OnGetShip().When(func(r *fleets.GetShipRequest) bool { return r.ShipID == "SHIP-2" }).Return(two, nil)
+
+	for _, id := range []string{"SHIP-2", "SHIP-1", "SHIP-2"} {
+		resp, err := getShip(t, m, id)
+		if err != nil || resp.Ship.Symbol != id {
+			t.Errorf("GetShip(%s) = %+v, %v", id, resp, err)
+		}
+	}
+	if _, err := getShip(t, m, "SHIP-3"); !errors.Is(err, mocks.ErrUnexpectedCall) {
+		t.Errorf("GetShip(SHIP-3) err = %v, want ErrUnexpectedCall", err)
+	}
+}
+
+func TestTimesAndOnce(t *testing.T) {
+	m := mocks.NewFleetsClient()
+	limited := errors.New("limited")
+	m.OnGetShip().Return(nil, limited).Once()
+	m.OnGetShip().Times(2)
+
+	var errs []error
+	for i := 0; i < 4; i++ {
+		_, err := getShip(t, m, "SHIP-1")
+		errs = append(errs, err)
+	}
+	if errs[0] != limited || errs[1] != nil || errs[2] != nil || !errors.Is(errs[3], mocks.ErrUnexpectedCall) {
+		t.Errorf("errors = %v, want limited, two answers, then unexpected", errs)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify = %v, want every expectation met", err)
+	}
+}
+
+func TestVerifyReportsUnmet(t *testing.T) {
+	m := mocks.NewFleetsClient()
+	m.OnDockShip().Times(2)
+	m.OnOrbitShip().Expect().Once()
+	m.OnGetShip().Expect() // Unlimited, so never unmet.
+	m.DockShip(context.Background(), &fleets.DockShipRequest{ShipID: "SHIP-1"})
+
+	err := m.Verify()
+	if err == nil {
+		t.Fatal("Verify = nil, want unmet expectations")
+	}
+	for _, want := range []string{"DockShip expectation 1 called 1 of 2 times", "OrbitShip expectation 1 called 0 of 1 times"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Verify = %q, want it to mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "GetShip") {
+		t.Errorf("Verify = %q, reports expectations without Times", err)
+	}
+}
+
+func TestUnexpectedCall(t *testing.T) {
+	m := mocks.NewFleetsClient()
+	_, err := m.OrbitShip(context.Background(), &fleets.OrbitShipRequest{ShipID: "SHIP-1"})
+	if !errors.Is(err, mocks.ErrUnexpectedCall) || !strings.Contains(err.Error(), "OrbitShip") || !strings.Contains(err.Error(), "SHIP-1") {
+		t.Errorf("err = %v, want ErrUnexpectedCall naming the method and request", err)
+	}
+	// Unexpected calls are recorded all the same.
+	if calls := m.Calls(); len(calls) != 1 || calls[0].Method != "OrbitShip" {
+		t.Errorf("Calls = %+v, want the OrbitShip call", calls)
+	}
+}
+
+func TestDefaultResponseIsFreshPerCall(t *testing.T) {
+	m := mocks.NewFleetsClient()
+	m.OnGetShip().When(func(*fleets.GetShipRequest) bool { return true })
+	first, err := getShip(t, m, "SHIP-1")
+	if err != nil || first == nil {
+		t.Fatalf("GetShip = %v, %v, want an empty response", first, err)
+	}
+	first.Ship.Symbol = "CHANGED"
+	second, _ := getShip(t, m, "SHIP-1")
+	if second == first || second.Ship.Symbol != "" {
+		t.Errorf("second response = %+v, shared with the first", second.Ship)
+	}
+}
+
+func TestDoAndReset(t *testing.T) {
+	m := mocks.NewFleetsClient()
+	m.OnGetShip().Do(func(_ context.Context, r *fleets.GetShipRequest) (*fleets.GetShipResponse, error) {
+		resp := &fleets.GetShipResponse{}
+		resp.Ship.Symbol = r.ShipID
+		return resp, nil
+	})
+	if resp, err := getShip(t, m, "SHIP-7"); err != nil || resp.Ship.Symbol != "SHIP-7" {
+		t.Errorf("GetShip = %+v, %v", resp, err)
+	}
+	m.OnGetShip().Reset()
+	if n := len(m.OnGetShip().Calls()); n != 0 {
+		t.Errorf("Calls after Reset = %d", n)
+	}
+	if _, err := getShip(t, m, "SHIP-7"); !errors.Is(err, mocks.ErrUnexpectedCall) {
+		t.Errorf("GetShip after Reset err = %v, want ErrUnexpectedCall", err)
+	}
+}
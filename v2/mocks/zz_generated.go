@@ -0,0 +1,577 @@
+// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"spacetradersgo/v2/agents"
+	"spacetradersgo/v2/contracts"
+	"spacetradersgo/v2/factions"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/status"
+	"spacetradersgo/v2/systems"
+)
+
+// AgentsClient is a programmable agents.AgentsClient.
+type AgentsClient struct {
+	recorder
+
+	newAgent *Method[agents.NewAgentRequest, agents.NewAgentResponse]
+	getAgent *Method[agents.GetAgentRequest, agents.GetAgentResponse]
+}
+
+var _ agents.AgentsClient = (*AgentsClient)(nil)
+
+func NewAgentsClient() *AgentsClient {
+	m := &AgentsClient{}
+	m.newAgent = newMethod[agents.NewAgentRequest, agents.NewAgentResponse]("NewAgent", &m.recorder)
+	m.getAgent = newMethod[agents.GetAgentRequest, agents.GetAgentResponse]("GetAgent", &m.recorder)
+	return m
+}
+
+// OnNewAgent programs and inspects calls to NewAgent.
+func (m *AgentsClient) OnNewAgent() *Method[agents.NewAgentRequest, agents.NewAgentResponse] {
+	return m.newAgent
+}
+
+func (m *AgentsClient) NewAgent(ctx context.Context, req *agents.NewAgentRequest) (*agents.NewAgentResponse, error) {
+	return m.newAgent.call(ctx, req)
+}
+
+// OnGetAgent programs and inspects calls to GetAgent.
+func (m *AgentsClient) OnGetAgent() *Method[agents.GetAgentRequest, agents.GetAgentResponse] {
+	return m.getAgent
+}
+
+func (m *AgentsClient) GetAgent(ctx context.Context, req *agents.GetAgentRequest) (*agents.GetAgentResponse, error) {
+	return m.getAgent.call(ctx, req)
+}
+
+// Verify reports expectations limited with Times that weren't called
+// that many times.
+func (m *AgentsClient) Verify() error {
+	return verify(
+		m.newAgent,
+		m.getAgent,
+	)
+}
+
+// ContractsClient is a programmable contracts.ContractsClient.
+type ContractsClient struct {
+	recorder
+
+	listContracts   *Method[contracts.ListContractsRequest, contracts.ListContractsResponse]
+	getContract     *Method[contracts.GetContractRequest, contracts.GetContractResponse]
+	acceptContract  *Method[contracts.AcceptContractRequest, contracts.AcceptContractResponse]
+	deliverContract *Method[contracts.DeliverContractRequest, contracts.DeliverContractResponse]
+	fulfillContract *Method[contracts.FulfillContractRequest, contracts.FulfillContractResponse]
+}
+
+var _ contracts.ContractsClient = (*ContractsClient)(nil)
+
+func NewContractsClient() *ContractsClient {
+	m := &ContractsClient{}
+	m.listContracts = newMethod[contracts.ListContractsRequest, contracts.ListContractsResponse]("ListContracts", &m.recorder)
+	m.getContract = newMethod[contracts.GetContractRequest, contracts.GetContractResponse]("GetContract", &m.recorder)
+	m.acceptContract = newMethod[contracts.AcceptContractRequest, contracts.AcceptContractResponse]("AcceptContract", &m.recorder)
+	m.deliverContract = newMethod[contracts.DeliverContractRequest, contracts.DeliverContractResponse]("DeliverContract", &m.recorder)
+	m.fulfillContract = newMethod[contracts.FulfillContractRequest, contracts.FulfillContractResponse]("FulfillContract", &m.recorder)
+	return m
+}
+
+// OnListContracts programs and inspects calls to ListContracts.
+func (m *ContractsClient) OnListContracts() *Method[contracts.ListContractsRequest, contracts.ListContractsResponse] {
+	return m.listContracts
+}
+
+func (m *ContractsClient) ListContracts(ctx context.Context, req *contracts.ListContractsRequest) (*contracts.ListContractsResponse, error) {
+	return m.listContracts.call(ctx, req)
+}
+
+// OnGetContract programs and inspects calls to GetContract.
+func (m *ContractsClient) OnGetContract() *Method[contracts.GetContractRequest, contracts.GetContractResponse] {
+	return m.getContract
+}
+
+func (m *ContractsClient) GetContract(ctx context.Context, req *contracts.GetContractRequest) (*contracts.GetContractResponse, error) {
+	return m.getContract.call(ctx, req)
+}
+
+// OnAcceptContract programs and inspects calls to AcceptContract.
+func (m *ContractsClient) OnAcceptContract() *Method[contracts.AcceptContractRequest, contracts.AcceptContractResponse] {
+	return m.acceptContract
+}
+
+func (m *ContractsClient) AcceptContract(ctx context.Context, req *contracts.AcceptContractRequest) (*contracts.AcceptContractResponse, error) {
+	return m.acceptContract.call(ctx, req)
+}
+
+// OnDeliverContract programs and inspects calls to DeliverContract.
+func (m *ContractsClient) OnDeliverContract() *Method[contracts.DeliverContractRequest, contracts.DeliverContractResponse] {
+	return m.deliverContract
+}
+
+func (m *ContractsClient) DeliverContract(ctx context.Context, req *contracts.DeliverContractRequest) (*contracts.DeliverContractResponse, error) {
+	return m.deliverContract.call(ctx, req)
+}
+
+// OnFulfillContract programs and inspects calls to FulfillContract.
+func (m *ContractsClient) OnFulfillContract() *Method[contracts.FulfillContractRequest, contracts.FulfillContractResponse] {
+	return m.fulfillContract
+}
+
+func (m *ContractsClient) FulfillContract(ctx context.Context, req *contracts.FulfillContractRequest) (*contracts.FulfillContractResponse, error) {
+	return m.fulfillContract.call(ctx, req)
+}
+
+// Verify reports expectations limited with Times that weren't called
+// that many times.
+func (m *ContractsClient) Verify() error {
+	return verify(
+		m.listContracts,
+		m.getContract,
+		m.acceptContract,
+		m.deliverContract,
+		m.fulfillContract,
+	)
+}
+
+// FactionsClient is a programmable factions.FactionsClient.
+type FactionsClient struct {
+	recorder
+
+	listFactions *Method[factions.ListFactionsRequest, factions.ListFactionsResponse]
+	getFaction   *Method[factions.GetFactionRequest, factions.GetFactionResponse]
+}
+
+var _ factions.FactionsClient = (*FactionsClient)(nil)
+
+func NewFactionsClient() *FactionsClient {
+	m := &FactionsClient{}
+	m.listFactions = newMethod[factions.ListFactionsRequest, factions.ListFactionsResponse]("ListFactions", &m.recorder)
+	m.getFaction = newMethod[factions.GetFactionRequest, factions.GetFactionResponse]("GetFaction", &m.recorder)
+	return m
+}
+
+// OnListFactions programs and inspects calls to ListFactions.
+func (m *FactionsClient) OnListFactions() *Method[factions.ListFactionsRequest, factions.ListFactionsResponse] {
+	return m.listFactions
+}
+
+func (m *FactionsClient) ListFactions(ctx context.Context, req *factions.ListFactionsRequest) (*factions.ListFactionsResponse, error) {
+	return m.listFactions.call(ctx, req)
+}
+
+// OnGetFaction programs and inspects calls to GetFaction.
+func (m *FactionsClient) OnGetFaction() *Method[factions.GetFactionRequest, factions.GetFactionResponse] {
+	return m.getFaction
+}
+
+func (m *FactionsClient) GetFaction(ctx context.Context, req *factions.GetFactionRequest) (*factions.GetFactionResponse, error) {
+	return m.getFaction.call(ctx, req)
+}
+
+// Verify reports expectations limited with Times that weren't called
+// that many times.
+func (m *FactionsClient) Verify() error {
+	return verify(
+		m.listFactions,
+		m.getFaction,
+	)
+}
+
+// FleetsClient is a programmable fleets.FleetsClient.
+type FleetsClient struct {
+	recorder
+
+	listShips         *Method[fleets.ListShipsRequest, fleets.ListShipsResponse]
+	getShip           *Method[fleets.GetShipRequest, fleets.GetShipResponse]
+	getShipCargo      *Method[fleets.GetShipCargoRequest, fleets.GetShipCargoResponse]
+	orbitShip         *Method[fleets.OrbitShipRequest, fleets.OrbitShipResponse]
+	createChart       *Method[fleets.CreateChartRequest, fleets.CreateChartResponse]
+	getShipCooldown   *Method[fleets.GetShipCooldownRequest, fleets.GetShipCooldownResponse]
+	dockShip          *Method[fleets.DockShipRequest, fleets.DockShipResponse]
+	createSurvey      *Method[fleets.CreateSurveyRequest, fleets.CreateSurveyResponse]
+	extractResource   *Method[fleets.ExtractResourceRequest, fleets.ExtractResourceResponse]
+	jettison          *Method[fleets.JettisonRequest, fleets.JettisonResponse]
+	jumpShip          *Method[fleets.JumpShipRequest, fleets.JumpShipResponse]
+	navigateShip      *Method[fleets.NavigateShipRequest, fleets.NavigateShipResponse]
+	getShipNav        *Method[fleets.GetShipNavRequest, fleets.GetShipNavResponse]
+	patchShipNav      *Method[fleets.PatchShipNavRequest, fleets.PatchShipNavResponse]
+	warpShip          *Method[fleets.WarpShipRequest, fleets.WarpShipResponse]
+	sellCargo         *Method[fleets.SellCargoRequest, fleets.SellCargoResponse]
+	purchaseCargo     *Method[fleets.PurchaseCargoRequest, fleets.PurchaseCargoResponse]
+	refuelShip        *Method[fleets.RefuelShipRequest, fleets.RefuelShipResponse]
+	negotiateContract *Method[fleets.NegotiateContractRequest, fleets.NegotiateContractResponse]
+}
+
+var _ fleets.FleetsClient = (*FleetsClient)(nil)
+
+func NewFleetsClient() *FleetsClient {
+	m := &FleetsClient{}
+	m.listShips = newMethod[fleets.ListShipsRequest, fleets.ListShipsResponse]("ListShips", &m.recorder)
+	m.getShip = newMethod[fleets.GetShipRequest, fleets.GetShipResponse]("GetShip", &m.recorder)
+	m.getShipCargo = newMethod[fleets.GetShipCargoRequest, fleets.GetShipCargoResponse]("GetShipCargo", &m.recorder)
+	m.orbitShip = newMethod[fleets.OrbitShipRequest, fleets.OrbitShipResponse]("OrbitShip", &m.recorder)
+	m.createChart = newMethod[fleets.CreateChartRequest, fleets.CreateChartResponse]("CreateChart", &m.recorder)
+	m.getShipCooldown = newMethod[fleets.GetShipCooldownRequest, fleets.GetShipCooldownResponse]("GetShipCooldown", &m.recorder)
+	m.dockShip = newMethod[fleets.DockShipRequest, fleets.DockShipResponse]("DockShip", &m.recorder)
+	m.createSurvey = newMethod[fleets.CreateSurveyRequest, fleets.CreateSurveyResponse]("CreateSurvey", &m.recorder)
+	m.extractResource = newMethod[fleets.ExtractResourceRequest, fleets.ExtractResourceResponse]("ExtractResource", &m.recorder)
+	m.jettison = newMethod[fleets.JettisonRequest, fleets.JettisonResponse]("Jettison", &m.recorder)
+	m.jumpShip = newMethod[fleets.JumpShipRequest, fleets.JumpShipResponse]("JumpShip", &m.recorder)
+	m.navigateShip = newMethod[fleets.NavigateShipRequest, fleets.NavigateShipResponse]("NavigateShip", &m.recorder)
+	m.getShipNav = newMethod[fleets.GetShipNavRequest, fleets.GetShipNavResponse]("GetShipNav", &m.recorder)
+	m.patchShipNav = newMethod[fleets.PatchShipNavRequest, fleets.PatchShipNavResponse]("PatchShipNav", &m.recorder)
+	m.warpShip = newMethod[fleets.WarpShipRequest, fleets.WarpShipResponse]("WarpShip", &m.recorder)
+	m.sellCargo = newMethod[fleets.SellCargoRequest, fleets.SellCargoResponse]("SellCargo", &m.recorder)
+	m.purchaseCargo = newMethod[fleets.PurchaseCargoRequest, fleets.PurchaseCargoResponse]("PurchaseCargo", &m.recorder)
+	m.refuelShip = newMethod[fleets.RefuelShipRequest, fleets.RefuelShipResponse]("RefuelShip", &m.recorder)
+	m.negotiateContract = newMethod[fleets.NegotiateContractRequest, fleets.NegotiateContractResponse]("NegotiateContract", &m.recorder)
+	return m
+}
+
+// OnListShips programs and inspects calls to ListShips.
+func (m *FleetsClient) OnListShips() *Method[fleets.ListShipsRequest, fleets.ListShipsResponse] {
+	return m.listShips
+}
+
+func (m *FleetsClient) ListShips(ctx context.Context, req *fleets.ListShipsRequest) (*fleets.ListShipsResponse, error) {
+	return m.listShips.call(ctx, req)
+}
+
+// OnGetShip programs and inspects calls to GetShip.
+func (m *FleetsClient) OnGetShip() *Method[fleets.GetShipRequest, fleets.GetShipResponse] {
+	return m.getShip
+}
+
+func (m *FleetsClient) GetShip(ctx context.Context, req *fleets.GetShipRequest) (*fleets.GetShipResponse, error) {
+	return m.getShip.call(ctx, req)
+}
+
+// OnGetShipCargo programs and inspects calls to GetShipCargo.
+func (m *FleetsClient) OnGetShipCargo() *Method[fleets.GetShipCargoRequest, fleets.GetShipCargoResponse] {
+	return m.getShipCargo
+}
+
+func (m *FleetsClient) GetShipCargo(ctx context.Context, req *fleets.GetShipCargoRequest) (*fleets.GetShipCargoResponse, error) {
+	return m.getShipCargo.call(ctx, req)
+}
+
+// OnOrbitShip programs and inspects calls to OrbitShip.
+func (m *FleetsClient) OnOrbitShip() *Method[fleets.OrbitShipRequest, fleets.OrbitShipResponse] {
+	return m.orbitShip
+}
+
+func (m *FleetsClient) OrbitShip(ctx context.Context, req *fleets.OrbitShipRequest) (*fleets.OrbitShipResponse, error) {
+	return m.orbitShip.call(ctx, req)
+}
+
+// OnCreateChart programs and inspects calls to CreateChart.
+func (m *FleetsClient) OnCreateChart() *Method[fleets.CreateChartRequest, fleets.CreateChartResponse] {
+	return m.createChart
+}
+
+func (m *FleetsClient) CreateChart(ctx context.Context, req *fleets.CreateChartRequest) (*fleets.CreateChartResponse, error) {
+	return m.createChart.call(ctx, req)
+}
+
+// OnGetShipCooldown programs and inspects calls to GetShipCooldown.
+func (m *FleetsClient) OnGetShipCooldown() *Method[fleets.GetShipCooldownRequest, fleets.GetShipCooldownResponse] {
+	return m.getShipCooldown
+}
+
+func (m *FleetsClient) GetShipCooldown(ctx context.Context, req *fleets.GetShipCooldownRequest) (*fleets.GetShipCooldownResponse, error) {
+	return m.getShipCooldown.call(ctx, req)
+}
+
+// OnDockShip programs and inspects calls to DockShip.
+func (m *FleetsClient) OnDockShip() *Method[fleets.DockShipRequest, fleets.DockShipResponse] {
+	return m.dockShip
+}
+
+func (m *FleetsClient) DockShip(ctx context.Context, req *fleets.DockShipRequest) (*fleets.DockShipResponse, error) {
+	return m.dockShip.call(ctx, req)
+}
+
+// OnCreateSurvey programs and inspects calls to CreateSurvey.
+func (m *FleetsClient) OnCreateSurvey() *Method[fleets.CreateSurveyRequest, fleets.CreateSurveyResponse] {
+	return m.createSurvey
+}
+
+func (m *FleetsClient) CreateSurvey(ctx context.Context, req *fleets.CreateSurveyRequest) (*fleets.CreateSurveyResponse, error) {
+	return m.createSurvey.call(ctx, req)
+}
+
+// OnExtractResource programs and inspects calls to ExtractResource.
+func (m *FleetsClient) OnExtractResource() *Method[fleets.ExtractResourceRequest, fleets.ExtractResourceResponse] {
+	return m.extractResource
+}
+
+func (m *FleetsClient) ExtractResource(ctx context.Context, req *fleets.ExtractResourceRequest) (*fleets.ExtractResourceResponse, error) {
+	return m.extractResource.call(ctx, req)
+}
+
+// OnJettison programs and inspects calls to Jettison.
+func (m *FleetsClient) OnJettison() *Method[fleets.JettisonRequest, fleets.JettisonResponse] {
+	return m.jettison
+}
+
+func (m *FleetsClient) Jettison(ctx context.Context, req *fleets.JettisonRequest) (*fleets.JettisonResponse, error) {
+	return m.jettison.call(ctx, req)
+}
+
+// OnJumpShip programs and inspects calls to JumpShip.
+func (m *FleetsClient) OnJumpShip() *Method[fleets.JumpShipRequest, fleets.JumpShipResponse] {
+	return m.jumpShip
+}
+
+func (m *FleetsClient) JumpShip(ctx context.Context, req *fleets.JumpShipRequest) (*fleets.JumpShipResponse, error) {
+	return m.jumpShip.call(ctx, req)
+}
+
+// OnNavigateShip programs and inspects calls to NavigateShip.
+func (m *FleetsClient) OnNavigateShip() *Method[fleets.NavigateShipRequest, fleets.NavigateShipResponse] {
+	return m.navigateShip
+}
+
+func (m *FleetsClient) NavigateShip(ctx context.Context, req *fleets.NavigateShipRequest) (*fleets.NavigateShipResponse, error) {
+	return m.navigateShip.call(ctx, req)
+}
+
+// OnGetShipNav programs and inspects calls to GetShipNav.
+func (m *FleetsClient) OnGetShipNav() *Method[fleets.GetShipNavRequest, fleets.GetShipNavResponse] {
+	return m.getShipNav
+}
+
+func (m *FleetsClient) GetShipNav(ctx context.Context, req *fleets.GetShipNavRequest) (*fleets.GetShipNavResponse, error) {
+	return m.getShipNav.call(ctx, req)
+}
+
+// OnPatchShipNav programs and inspects calls to PatchShipNav.
+func (m *FleetsClient) OnPatchShipNav() *Method[fleets.PatchShipNavRequest, fleets.PatchShipNavResponse] {
+	return m.patchShipNav
+}
+
+func (m *FleetsClient) PatchShipNav(ctx context.Context, req *fleets.PatchShipNavRequest) (*fleets.PatchShipNavResponse, error) {
+	return m.patchShipNav.call(ctx, req)
+}
+
+// OnWarpShip programs and inspects calls to WarpShip.
+func (m *FleetsClient) OnWarpShip() *Method[fleets.WarpShipRequest, fleets.WarpShipResponse] {
+	return m.warpShip
+}
+
+func (m *FleetsClient) WarpShip(ctx context.Context, req *fleets.WarpShipRequest) (*fleets.WarpShipResponse, error) {
+	return m.warpShip.call(ctx, req)
+}
+
+// OnSellCargo programs and inspects calls to SellCargo.
+func (m *FleetsClient) OnSellCargo() *Method[fleets.SellCargoRequest, fleets.SellCargoResponse] {
+	return m.sellCargo
+}
+
+func (m *FleetsClient) SellCargo(ctx context.Context, req *fleets.SellCargoRequest) (*fleets.SellCargoResponse, error) {
+	return m.sellCargo.call(ctx, req)
+}
+
+// OnPurchaseCargo programs and inspects calls to PurchaseCargo.
+func (m *FleetsClient) OnPurchaseCargo() *Method[fleets.PurchaseCargoRequest, fleets.PurchaseCargoResponse] {
+	return m.purchaseCargo
+}
+
+func (m *FleetsClient) PurchaseCargo(ctx context.Context, req *fleets.PurchaseCargoRequest) (*fleets.PurchaseCargoResponse, error) {
+	return m.purchaseCargo.call(ctx, req)
+}
+
+// OnRefuelShip programs and inspects calls to RefuelShip.
+func (m *FleetsClient) OnRefuelShip() *Method[fleets.RefuelShipRequest, fleets.RefuelShipResponse] {
+	return m.refuelShip
+}
+
+func (m *FleetsClient) RefuelShip(ctx context.Context, req *fleets.RefuelShipRequest) (*fleets.RefuelShipResponse, error) {
+	return m.refuelShip.call(ctx, req)
+}
+
+// OnNegotiateContract programs and inspects calls to NegotiateContract.
+func (m *FleetsClient) OnNegotiateContract() *Method[fleets.NegotiateContractRequest, fleets.NegotiateContractResponse] {
+	return m.negotiateContract
+}
+
+func (m *FleetsClient) NegotiateContract(ctx context.Context, req *fleets.NegotiateContractRequest) (*fleets.NegotiateContractResponse, error) {
+	return m.negotiateContract.call(ctx, req)
+}
+
+// Verify reports expectations limited with Times that weren't called
+// that many times.
+func (m *FleetsClient) Verify() error {
+	return verify(
+		m.listShips,
+		m.getShip,
+		m.getShipCargo,
+		m.orbitShip,
+		m.createChart,
+		m.getShipCooldown,
+		m.dockShip,
+		m.createSurvey,
+		m.extractResource,
+		m.jettison,
+		m.jumpShip,
+		m.navigateShip,
+		m.getShipNav,
+		m.patchShipNav,
+		m.warpShip,
+		m.sellCargo,
+		m.purchaseCargo,
+		m.refuelShip,
+		m.negotiateContract,
+	)
+}
+
+// SystemsClient is a programmable systems.SystemsClient.
+type SystemsClient struct {
+	recorder
+
+	purchaseShip  *Method[systems.PurchaseShipRequest, systems.PurchaseShipResponse]
+	listSystems   *Method[systems.ListSystemsRequest, systems.ListSystemsResponse]
+	getSystem     *Method[systems.GetSystemRequest, systems.GetSystemResponse]
+	listWaypoints *Method[systems.ListWaypointsRequest, systems.ListWaypointsResponse]
+	getWaypoint   *Method[systems.GetWaypointRequest, systems.GetWaypointResponse]
+	getMarket     *Method[systems.GetMarketRequest, systems.GetMarketResponse]
+	getShipyard   *Method[systems.GetShipyardRequest, systems.GetShipyardResponse]
+	getJumpGate   *Method[systems.GetJumpGateRequest, systems.GetJumpGateResponse]
+}
+
+var _ systems.SystemsClient = (*SystemsClient)(nil)
+
+func NewSystemsClient() *SystemsClient {
+	m := &SystemsClient{}
+	m.purchaseShip = newMethod[systems.PurchaseShipRequest, systems.PurchaseShipResponse]("PurchaseShip", &m.recorder)
+	m.listSystems = newMethod[systems.ListSystemsRequest, systems.ListSystemsResponse]("ListSystems", &m.recorder)
+	m.getSystem = newMethod[systems.GetSystemRequest, systems.GetSystemResponse]("GetSystem", &m.recorder)
+	m.listWaypoints = newMethod[systems.ListWaypointsRequest, systems.ListWaypointsResponse]("ListWaypoints", &m.recorder)
+	m.getWaypoint = newMethod[systems.GetWaypointRequest, systems.GetWaypointResponse]("GetWaypoint", &m.recorder)
+	m.getMarket = newMethod[systems.GetMarketRequest, systems.GetMarketResponse]("GetMarket", &m.recorder)
+	m.getShipyard = newMethod[systems.GetShipyardRequest, systems.GetShipyardResponse]("GetShipyard", &m.recorder)
+	m.getJumpGate = newMethod[systems.GetJumpGateRequest, systems.GetJumpGateResponse]("GetJumpGate", &m.recorder)
+	return m
+}
+
+// OnPurchaseShip programs and inspects calls to PurchaseShip.
+func (m *SystemsClient) OnPurchaseShip() *Method[systems.PurchaseShipRequest, systems.PurchaseShipResponse] {
+	return m.purchaseShip
+}
+
+func (m *SystemsClient) PurchaseShip(ctx context.Context, req *systems.PurchaseShipRequest) (*systems.PurchaseShipResponse, error) {
+	return m.purchaseShip.call(ctx, req)
+}
+
+// OnListSystems programs and inspects calls to ListSystems.
+func (m *SystemsClient) OnListSystems() *Method[systems.ListSystemsRequest, systems.ListSystemsResponse] {
+	return m.listSystems
+}
+
+func (m *SystemsClient) ListSystems(ctx context.Context, req *systems.ListSystemsRequest) (*systems.ListSystemsResponse, error) {
+	return m.listSystems.call(ctx, req)
+}
+
+// OnGetSystem programs and inspects calls to GetSystem.
+func (m *SystemsClient) OnGetSystem() *Method[systems.GetSystemRequest, systems.GetSystemResponse] {
+	return m.getSystem
+}
+
+func (m *SystemsClient) GetSystem(ctx context.Context, req *systems.GetSystemRequest) (*systems.GetSystemResponse, error) {
+	return m.getSystem.call(ctx, req)
+}
+
+// OnListWaypoints programs and inspects calls to ListWaypoints.
+func (m *SystemsClient) OnListWaypoints() *Method[systems.ListWaypointsRequest, systems.ListWaypointsResponse] {
+	return m.listWaypoints
+}
+
+func (m *SystemsClient) ListWaypoints(ctx context.Context, req *systems.ListWaypointsRequest) (*systems.ListWaypointsResponse, error) {
+	return m.listWaypoints.call(ctx, req)
+}
+
+// OnGetWaypoint programs and inspects calls to GetWaypoint.
+func (m *SystemsClient) OnGetWaypoint() *Method[systems.GetWaypointRequest, systems.GetWaypointResponse] {
+	return m.getWaypoint
+}
+
+func (m *SystemsClient) GetWaypoint(ctx context.Context, req *systems.GetWaypointRequest) (*systems.GetWaypointResponse, error) {
+	return m.getWaypoint.call(ctx, req)
+}
+
+// OnGetMarket programs and inspects calls to GetMarket.
+func (m *SystemsClient) OnGetMarket() *Method[systems.GetMarketRequest, systems.GetMarketResponse] {
+	return m.getMarket
+}
+
+func (m *SystemsClient) GetMarket(ctx context.Context, req *systems.GetMarketRequest) (*systems.GetMarketResponse, error) {
+	return m.getMarket.call(ctx, req)
+}
+
+// OnGetShipyard programs and inspects calls to GetShipyard.
+func (m *SystemsClient) OnGetShipyard() *Method[systems.GetShipyardRequest, systems.GetShipyardResponse] {
+	return m.getShipyard
+}
+
+func (m *SystemsClient) GetShipyard(ctx context.Context, req *systems.GetShipyardRequest) (*systems.GetShipyardResponse, error) {
+	return m.getShipyard.call(ctx, req)
+}
+
+// OnGetJumpGate programs and inspects calls to GetJumpGate.
+func (m *SystemsClient) OnGetJumpGate() *Method[systems.GetJumpGateRequest, systems.GetJumpGateResponse] {
+	return m.getJumpGate
+}
+
+func (m *SystemsClient) GetJumpGate(ctx context.Context, req *systems.GetJumpGateRequest) (*systems.GetJumpGateResponse, error) {
+	return m.getJumpGate.call(ctx, req)
+}
+
+// Verify reports expectations limited with Times that weren't called
+// that many times.
+func (m *SystemsClient) Verify() error {
+	return verify(
+		m.purchaseShip,
+		m.listSystems,
+		m.getSystem,
+		m.listWaypoints,
+		m.getWaypoint,
+		m.getMarket,
+		m.getShipyard,
+		m.getJumpGate,
+	)
+}
+
+// StatusClient is a programmable status.StatusClient.
+type StatusClient struct {
+	recorder
+
+	getStatus *Method[status.GetStatusRequest, status.GetStatusResponse]
+}
+
+var _ status.StatusClient = (*StatusClient)(nil)
+
+func NewStatusClient() *StatusClient {
+	m := &StatusClient{}
+	m.getStatus = newMethod[status.GetStatusRequest, status.GetStatusResponse]("GetStatus", &m.recorder)
+	return m
+}
+
+// OnGetStatus programs and inspects calls to GetStatus.
+func (m *StatusClient) OnGetStatus() *Method[status.GetStatusRequest, status.GetStatusResponse] {
+	return m.getStatus
+}
+
+func (m *StatusClient) GetStatus(ctx context.Context, req *status.GetStatusRequest) (*status.GetStatusResponse, error) {
+	return m.getStatus.call(ctx, req)
+}
+
+// Verify reports expectations limited with Times that weren't called
+// that many times.
+func (m *StatusClient) Verify() error {
+	return verify(
+		m.getStatus,
+	)
+}
@@ -0,0 +1,5 @@
+// Package models holds the SpaceTraders API schemas shared by every client.
+//
+// The types are generated from openapi/spacetraders.json; run go generate
+// from the v2 directory after updating the spec.
+package models
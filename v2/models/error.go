@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// APIError is returned by every client when the API answers with a non-2xx
+// status. Code is the game's error code (e.g. 4214 for a ship in transit)
+// and Data carries any structured details the API attached.
+type APIError struct {
+	StatusCode int             `json:"-"`
+	Message    string          `json:"message"`
+	Code       int             `json:"code"`
+	Data       json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("spacetraders: %s (status %d, code %d)", e.Message, e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("spacetraders: %s (status %d)", e.Message, e.StatusCode)
+}
+
+// Error codes the game returns in APIError.Code.
+const (
+	ErrCodeUnauthorized             = 401
+	ErrCodeNotFound                 = 404
+	ErrCodeValidation               = 422
+	ErrCodeRateLimited              = 429
+	ErrCodeCooldownConflict         = 4000
+	ErrCodeWaypointNoAccess         = 4001
+	ErrCodeTokenEmpty               = 4100
+	ErrCodeAgentNotExists           = 4107
+	ErrCodeRegisterAgentExists      = 4109
+	ErrCodeNavigateInTransit        = 4200
+	ErrCodeNavigateInvalidDest      = 4201
+	ErrCodeNavigateOutsideSystem    = 4202
+	ErrCodeNavigateInsufficientFuel = 4203
+	ErrCodeNavigateSameDestination  = 4204
+	ErrCodeExtractInvalidWaypoint   = 4205
+	ErrCodeJumpNoSystem             = 4207
+	ErrCodeJumpSameSystem           = 4208
+	ErrCodeJumpMissingModule        = 4210
+	ErrCodeJumpNoValidWaypoint      = 4211
+	ErrCodeShipInTransit            = 4214
+	ErrCodeMissingSensorArrays      = 4215
+	ErrCodePurchaseShipCredits      = 4216
+	ErrCodeCargoExceedsLimit        = 4217
+	ErrCodeCargoMissing             = 4218
+	ErrCodeCargoUnitCount           = 4219
+	ErrCodeSurveyVerification       = 4220
+	ErrCodeSurveyExpired            = 4221
+	ErrCodeSurveyWaypointType       = 4222
+	ErrCodeSurveyOrbit              = 4223
+	ErrCodeSurveyExhausted          = 4224
+	ErrCodeRefuelDocked             = 4225
+	ErrCodeRefuelInvalidWaypoint    = 4226
+	ErrCodeMissingMounts            = 4227
+	ErrCodeCargoFull                = 4228
+	ErrCodeWaypointCharted          = 4230
+	ErrCodeWarpInsideSystem         = 4235
+	ErrCodeShipNotInOrbit           = 4236
+	ErrCodeMissingSurveyor          = 4240
+	ErrCodeMissingWarpDrive         = 4241
+	ErrCodeShipNotDocked            = 4244
+	ErrCodeAcceptContractConflict   = 4501
+	ErrCodeFulfillContractDelivery  = 4502
+	ErrCodeContractDeadline         = 4503
+	ErrCodeContractFulfilled        = 4504
+	ErrCodeContractNotAccepted      = 4505
+	ErrCodeDeliverTerms             = 4508
+	ErrCodeDeliverFulfilled         = 4509
+	ErrCodeDeliverInvalidLocation   = 4510
+	ErrCodeExistingContract         = 4511
+	ErrCodeInsufficientCredits      = 4600
+	ErrCodeTradeNoPurchase          = 4601
+	ErrCodeTradeNotSold             = 4602
+	ErrCodeMarketNotFound           = 4603
+	ErrCodeTradeUnitLimit           = 4604
+	ErrCodeWaypointNoFaction        = 4700
+	ErrCodeShipyardNotFound         = 4800
+	ErrCodeShipyardShipUnavailable  = 4801
+)
+
+// IsCode reports whether err is an *APIError with the given code.
+func IsCode(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
@@ -0,0 +1,17 @@
+package models
+
+// RawResponse is embedded in every response type and keeps the exact bytes
+// the API returned, which helps when a decoded value looks wrong.
+type RawResponse struct {
+	raw []byte
+}
+
+// Raw returns the undecoded response body.
+func (r *RawResponse) Raw() []byte {
+	return r.raw
+}
+
+// SetRaw is called by the clients after decoding; callers don't need it.
+func (r *RawResponse) SetRaw(b []byte) {
+	r.raw = b
+}
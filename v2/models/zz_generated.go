@@ -0,0 +1,766 @@
+// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.
+
+package models
+
+import "time"
+
+// Agent details.
+type Agent struct {
+	// AccountID The unique identifier of the parent Account.
+	// >= 1 characters
+	AccountID string `json:"accountId"`
+	// Symbol of the agent.
+	// >= 3 characters <= 14 characters
+	Symbol string `json:"symbol"`
+	// Headquarters The headquarters of the agent.
+	// >= 1 characters
+	Headquarters string `json:"headquarters"`
+	// Credits The number of credits the agent has available. Credits can be negative if funds have been overdrawn.
+	Credits int64 `json:"credits"`
+	// StartingFaction The faction the agent started with.
+	// >= 1 characters
+	StartingFaction string `json:"startingFaction"`
+}
+
+// Chart The chart of a system or waypoint, which makes the location visible to other agents.
+type Chart struct {
+	// WaypointSymbol The symbol of the waypoint.
+	WaypointSymbol string `json:"waypointSymbol,omitempty"`
+	// SubmittedBy The agent that submitted the chart for this waypoint.
+	SubmittedBy string `json:"submittedBy,omitempty"`
+	// SubmittedOn The time the chart for this waypoint was submitted.
+	SubmittedOn time.Time `json:"submittedOn,omitempty"`
+}
+
+type ConnectedSystem struct {
+	// Symbol The symbol of the system.
+	// >= 1 characters
+	Symbol string `json:"symbol"`
+	// SectorSymbol The sector of this system.
+	// >= 1 characters
+	SectorSymbol string     `json:"sectorSymbol"`
+	Type         SystemType `json:"type"`
+	// FactionSymbol The symbol of the faction that owns the connected jump gate in the system.
+	FactionSymbol string `json:"factionSymbol,omitempty"`
+	// X Position in the universe in the x axis.
+	X int `json:"x"`
+	// Y Position in the universe in the y axis.
+	Y int `json:"y"`
+	// Distance The distance of this system to the connected Jump Gate.
+	Distance int `json:"distance"`
+}
+
+// Contract details.
+type Contract struct {
+	// ID of the contract.
+	// >= 1 characters
+	ID string `json:"id"`
+	// FactionSymbol The symbol of the faction that this contract is for.
+	// >= 1 characters
+	FactionSymbol string `json:"factionSymbol"`
+	// Type of contract.
+	Type  ContractType  `json:"type"`
+	Terms ContractTerms `json:"terms"`
+	// Accepted Whether the contract has been accepted by the agent
+	Accepted bool `json:"accepted"`
+	// Fulfilled Whether the contract has been fulfilled
+	Fulfilled bool `json:"fulfilled"`
+	// Expiration Deprecated in favor of deadlineToAccept
+	Expiration time.Time `json:"expiration"`
+	// DeadlineToAccept The time at which the contract is no longer available to be accepted
+	DeadlineToAccept time.Time `json:"deadlineToAccept,omitempty"`
+}
+
+// ContractType Type of contract.
+type ContractType string
+
+const (
+	ContractTypeProcurement ContractType = "PROCUREMENT"
+	ContractTypeTransport   ContractType = "TRANSPORT"
+	ContractTypeShuttle     ContractType = "SHUTTLE"
+)
+
+// ContractDeliverGood The details of a delivery contract. Includes the type of good, units needed, and the destination.
+type ContractDeliverGood struct {
+	// TradeSymbol The symbol of the trade good to deliver.
+	// >= 1 characters
+	TradeSymbol string `json:"tradeSymbol"`
+	// DestinationSymbol The destination where goods need to be delivered.
+	// >= 1 characters
+	DestinationSymbol string `json:"destinationSymbol"`
+	// UnitsRequired The number of units that need to be delivered on this contract.
+	UnitsRequired int `json:"unitsRequired"`
+	// UnitsFulfilled The number of units fulfilled on this contract.
+	UnitsFulfilled int `json:"unitsFulfilled"`
+}
+
+// ContractPayment Payments for the contract.
+type ContractPayment struct {
+	// OnAccepted The amount of credits received up front for accepting the contract.
+	OnAccepted int `json:"onAccepted"`
+	// OnFulfilled The amount of credits received when the contract is fulfilled.
+	OnFulfilled int `json:"onFulfilled"`
+}
+
+// ContractTerms The terms to fulfill the contract.
+type ContractTerms struct {
+	// Deadline The deadline for the contract.
+	Deadline time.Time       `json:"deadline"`
+	Payment  ContractPayment `json:"payment"`
+	// Deliver The cargo that needs to be delivered to fulfill the contract.
+	Deliver []ContractDeliverGood `json:"deliver,omitempty"`
+}
+
+// Cooldown A cooldown is a period of time in which a ship cannot perform certain actions.
+type Cooldown struct {
+	// ShipSymbol The symbol of the ship that is on cooldown
+	// >= 1 characters
+	ShipSymbol string `json:"shipSymbol"`
+	// TotalSeconds The total duration of the cooldown in seconds
+	// >= 0
+	TotalSeconds int `json:"totalSeconds"`
+	// RemainingSeconds The remaining duration of the cooldown in seconds
+	// >= 0
+	RemainingSeconds int `json:"remainingSeconds"`
+	// Expiration The date and time when the cooldown expires in ISO 8601 format
+	Expiration time.Time `json:"expiration,omitempty"`
+}
+
+// Extraction details.
+type Extraction struct {
+	// ShipSymbol Symbol of the ship that executed the extraction.
+	// >= 1 characters
+	ShipSymbol string          `json:"shipSymbol"`
+	Yield      ExtractionYield `json:"yield"`
+}
+
+// ExtractionYield A yield from the extraction operation.
+type ExtractionYield struct {
+	// Symbol The symbol of the good extracted.
+	Symbol string `json:"symbol"`
+	// Units The number of units extracted that were placed into the ship's cargo hold.
+	Units int `json:"units"`
+}
+
+type Faction struct {
+	// >= 1 characters
+	Symbol string `json:"symbol"`
+	// >= 1 characters
+	Name string `json:"name"`
+	// >= 1 characters
+	Description string `json:"description"`
+	// >= 1 characters
+	Headquarters string         `json:"headquarters"`
+	Traits       []FactionTrait `json:"traits"`
+	// IsRecruiting Whether or not the faction is currently recruiting new agents.
+	IsRecruiting bool `json:"isRecruiting"`
+}
+
+type FactionTrait struct {
+	// Symbol The unique identifier of the trait.
+	Symbol string `json:"symbol"`
+	// Name The name of the trait.
+	Name string `json:"name"`
+	// Description A description of the trait.
+	Description string `json:"description"`
+}
+
+type JumpGate struct {
+	// JumpRange The maximum jump range of the gate.
+	JumpRange float64 `json:"jumpRange"`
+	// FactionSymbol The symbol of the faction that owns the gate.
+	FactionSymbol string `json:"factionSymbol,omitempty"`
+	// ConnectedSystems The systems within range of the gate that have a corresponding gate.
+	ConnectedSystems []ConnectedSystem `json:"connectedSystems"`
+}
+
+type Market struct {
+	// Symbol The symbol of the market. The symbol is the same as the waypoint where the market is located.
+	Symbol string `json:"symbol"`
+	// Exports The list of goods that are exported from this market.
+	Exports []TradeGood `json:"exports"`
+	// Imports The list of goods that are sought as imports in this market.
+	Imports []TradeGood `json:"imports"`
+	// Exchange The list of goods that are bought and sold between agents at this market.
+	Exchange []TradeGood `json:"exchange"`
+	// Transactions The list of recent transactions at this market. Visible only when a ship is present at the market.
+	Transactions []MarketTransaction `json:"transactions,omitempty"`
+	// TradeGoods The list of goods that are traded at this market. Visible only when a ship is present at the market.
+	TradeGoods []MarketTradeGood `json:"tradeGoods,omitempty"`
+}
+
+type MarketTradeGood struct {
+	// Symbol The symbol of the trade good.
+	Symbol string `json:"symbol"`
+	// TradeVolume The typical volume flowing through the market for this type of good. The larger the trade volume, the more stable prices will be.
+	// >= 1
+	TradeVolume int         `json:"tradeVolume"`
+	Supply      SupplyLevel `json:"supply"`
+	// PurchasePrice The price at which this good can be purchased from the market.
+	// >= 0
+	PurchasePrice int `json:"purchasePrice"`
+	// SellPrice The price at which this good can be sold to the market.
+	// >= 0
+	SellPrice int `json:"sellPrice"`
+}
+
+type MarketTransaction struct {
+	// WaypointSymbol The symbol of the waypoint where the transaction took place.
+	WaypointSymbol string `json:"waypointSymbol"`
+	// ShipSymbol The symbol of the ship that made the transaction.
+	ShipSymbol string `json:"shipSymbol"`
+	// TradeSymbol The symbol of the trade good.
+	TradeSymbol string `json:"tradeSymbol"`
+	// Type The type of transaction.
+	Type MarketTransactionType `json:"type"`
+	// Units The number of units of the transaction.
+	// >= 0
+	Units int `json:"units"`
+	// PricePerUnit The price per unit of the transaction.
+	// >= 0
+	PricePerUnit int `json:"pricePerUnit"`
+	// TotalPrice The total price of the transaction.
+	// >= 0
+	TotalPrice int `json:"totalPrice"`
+	// Timestamp The timestamp of the transaction.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MarketTransactionType The type of transaction.
+type MarketTransactionType string
+
+const (
+	MarketTransactionTypePurchase MarketTransactionType = "PURCHASE"
+	MarketTransactionTypeSell     MarketTransactionType = "SELL"
+)
+
+type Meta struct {
+	// >= 0
+	Total int `json:"total"`
+	// >= 1
+	Page int `json:"page"`
+	// >= 1 <= 20
+	Limit int `json:"limit"`
+}
+
+// Ship details.
+type Ship struct {
+	// Symbol The globally unique identifier of the ship in the following format: `[AGENT_SYMBOL]-[HEX_ID]`
+	Symbol       string           `json:"symbol"`
+	Registration ShipRegistration `json:"registration"`
+	Nav          ShipNav          `json:"nav"`
+	Crew         ShipCrew         `json:"crew"`
+	Frame        ShipFrame        `json:"frame"`
+	Reactor      ShipReactor      `json:"reactor"`
+	Engine       ShipEngine       `json:"engine"`
+	// Modules installed in this ship.
+	Modules []ShipModule `json:"modules"`
+	// Mounts installed in this ship.
+	Mounts []ShipMount `json:"mounts"`
+	Cargo  ShipCargo   `json:"cargo"`
+	Fuel   ShipFuel    `json:"fuel"`
+}
+
+// ShipCargo Ship cargo details.
+type ShipCargo struct {
+	// Capacity The max number of items that can be stored in the cargo hold.
+	// >= 0
+	Capacity int `json:"capacity"`
+	// Units The number of items currently stored in the cargo hold.
+	// >= 0
+	Units int `json:"units"`
+	// Inventory The items currently in the cargo hold.
+	Inventory []ShipCargoItem `json:"inventory"`
+}
+
+// ShipCargoItem The type of cargo item and the number of units.
+type ShipCargoItem struct {
+	// Symbol The unique identifier of the cargo item type.
+	Symbol string `json:"symbol"`
+	// Name The name of the cargo item type.
+	Name string `json:"name"`
+	// Description The description of the cargo item type.
+	Description string `json:"description"`
+	// Units The number of units of the cargo item.
+	// >= 1
+	Units int `json:"units"`
+}
+
+// ShipCrew The ship's crew service and maintain the ship's systems and equipment.
+type ShipCrew struct {
+	// Current The current number of crew members on the ship.
+	Current int `json:"current"`
+	// Required The minimum number of crew members required to maintain the ship.
+	Required int `json:"required"`
+	// Capacity The maximum number of crew members the ship can support.
+	Capacity int `json:"capacity"`
+	// Rotation The rotation of crew shifts. A stricter shift improves the ship's performance. A more relaxed shift improves the crew's morale.
+	Rotation ShipCrewRotation `json:"rotation"`
+	// Morale A rough measure of the crew's morale. A higher morale means the crew is happier and more productive. A lower morale means the ship is more prone to accidents.
+	// >= 0 <= 100
+	Morale int `json:"morale"`
+	// Wages The amount of credits per crew member paid per hour. Wages are paid when a ship docks at a civilized waypoint.
+	// >= 0
+	Wages int `json:"wages"`
+}
+
+// ShipCrewRotation The rotation of crew shifts. A stricter shift improves the ship's performance. A more relaxed shift improves the crew's morale.
+type ShipCrewRotation string
+
+const (
+	ShipCrewRotationStrict  ShipCrewRotation = "STRICT"
+	ShipCrewRotationRelaxed ShipCrewRotation = "RELAXED"
+)
+
+// ShipEngine The engine determines how quickly a ship travels between waypoints.
+type ShipEngine struct {
+	Symbol      string `json:"symbol"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Condition is a range of 0 to 100 where 0 is completely worn out and 100 is brand new.
+	// >= 0 <= 100
+	Condition int `json:"condition,omitempty"`
+	// >= 1
+	Speed        int              `json:"speed"`
+	Requirements ShipRequirements `json:"requirements"`
+}
+
+// ShipFrame The frame of the ship. The frame determines the number of modules and mounting points of the ship, as well as base fuel capacity.
+type ShipFrame struct {
+	Symbol      string `json:"symbol"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Condition is a range of 0 to 100 where 0 is completely worn out and 100 is brand new.
+	// >= 0 <= 100
+	Condition int `json:"condition,omitempty"`
+	// >= 0
+	ModuleSlots int `json:"moduleSlots"`
+	// >= 0
+	MountingPoints int `json:"mountingPoints"`
+	// >= 0
+	FuelCapacity int              `json:"fuelCapacity"`
+	Requirements ShipRequirements `json:"requirements"`
+}
+
+// ShipFuel Details of the ship's fuel tanks including how much fuel was consumed during the last transit or action.
+type ShipFuel struct {
+	// Current The current amount of fuel in the ship's tanks.
+	// >= 0
+	Current int `json:"current"`
+	// Capacity The maximum amount of fuel the ship's tanks can hold.
+	// >= 0
+	Capacity int `json:"capacity"`
+	// Consumed An object that only shows up when an action has consumed fuel in the process. Shows the fuel consumption data.
+	Consumed *ShipFuelConsumed `json:"consumed,omitempty"`
+}
+
+// ShipFuelConsumed An object that only shows up when an action has consumed fuel in the process. Shows the fuel consumption data.
+type ShipFuelConsumed struct {
+	// Amount The amount of fuel consumed by the most recent transit or action.
+	// >= 0
+	Amount int `json:"amount"`
+	// Timestamp The time at which the fuel was consumed.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ShipModule A module can be installed in a ship and provides a set of capabilities such as storage space or quarters for crew.
+type ShipModule struct {
+	Symbol string `json:"symbol"`
+	// >= 0
+	Capacity int `json:"capacity,omitempty"`
+	// >= 0
+	Range        int              `json:"range,omitempty"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	Requirements ShipRequirements `json:"requirements"`
+}
+
+// ShipMount A mount is installed on the exterier of a ship.
+type ShipMount struct {
+	Symbol      string `json:"symbol"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// >= 0
+	Strength     int              `json:"strength,omitempty"`
+	Deposits     []string         `json:"deposits,omitempty"`
+	Requirements ShipRequirements `json:"requirements"`
+}
+
+// ShipNav The navigation information of the ship.
+type ShipNav struct {
+	// SystemSymbol The system symbol of the ship's current location.
+	// >= 1 characters
+	SystemSymbol string `json:"systemSymbol"`
+	// WaypointSymbol The waypoint symbol of the ship's current location, or if the ship is in-transit, the waypoint symbol of the ship's destination.
+	// >= 1 characters
+	WaypointSymbol string            `json:"waypointSymbol"`
+	Route          ShipNavRoute      `json:"route"`
+	Status         ShipNavStatus     `json:"status"`
+	FlightMode     ShipNavFlightMode `json:"flightMode"`
+}
+
+// ShipNavFlightMode The ship's set speed when traveling between waypoints or systems.
+type ShipNavFlightMode string
+
+const (
+	ShipNavFlightModeDrift   ShipNavFlightMode = "DRIFT"
+	ShipNavFlightModeStealth ShipNavFlightMode = "STEALTH"
+	ShipNavFlightModeCruise  ShipNavFlightMode = "CRUISE"
+	ShipNavFlightModeBurn    ShipNavFlightMode = "BURN"
+)
+
+// ShipNavRoute The routing information for the ship's most recent transit or current location.
+type ShipNavRoute struct {
+	Destination ShipNavRouteWaypoint `json:"destination"`
+	Departure   ShipNavRouteWaypoint `json:"departure"`
+	// DepartureTime The date time of the ship's departure.
+	DepartureTime time.Time `json:"departureTime"`
+	// Arrival The date time of the ship's arrival. If the ship is in-transit, this is the expected time of arrival.
+	Arrival time.Time `json:"arrival"`
+}
+
+// ShipNavRouteWaypoint The destination or departure of a ships nav route.
+type ShipNavRouteWaypoint struct {
+	Symbol       string       `json:"symbol"`
+	Type         WaypointType `json:"type"`
+	SystemSymbol string       `json:"systemSymbol"`
+	X            int          `json:"x"`
+	Y            int          `json:"y"`
+}
+
+// ShipNavStatus The current status of the ship
+type ShipNavStatus string
+
+const (
+	ShipNavStatusInTransit ShipNavStatus = "IN_TRANSIT"
+	ShipNavStatusInOrbit   ShipNavStatus = "IN_ORBIT"
+	ShipNavStatusDocked    ShipNavStatus = "DOCKED"
+)
+
+// ShipReactor The reactor of the ship. The reactor is responsible for powering the ship's systems and weapons.
+type ShipReactor struct {
+	Symbol      string `json:"symbol"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Condition is a range of 0 to 100 where 0 is completely worn out and 100 is brand new.
+	// >= 0 <= 100
+	Condition int `json:"condition,omitempty"`
+	// >= 1
+	PowerOutput  int              `json:"powerOutput"`
+	Requirements ShipRequirements `json:"requirements"`
+}
+
+// ShipRegistration The public registration information of the ship
+type ShipRegistration struct {
+	// Name The agent's registered name of the ship
+	// >= 1 characters
+	Name string `json:"name"`
+	// FactionSymbol The symbol of the faction the ship is registered with
+	// >= 1 characters
+	FactionSymbol string   `json:"factionSymbol"`
+	Role          ShipRole `json:"role"`
+}
+
+// ShipRequirements The requirements for installation on a ship
+type ShipRequirements struct {
+	// Power The amount of power required from the reactor.
+	Power int `json:"power,omitempty"`
+	// Crew The number of crew required for operation.
+	Crew int `json:"crew,omitempty"`
+	// Slots The number of module slots required for installation.
+	Slots int `json:"slots,omitempty"`
+}
+
+// ShipRole The registered role of the ship
+type ShipRole string
+
+const (
+	ShipRoleFabricator  ShipRole = "FABRICATOR"
+	ShipRoleHarvester   ShipRole = "HARVESTER"
+	ShipRoleHauler      ShipRole = "HAULER"
+	ShipRoleInterceptor ShipRole = "INTERCEPTOR"
+	ShipRoleExcavator   ShipRole = "EXCAVATOR"
+	ShipRoleTransport   ShipRole = "TRANSPORT"
+	ShipRoleRepair      ShipRole = "REPAIR"
+	ShipRoleSurveyor    ShipRole = "SURVEYOR"
+	ShipRoleCommand     ShipRole = "COMMAND"
+	ShipRoleCarrier     ShipRole = "CARRIER"
+	ShipRolePatrol      ShipRole = "PATROL"
+	ShipRoleSatellite   ShipRole = "SATELLITE"
+	ShipRoleExplorer    ShipRole = "EXPLORER"
+	ShipRoleRefinery    ShipRole = "REFINERY"
+)
+
+// ShipType Type of ship
+type ShipType string
+
+const (
+	ShipTypeShipProbe             ShipType = "SHIP_PROBE"
+	ShipTypeShipMiningDrone       ShipType = "SHIP_MINING_DRONE"
+	ShipTypeShipInterceptor       ShipType = "SHIP_INTERCEPTOR"
+	ShipTypeShipLightHauler       ShipType = "SHIP_LIGHT_HAULER"
+	ShipTypeShipCommandFrigate    ShipType = "SHIP_COMMAND_FRIGATE"
+	ShipTypeShipExplorer          ShipType = "SHIP_EXPLORER"
+	ShipTypeShipHeavyFreighter    ShipType = "SHIP_HEAVY_FREIGHTER"
+	ShipTypeShipLightShuttle      ShipType = "SHIP_LIGHT_SHUTTLE"
+	ShipTypeShipOreHound          ShipType = "SHIP_ORE_HOUND"
+	ShipTypeShipRefiningFreighter ShipType = "SHIP_REFINING_FREIGHTER"
+)
+
+type Shipyard struct {
+	// Symbol The symbol of the shipyard. The symbol is the same as the waypoint where the shipyard is located.
+	// >= 1 characters
+	Symbol string `json:"symbol"`
+	// ShipTypes The list of ship types available for purchase at this shipyard.
+	ShipTypes []ShipyardShipType `json:"shipTypes"`
+	// Transactions The list of recent transactions at this shipyard.
+	Transactions []ShipyardTransaction `json:"transactions,omitempty"`
+	// Ships The ships that are currently available for purchase at the shipyard.
+	Ships []ShipyardShip `json:"ships,omitempty"`
+}
+
+type ShipyardShipType struct {
+	Type ShipType `json:"type,omitempty"`
+}
+
+type ShipyardShip struct {
+	Type          ShipType     `json:"type,omitempty"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	PurchasePrice int          `json:"purchasePrice"`
+	Frame         ShipFrame    `json:"frame"`
+	Reactor       ShipReactor  `json:"reactor"`
+	Engine        ShipEngine   `json:"engine"`
+	Modules       []ShipModule `json:"modules"`
+	Mounts        []ShipMount  `json:"mounts"`
+}
+
+type ShipyardTransaction struct {
+	// WaypointSymbol The symbol of the waypoint where the transaction took place.
+	WaypointSymbol string `json:"waypointSymbol"`
+	// ShipSymbol The symbol of the ship that was purchased.
+	ShipSymbol string `json:"shipSymbol"`
+	// Price The price of the transaction.
+	// >= 1
+	Price int `json:"price"`
+	// AgentSymbol The symbol of the agent that made the transaction.
+	AgentSymbol string `json:"agentSymbol"`
+	// Timestamp The timestamp of the transaction.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SupplyLevel A good's supply in a market.
+type SupplyLevel string
+
+const (
+	SupplyLevelScarce   SupplyLevel = "SCARCE"
+	SupplyLevelLimited  SupplyLevel = "LIMITED"
+	SupplyLevelModerate SupplyLevel = "MODERATE"
+	SupplyLevelAbundant SupplyLevel = "ABUNDANT"
+)
+
+// Survey A resource survey of a waypoint, detailing a specific extraction location and the types of resources that can be found there.
+type Survey struct {
+	// Signature A unique signature for the location of this survey. This signature is verified when attempting an extraction using this survey.
+	// >= 1 characters
+	Signature string `json:"signature"`
+	// Symbol The symbol of the waypoint that this survey is for.
+	// >= 1 characters
+	Symbol string `json:"symbol"`
+	// Deposits A list of deposits that can be found at this location.
+	Deposits []SurveyDeposit `json:"deposits"`
+	// Expiration The date and time when the survey expires. After this date and time, the survey will no longer be available for extraction.
+	Expiration time.Time `json:"expiration"`
+	// Size The size of the deposit. This value indicates how much can be extracted from the survey before it is exhausted.
+	Size SurveySize `json:"size"`
+}
+
+// SurveySize The size of the deposit. This value indicates how much can be extracted from the survey before it is exhausted.
+type SurveySize string
+
+const (
+	SurveySizeSmall    SurveySize = "SMALL"
+	SurveySizeModerate SurveySize = "MODERATE"
+	SurveySizeLarge    SurveySize = "LARGE"
+)
+
+// SurveyDeposit A surveyed deposit of a mineral or resource available for extraction.
+type SurveyDeposit struct {
+	// Symbol The symbol of the deposit.
+	Symbol string `json:"symbol"`
+}
+
+type System struct {
+	// >= 1 characters
+	Symbol string `json:"symbol"`
+	// >= 1 characters
+	SectorSymbol string           `json:"sectorSymbol"`
+	Type         SystemType       `json:"type"`
+	X            int              `json:"x"`
+	Y            int              `json:"y"`
+	Waypoints    []SystemWaypoint `json:"waypoints"`
+	Factions     []SystemFaction  `json:"factions"`
+}
+
+type SystemFaction struct {
+	// >= 1 characters
+	Symbol string `json:"symbol"`
+}
+
+// SystemType The type of waypoint.
+type SystemType string
+
+const (
+	SystemTypeNeutronStar SystemType = "NEUTRON_STAR"
+	SystemTypeRedStar     SystemType = "RED_STAR"
+	SystemTypeOrangeStar  SystemType = "ORANGE_STAR"
+	SystemTypeBlueStar    SystemType = "BLUE_STAR"
+	SystemTypeYoungStar   SystemType = "YOUNG_STAR"
+	SystemTypeWhiteDwarf  SystemType = "WHITE_DWARF"
+	SystemTypeBlackHole   SystemType = "BLACK_HOLE"
+	SystemTypeHypergiant  SystemType = "HYPERGIANT"
+	SystemTypeNebula      SystemType = "NEBULA"
+	SystemTypeUnstable    SystemType = "UNSTABLE"
+)
+
+type SystemWaypoint struct {
+	// >= 1 characters
+	Symbol string       `json:"symbol"`
+	Type   WaypointType `json:"type"`
+	X      int          `json:"x"`
+	Y      int          `json:"y"`
+}
+
+type TradeGood struct {
+	Symbol      TradeSymbol `json:"symbol"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+}
+
+// TradeSymbol The good's symbol.
+type TradeSymbol string
+
+const (
+	TradeSymbolPreciousStones        TradeSymbol = "PRECIOUS_STONES"
+	TradeSymbolQuartzSand            TradeSymbol = "QUARTZ_SAND"
+	TradeSymbolSiliconCrystals       TradeSymbol = "SILICON_CRYSTALS"
+	TradeSymbolAmmoniaIce            TradeSymbol = "AMMONIA_ICE"
+	TradeSymbolLiquidHydrogen        TradeSymbol = "LIQUID_HYDROGEN"
+	TradeSymbolLiquidNitrogen        TradeSymbol = "LIQUID_NITROGEN"
+	TradeSymbolIceWater              TradeSymbol = "ICE_WATER"
+	TradeSymbolExoticMatter          TradeSymbol = "EXOTIC_MATTER"
+	TradeSymbolAdvancedCircuitry     TradeSymbol = "ADVANCED_CIRCUITRY"
+	TradeSymbolGravitonEmitters      TradeSymbol = "GRAVITON_EMITTERS"
+	TradeSymbolIron                  TradeSymbol = "IRON"
+	TradeSymbolIronOre               TradeSymbol = "IRON_ORE"
+	TradeSymbolCopper                TradeSymbol = "COPPER"
+	TradeSymbolCopperOre             TradeSymbol = "COPPER_ORE"
+	TradeSymbolAluminum              TradeSymbol = "ALUMINUM"
+	TradeSymbolAluminumOre           TradeSymbol = "ALUMINUM_ORE"
+	TradeSymbolSilver                TradeSymbol = "SILVER"
+	TradeSymbolSilverOre             TradeSymbol = "SILVER_ORE"
+	TradeSymbolGold                  TradeSymbol = "GOLD"
+	TradeSymbolGoldOre               TradeSymbol = "GOLD_ORE"
+	TradeSymbolPlatinum              TradeSymbol = "PLATINUM"
+	TradeSymbolPlatinumOre           TradeSymbol = "PLATINUM_ORE"
+	TradeSymbolDiamonds              TradeSymbol = "DIAMONDS"
+	TradeSymbolUranite               TradeSymbol = "URANITE"
+	TradeSymbolUraniteOre            TradeSymbol = "URANITE_ORE"
+	TradeSymbolMeritium              TradeSymbol = "MERITIUM"
+	TradeSymbolMeritiumOre           TradeSymbol = "MERITIUM_ORE"
+	TradeSymbolHydrocarbon           TradeSymbol = "HYDROCARBON"
+	TradeSymbolAntimatter            TradeSymbol = "ANTIMATTER"
+	TradeSymbolFertilizers           TradeSymbol = "FERTILIZERS"
+	TradeSymbolFabrics               TradeSymbol = "FABRICS"
+	TradeSymbolFood                  TradeSymbol = "FOOD"
+	TradeSymbolJewelry               TradeSymbol = "JEWELRY"
+	TradeSymbolMachinery             TradeSymbol = "MACHINERY"
+	TradeSymbolFirearms              TradeSymbol = "FIREARMS"
+	TradeSymbolAssaultRifles         TradeSymbol = "ASSAULT_RIFLES"
+	TradeSymbolMilitaryEquipment     TradeSymbol = "MILITARY_EQUIPMENT"
+	TradeSymbolExplosives            TradeSymbol = "EXPLOSIVES"
+	TradeSymbolLabInstruments        TradeSymbol = "LAB_INSTRUMENTS"
+	TradeSymbolAmmunition            TradeSymbol = "AMMUNITION"
+	TradeSymbolElectronics           TradeSymbol = "ELECTRONICS"
+	TradeSymbolShipPlating           TradeSymbol = "SHIP_PLATING"
+	TradeSymbolEquipment             TradeSymbol = "EQUIPMENT"
+	TradeSymbolFuel                  TradeSymbol = "FUEL"
+	TradeSymbolMedicine              TradeSymbol = "MEDICINE"
+	TradeSymbolDrugs                 TradeSymbol = "DRUGS"
+	TradeSymbolClothing              TradeSymbol = "CLOTHING"
+	TradeSymbolMicroprocessors       TradeSymbol = "MICROPROCESSORS"
+	TradeSymbolPlastics              TradeSymbol = "PLASTICS"
+	TradeSymbolPolynucleotides       TradeSymbol = "POLYNUCLEOTIDES"
+	TradeSymbolBiocomposites         TradeSymbol = "BIOCOMPOSITES"
+	TradeSymbolNanobots              TradeSymbol = "NANOBOTS"
+	TradeSymbolAiMainframes          TradeSymbol = "AI_MAINFRAMES"
+	TradeSymbolQuantumDrives         TradeSymbol = "QUANTUM_DRIVES"
+	TradeSymbolRoboticDrones         TradeSymbol = "ROBOTIC_DRONES"
+	TradeSymbolCyberImplants         TradeSymbol = "CYBER_IMPLANTS"
+	TradeSymbolGeneTherapeutics      TradeSymbol = "GENE_THERAPEUTICS"
+	TradeSymbolNeuralChips           TradeSymbol = "NEURAL_CHIPS"
+	TradeSymbolMoodRegulators        TradeSymbol = "MOOD_REGULATORS"
+	TradeSymbolViralAgents           TradeSymbol = "VIRAL_AGENTS"
+	TradeSymbolMicroFusionGenerators TradeSymbol = "MICRO_FUSION_GENERATORS"
+	TradeSymbolSupergrains           TradeSymbol = "SUPERGRAINS"
+	TradeSymbolLaserRifles           TradeSymbol = "LASER_RIFLES"
+	TradeSymbolHolographics          TradeSymbol = "HOLOGRAPHICS"
+	TradeSymbolShipSalvage           TradeSymbol = "SHIP_SALVAGE"
+	TradeSymbolRelicTech             TradeSymbol = "RELIC_TECH"
+	TradeSymbolNovelLifeforms        TradeSymbol = "NOVEL_LIFEFORMS"
+	TradeSymbolBotanicalSpecimens    TradeSymbol = "BOTANICAL_SPECIMENS"
+	TradeSymbolCulturalArtifacts     TradeSymbol = "CULTURAL_ARTIFACTS"
+)
+
+// Waypoint A waypoint is a location that ships can travel to such as a Planet, Moon or Space Station.
+type Waypoint struct {
+	// >= 1 characters
+	Symbol string       `json:"symbol"`
+	Type   WaypointType `json:"type"`
+	// >= 1 characters
+	SystemSymbol string `json:"systemSymbol"`
+	X            int    `json:"x"`
+	Y            int    `json:"y"`
+	// Orbitals Waypoints that orbit this waypoint.
+	Orbitals []WaypointOrbital `json:"orbitals"`
+	Faction  *WaypointFaction  `json:"faction,omitempty"`
+	// Traits The traits of the waypoint.
+	Traits []WaypointTrait `json:"traits"`
+	Chart  *Chart          `json:"chart,omitempty"`
+}
+
+// WaypointFaction The faction that controls the waypoint.
+type WaypointFaction struct {
+	// >= 1 characters
+	Symbol string `json:"symbol"`
+}
+
+// WaypointOrbital An orbital is another waypoint that orbits a parent waypoint.
+type WaypointOrbital struct {
+	// >= 1 characters
+	Symbol string `json:"symbol"`
+}
+
+type WaypointTrait struct {
+	// Symbol The unique identifier of the trait.
+	Symbol string `json:"symbol"`
+	// Name The name of the trait.
+	Name string `json:"name"`
+	// Description A description of the trait.
+	Description string `json:"description"`
+}
+
+// WaypointType The type of waypoint.
+type WaypointType string
+
+const (
+	WaypointTypePlanet         WaypointType = "PLANET"
+	WaypointTypeGasGiant       WaypointType = "GAS_GIANT"
+	WaypointTypeMoon           WaypointType = "MOON"
+	WaypointTypeOrbitalStation WaypointType = "ORBITAL_STATION"
+	WaypointTypeJumpGate       WaypointType = "JUMP_GATE"
+	WaypointTypeAsteroidField  WaypointType = "ASTEROID_FIELD"
+	WaypointTypeNebula         WaypointType = "NEBULA"
+	WaypointTypeDebrisField    WaypointType = "DEBRIS_FIELD"
+	WaypointTypeGravityWell    WaypointType = "GRAVITY_WELL"
+)
@@ -0,0 +1,412 @@
+package navigation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/internal/wait"
+	"spacetradersgo/v2/models"
+	"time"
+)
+
+// ErrNoCheckpoint is returned by Resume when no route is in progress for
+// the ship.
+var ErrNoCheckpoint = errors.New("navigation: no checkpoint")
+
+// Checkpoint records how far a ship has got through a Plan.
+type Checkpoint struct {
+	Ship string `json:"ship"`
+	Plan Plan   `json:"plan"`
+	// Next is the index of the first step not known to be done.
+	Next    int       `json:"next"`
+	Updated time.Time `json:"updated"`
+}
+
+// CheckpointStore persists checkpoints between runs.
+type CheckpointStore interface {
+	// Load returns the ship's checkpoint, or nil and no error if there is
+	// none.
+	Load(ship string) (*Checkpoint, error)
+	Save(cp *Checkpoint) error
+	// Delete removes the ship's checkpoint, if any.
+	Delete(ship string) error
+}
+
+// FileCheckpoints keeps one JSON file per ship in Dir.
+type FileCheckpoints struct {
+	Dir string
+}
+
+func (f FileCheckpoints) path(ship string) string {
+	return filepath.Join(f.Dir, ship+".json")
+}
+
+func (f FileCheckpoints) Load(ship string) (*Checkpoint, error) {
+	b, err := os.ReadFile(f.path(ship))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, fmt.Errorf("navigation: checkpoint %s: %w", f.path(ship), err)
+	}
+	return cp, nil
+}
+
+// Save writes the checkpoint to a temporary file first and renames it into
+// place, so a crash never leaves a torn checkpoint behind.
+func (f FileCheckpoints) Save(cp *Checkpoint) error {
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.path(cp.Ship) + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path(cp.Ship))
+}
+
+func (f FileCheckpoints) Delete(ship string) error {
+	err := os.Remove(f.path(ship))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+type Phase string
+
+const (
+	PhaseStarted Phase = "STARTED"
+	// PhaseWaiting is sent while the ship is in transit or on cooldown,
+	// with Until set to when the executor next checks on it.
+	PhaseWaiting   Phase = "WAITING"
+	PhaseCompleted Phase = "COMPLETED"
+	// PhaseSkipped is sent on resume for steps the ship's nav shows are
+	// done already.
+	PhaseSkipped Phase = "SKIPPED"
+	PhaseFailed  Phase = "FAILED"
+)
+
+// Event reports the progress of an Executor.
+type Event struct {
+	Ship  string
+	Index int
+	Total int
+	Step  Step
+	Phase Phase
+	// Nav is the ship's nav as last seen.
+	Nav   models.ShipNav
+	Until time.Time
+	Err   error
+}
+
+// Executor drives ships through plans from PlanRoute or PlanJourney:
+// orbiting and docking as each step needs, switching flight modes,
+// navigating, refuelling, jumping and warping, and waiting out flights and
+// cooldowns in between.
+//
+// With a CheckpointStore, progress is saved after every step, and Resume
+// picks a route up again after a crash or restart, reconciling the
+// checkpoint with the ship's actual nav.
+type Executor struct {
+	client      fleets.FleetsClient
+	token       string
+	checkpoints CheckpointStore
+	progress    func(Event)
+	margin      time.Duration
+}
+
+type executorOpts func(*Executor)
+
+var (
+	defaultExecutorOpts = []executorOpts{
+		WithWaitMargin(500 * time.Millisecond),
+	}
+)
+
+// WithCheckpoints saves progress to store, which makes Resume possible.
+func WithCheckpoints(store CheckpointStore) executorOpts {
+	return func(e *Executor) {
+		e.checkpoints = store
+	}
+}
+
+// WithProgress calls fn with every Event. fn runs on the executing
+// goroutine and should return quickly.
+func WithProgress(fn func(Event)) executorOpts {
+	return func(e *Executor) {
+		e.progress = fn
+	}
+}
+
+// WithWaitMargin sets how long to wait past an expected arrival or cooldown
+// expiry before carrying on.
+func WithWaitMargin(margin time.Duration) executorOpts {
+	return func(e *Executor) {
+		e.margin = margin
+	}
+}
+
+// NewExecutor returns an Executor acting for the agent behind token. A
+// client that is a fleets.ShipLookup, such as a FleetState, saves the nav
+// lookups before docking and orbiting.
+func NewExecutor(client fleets.FleetsClient, token string, opts ...executorOpts) *Executor {
+	e := &Executor{client: client, token: token}
+	for _, opt := range append(defaultExecutorOpts, opts...) {
+		opt(e)
+	}
+	return e
+}
+
+// Execute drives the ship through plan and returns its nav at the end. Any
+// checkpoint left for the ship is replaced, and removed once the plan is
+// done.
+func (e *Executor) Execute(ctx context.Context, ship string, plan *Plan) (models.ShipNav, error) {
+	cp := &Checkpoint{Ship: ship, Plan: *plan}
+	if err := e.save(cp); err != nil {
+		return models.ShipNav{}, err
+	}
+	return e.run(ctx, cp)
+}
+
+// Resume carries on with the ship's checkpointed plan. Steps that the
+// ship's nav shows were done before the checkpoint was saved are skipped,
+// and a flight still under way is waited out.
+func (e *Executor) Resume(ctx context.Context, ship string) (models.ShipNav, error) {
+	if e.checkpoints == nil {
+		return models.ShipNav{}, fmt.Errorf("%w for %s: executor has no checkpoint store", ErrNoCheckpoint, ship)
+	}
+	cp, err := e.checkpoints.Load(ship)
+	if err != nil {
+		return models.ShipNav{}, err
+	}
+	if cp == nil {
+		return models.ShipNav{}, fmt.Errorf("%w for %s", ErrNoCheckpoint, ship)
+	}
+	return e.run(ctx, cp)
+}
+
+func (e *Executor) run(ctx context.Context, cp *Checkpoint) (models.ShipNav, error) {
+	resp, err := e.client.GetShipNav(ctx, &fleets.GetShipNavRequest{Token: e.token, ShipID: cp.Ship})
+	if err != nil {
+		return models.ShipNav{}, err
+	}
+	nav := resp.Nav
+
+	if done := reconcile(cp.Plan.Steps, cp.Next, nav); done > cp.Next {
+		for i := cp.Next; i < done; i++ {
+			e.emit(cp, i, PhaseSkipped, nav, nil)
+		}
+		cp.Next = done
+		if err := e.save(cp); err != nil {
+			return nav, err
+		}
+	}
+	if nav.Status == models.ShipNavStatusInTransit {
+		index := cp.Next - 1
+		if index < 0 {
+			index = 0
+		}
+		// The flight started before this run, so there is no local time it
+		// started at to time it from as flightEnd does. The server's
+		// arrival time is trusted against the local clock instead: a clock
+		// running ahead only means checking on the ship early and again
+		// later, one running behind means waiting that much too long.
+		if nav, err = e.arrive(ctx, cp, index, nav, nav.Route.Arrival); err != nil {
+			return nav, err
+		}
+	}
+
+	for i := cp.Next; i < len(cp.Plan.Steps); i++ {
+		e.emit(cp, i, PhaseStarted, nav, nil)
+		next, err := e.step(ctx, cp, i, nav)
+		if err != nil {
+			e.emit(cp, i, PhaseFailed, next, err)
+			return next, fmt.Errorf("navigation: step %d (%s) for %s: %w", i, cp.Plan.Steps[i].Kind, cp.Ship, err)
+		}
+		nav = next
+		cp.Next = i + 1
+		if err := e.save(cp); err != nil {
+			return nav, err
+		}
+		e.emit(cp, i, PhaseCompleted, nav, nil)
+	}
+
+	if e.checkpoints != nil {
+		return nav, e.checkpoints.Delete(cp.Ship)
+	}
+	return nav, nil
+}
+
+// reconcile returns the index of the first step still to do, given that
+// steps before next are done. If the ship is at, or on its way to, where
+// the next movement goes, that movement and anything before it happened
+// after the checkpoint was saved.
+func reconcile(steps []Step, next int, nav models.ShipNav) int {
+	for i := next; i < len(steps); i++ {
+		switch steps[i].Kind {
+		case StepNavigate, StepWarp:
+			if nav.WaypointSymbol == steps[i].Waypoint {
+				return i + 1
+			}
+			return next
+		case StepJump:
+			if nav.SystemSymbol == steps[i].System {
+				return i + 1
+			}
+			return next
+		}
+	}
+	return next
+}
+
+func (e *Executor) step(ctx context.Context, cp *Checkpoint, i int, nav models.ShipNav) (models.ShipNav, error) {
+	step := cp.Plan.Steps[i]
+	switch step.Kind {
+	case StepFlightMode:
+		if nav.FlightMode == step.Mode {
+			return nav, nil
+		}
+		resp, err := e.client.PatchShipNav(ctx, &fleets.PatchShipNavRequest{Token: e.token, ShipID: cp.Ship, FlightMode: step.Mode})
+		if err != nil {
+			return nav, err
+		}
+		return resp.Nav, nil
+
+	case StepNavigate:
+		if nav.WaypointSymbol == step.Waypoint {
+			return nav, nil
+		}
+		if _, err := fleets.EnsureInOrbit(ctx, e.client, e.token, cp.Ship); err != nil {
+			return nav, err
+		}
+		resp, err := e.client.NavigateShip(ctx, &fleets.NavigateShipRequest{Token: e.token, ShipID: cp.Ship, WaypointSymbol: step.Waypoint})
+		if err != nil {
+			return nav, err
+		}
+		return e.arrive(ctx, cp, i, resp.Data.Nav, flightEnd(resp.Data.Nav))
+
+	case StepWarp:
+		if nav.WaypointSymbol == step.Waypoint {
+			return nav, nil
+		}
+		if _, err := fleets.EnsureInOrbit(ctx, e.client, e.token, cp.Ship); err != nil {
+			return nav, err
+		}
+		resp, err := e.client.WarpShip(ctx, &fleets.WarpShipRequest{Token: e.token, ShipID: cp.Ship, WaypointSymbol: step.Waypoint})
+		if err != nil {
+			return nav, err
+		}
+		return e.arrive(ctx, cp, i, resp.Data.Nav, flightEnd(resp.Data.Nav))
+
+	case StepJump:
+		if nav.SystemSymbol == step.System {
+			return nav, nil
+		}
+		if _, err := fleets.EnsureInOrbit(ctx, e.client, e.token, cp.Ship); err != nil {
+			return nav, err
+		}
+		if err := e.cooldown(ctx, cp, i, nav); err != nil {
+			return nav, err
+		}
+		resp, err := e.client.JumpShip(ctx, &fleets.JumpShipRequest{Token: e.token, ShipID: cp.Ship, SystemSymbol: step.System})
+		if err != nil {
+			return nav, err
+		}
+		return resp.Data.Nav, nil
+
+	case StepRefuel:
+		docked, err := fleets.EnsureDocked(ctx, e.client, e.token, cp.Ship)
+		if err != nil {
+			return nav, err
+		}
+		if _, err := e.client.RefuelShip(ctx, &fleets.RefuelShipRequest{Token: e.token, ShipID: cp.Ship}); err != nil {
+			return docked, err
+		}
+		return docked, nil
+	}
+	return nav, fmt.Errorf("unknown step kind %q", step.Kind)
+}
+
+// flightEnd is when a flight that has just started should be over by the
+// local clock, timed from the route's server departure and arrival times.
+func flightEnd(nav models.ShipNav) time.Time {
+	route := nav.Route
+	if route.DepartureTime.IsZero() {
+		return route.Arrival
+	}
+	return time.Now().Add(route.Arrival.Sub(route.DepartureTime))
+}
+
+// arrive waits until the ship is no longer in transit, checking on it at
+// deadline plus the margin and again, with a growing margin, for as long
+// as it is.
+func (e *Executor) arrive(ctx context.Context, cp *Checkpoint, i int, nav models.ShipNav, deadline time.Time) (models.ShipNav, error) {
+	margin := e.margin
+	for nav.Status == models.ShipNavStatusInTransit {
+		until := deadline.Add(margin)
+		e.emitUntil(cp, i, nav, until)
+		if err := wait.Until(ctx, until); err != nil {
+			return nav, err
+		}
+		resp, err := e.client.GetShipNav(ctx, &fleets.GetShipNavRequest{Token: e.token, ShipID: cp.Ship})
+		if err != nil {
+			return nav, err
+		}
+		nav = resp.Nav
+		deadline = time.Now()
+		margin *= 2
+		if margin <= 0 {
+			margin = time.Second
+		}
+	}
+	return nav, nil
+}
+
+// cooldown waits out the ship's reactor cooldown, which blocks jumps.
+func (e *Executor) cooldown(ctx context.Context, cp *Checkpoint, i int, nav models.ShipNav) error {
+	resp, err := e.client.GetShipCooldown(ctx, &fleets.GetShipCooldownRequest{Token: e.token, ShipID: cp.Ship})
+	if err != nil {
+		return err
+	}
+	if !resp.IsOnCooldown || resp.Cooldown.RemainingSeconds <= 0 {
+		return nil
+	}
+	until := time.Now().Add(time.Duration(resp.Cooldown.RemainingSeconds)*time.Second + e.margin)
+	e.emitUntil(cp, i, nav, until)
+	return wait.Until(ctx, until)
+}
+
+func (e *Executor) save(cp *Checkpoint) error {
+	if e.checkpoints == nil {
+		return nil
+	}
+	cp.Updated = time.Now()
+	return e.checkpoints.Save(cp)
+}
+
+func (e *Executor) emit(cp *Checkpoint, i int, phase Phase, nav models.ShipNav, err error) {
+	if e.progress == nil || i >= len(cp.Plan.Steps) {
+		return
+	}
+	e.progress(Event{Ship: cp.Ship, Index: i, Total: len(cp.Plan.Steps), Step: cp.Plan.Steps[i], Phase: phase, Nav: nav, Err: err})
+}
+
+func (e *Executor) emitUntil(cp *Checkpoint, i int, nav models.ShipNav, until time.Time) {
+	if e.progress == nil || i >= len(cp.Plan.Steps) {
+		return
+	}
+	e.progress(Event{Ship: cp.Ship, Index: i, Total: len(cp.Plan.Steps), Step: cp.Plan.Steps[i], Phase: PhaseWaiting, Nav: nav, Until: until})
+}
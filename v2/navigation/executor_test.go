@@ -0,0 +1,171 @@
+package navigation_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/fake"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+	"spacetradersgo/v2/systems"
+)
+
+// landing stands in for time passing on the fake server. A flight the
+// executor starts lands at once, and one it finds under way when it
+// resumes lands the next time it checks on the ship.
+type landing struct {
+	fleets.FleetsClient
+	clock *fake.ManualClock
+
+	mu        sync.Mutex
+	navChecks int
+	navigated []string
+}
+
+func (c *landing) GetShipNav(ctx context.Context, req *fleets.GetShipNavRequest) (*fleets.GetShipNavResponse, error) {
+	c.mu.Lock()
+	c.navChecks++
+	first := c.navChecks == 1
+	c.mu.Unlock()
+	resp, err := c.FleetsClient.GetShipNav(ctx, req)
+	if err != nil || first || resp.Nav.Status != models.ShipNavStatusInTransit {
+		return resp, err
+	}
+	c.clock.Set(resp.Nav.Route.Arrival)
+	return c.FleetsClient.GetShipNav(ctx, req)
+}
+
+func (c *landing) NavigateShip(ctx context.Context, req *fleets.NavigateShipRequest) (*fleets.NavigateShipResponse, error) {
+	c.mu.Lock()
+	c.navigated = append(c.navigated, req.WaypointSymbol)
+	c.mu.Unlock()
+	resp, err := c.FleetsClient.NavigateShip(ctx, req)
+	if err == nil {
+		c.clock.Set(resp.Data.Nav.Route.Arrival)
+		resp.Data.Nav.Route.Arrival = resp.Data.Nav.Route.DepartureTime
+		resp.SetRaw(nil)
+	}
+	return resp, err
+}
+
+func TestExecutorResumesMidFlight(t *testing.T) {
+	ctx := context.Background()
+	// The server's clock runs an hour behind, so its arrival times are in
+	// the past by the local clock and the executor never sleeps for long.
+	clock := fake.NewManualClock(time.Now().Add(-time.Hour).Truncate(time.Second))
+	srv := fake.NewServer(fake.WithSeed(7), fake.WithClock(clock))
+	defer srv.Close()
+	token, err := srv.Register("PILOT", "COSMIC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl := fleets.NewFleets(fleets.WithHTTPClient(srv.Client()))
+	sys := systems.NewSystems(systems.WithHTTPClient(srv.Client()))
+
+	list, err := fl.ListShips(ctx, &fleets.ListShipsRequest{Token: token})
+	if err != nil {
+		t.Fatalf("ListShips: %v", err)
+	}
+	ship := list.Ships[0]
+	hq := ship.Nav.WaypointSymbol
+	wps, err := sys.ListWaypoints(ctx, &systems.ListWaypointsRequest{Token: token, SystemID: ship.Nav.SystemSymbol, NumPerPage: 20})
+	if err != nil {
+		t.Fatalf("ListWaypoints: %v", err)
+	}
+	// Every fake marketplace sells fuel; pick one away from the HQ.
+	var here models.Waypoint
+	for _, wp := range wps.Waypoints {
+		if wp.Symbol == hq {
+			here = wp
+		}
+	}
+	var market string
+	for _, wp := range wps.Waypoints {
+		for _, trait := range wp.Traits {
+			if trait.Symbol == "MARKETPLACE" && market == "" && navigation.WaypointDistance(here, wp) > 0 {
+				market = wp.Symbol
+			}
+		}
+	}
+	if market == "" {
+		t.Fatal("no marketplace away from the HQ")
+	}
+
+	plan := &navigation.Plan{Steps: []navigation.Step{
+		{Kind: navigation.StepNavigate, Waypoint: market, Mode: models.ShipNavFlightModeCruise},
+		{Kind: navigation.StepRefuel, Waypoint: market},
+		{Kind: navigation.StepNavigate, Waypoint: hq, Mode: models.ShipNavFlightModeCruise},
+	}}
+	store := navigation.FileCheckpoints{Dir: t.TempDir()}
+	// A run that saved its checkpoint and sent the ship on its way, then
+	// stopped before it could record that.
+	if err := store.Save(&navigation.Checkpoint{Ship: ship.Symbol, Plan: *plan}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fl.OrbitShip(ctx, &fleets.OrbitShipRequest{Token: token, ShipID: ship.Symbol}); err != nil {
+		t.Fatalf("OrbitShip: %v", err)
+	}
+	flight, err := fl.NavigateShip(ctx, &fleets.NavigateShipRequest{Token: token, ShipID: ship.Symbol, WaypointSymbol: market})
+	if err != nil {
+		t.Fatalf("NavigateShip: %v", err)
+	}
+	burned := ship.Fuel.Current - flight.Data.Fuel.Current
+
+	client := &landing{FleetsClient: fl, clock: clock}
+	var events []navigation.Event
+	ex := navigation.NewExecutor(client, token, navigation.WithCheckpoints(store), navigation.WithWaitMargin(time.Millisecond),
+		navigation.WithProgress(func(e navigation.Event) { events = append(events, e) }))
+	nav, err := ex.Resume(ctx, ship.Symbol)
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	var phases []string
+	for _, e := range events {
+		phases = append(phases, string(e.Phase))
+	}
+	want := []navigation.Phase{
+		navigation.PhaseSkipped, navigation.PhaseWaiting,
+		navigation.PhaseStarted, navigation.PhaseCompleted,
+		navigation.PhaseStarted, navigation.PhaseWaiting, navigation.PhaseCompleted,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("phases = %v, want %v", phases, want)
+	}
+	for i, e := range events {
+		if e.Phase != want[i] {
+			t.Fatalf("phases = %v, want %v", phases, want)
+		}
+	}
+	if events[0].Index != 0 || events[1].Index != 0 {
+		t.Errorf("skipped step %d and waited on step %d, want the first flight", events[0].Index, events[1].Index)
+	}
+	// The flight under way is waited out by the server's arrival time.
+	if until := flight.Data.Nav.Route.Arrival.Add(time.Millisecond); !events[1].Until.Equal(until) {
+		t.Errorf("waited until %v, want the arrival plus the margin, %v", events[1].Until, until)
+	}
+	if len(client.navigated) != 1 || client.navigated[0] != hq {
+		t.Errorf("navigated to %v, want only the flight back to %s", client.navigated, hq)
+	}
+
+	if nav.WaypointSymbol != hq {
+		t.Errorf("ended at %s, want %s", nav.WaypointSymbol, hq)
+	}
+	got, err := fl.GetShip(ctx, &fleets.GetShipRequest{Token: token, ShipID: ship.Symbol})
+	if err != nil {
+		t.Fatalf("GetShip: %v", err)
+	}
+	// Refuelled at the market, the ship burned only the flight back.
+	if back := got.Ship.Fuel.Capacity - got.Ship.Fuel.Current; back != burned {
+		t.Errorf("fuel %d of %d, want %d burned since refuelling", got.Ship.Fuel.Current, got.Ship.Fuel.Capacity, burned)
+	}
+	if got.Ship.Nav.Status == models.ShipNavStatusInTransit {
+		t.Errorf("ship still in transit")
+	}
+	if cp, err := store.Load(ship.Symbol); err != nil || cp != nil {
+		t.Errorf("checkpoint after finishing = %+v, %v, want none", cp, err)
+	}
+}
@@ -0,0 +1,416 @@
+package navigation
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/systems"
+	"strings"
+	"time"
+)
+
+const (
+	// StepJump jumps to System with JumpShip, through a gate or with a
+	// jump drive. Time is the cooldown the jump leaves.
+	StepJump StepKind = "JUMP"
+	// StepWarp warps to Waypoint in System with WarpShip.
+	StepWarp StepKind = "WARP"
+)
+
+// JumpDriveAntimatter is the antimatter a jump drive is assumed to use per
+// jump when estimating a journey. Gate jumps use none.
+const JumpDriveAntimatter = 1
+
+// Galaxy is a graph of systems joined by jump gates.
+type Galaxy struct {
+	systems map[string]*galaxySystem
+	// system maps each known waypoint to its system.
+	system map[string]string
+}
+
+type galaxySystem struct {
+	models.System
+	gate        string
+	connections []string
+	stations    map[string]bool
+}
+
+// NewGalaxy builds a galaxy of systems, as returned by ListSystems, without
+// any gate connections yet; add them with SetJumpGate.
+func NewGalaxy(list []models.System) *Galaxy {
+	g := &Galaxy{systems: map[string]*galaxySystem{}, system: map[string]string{}}
+	for _, sys := range list {
+		gs := &galaxySystem{System: sys, stations: map[string]bool{}}
+		for _, wp := range sys.Waypoints {
+			g.system[wp.Symbol] = sys.Symbol
+			if wp.Type == models.WaypointTypeJumpGate && gs.gate == "" {
+				gs.gate = wp.Symbol
+			}
+		}
+		g.systems[sys.Symbol] = gs
+	}
+	return g
+}
+
+// LoadGalaxy lists every system and fetches every jump gate. That is one
+// request per page of systems plus one per gate, so on the live API it
+// takes a while; cache the result.
+func LoadGalaxy(ctx context.Context, client systems.SystemsClient, token string) (*Galaxy, error) {
+	var list []models.System
+	for page := 1; ; page++ {
+		resp, err := client.ListSystems(ctx, &systems.ListSystemsRequest{Token: token, Page: page, NumPerPage: 20})
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, resp.Systems...)
+		if len(resp.Systems) == 0 || len(list) >= resp.Meta.Total {
+			break
+		}
+	}
+
+	g := NewGalaxy(list)
+	for _, sys := range list {
+		gate := g.systems[sys.Symbol].gate
+		if gate == "" {
+			continue
+		}
+		resp, err := client.GetJumpGate(ctx, &systems.GetJumpGateRequest{Token: token, SystemID: sys.Symbol, WaypointID: gate})
+		if err != nil {
+			return nil, err
+		}
+		g.SetJumpGate(gate, resp.JumpGate)
+	}
+	return g, nil
+}
+
+// SetJumpGate records the connections of the gate at waypoint.
+func (g *Galaxy) SetJumpGate(waypoint string, gate models.JumpGate) {
+	gs, ok := g.systems[g.system[waypoint]]
+	if !ok {
+		return
+	}
+	gs.gate = waypoint
+	gs.connections = gs.connections[:0]
+	for _, cs := range gate.ConnectedSystems {
+		gs.connections = append(gs.connections, cs.Symbol)
+	}
+}
+
+// AddMarkets records markets, as returned by GetMarket, so journeys can
+// refuel at those that trade FUEL.
+func (g *Galaxy) AddMarkets(markets ...models.Market) {
+	for _, m := range markets {
+		gs, ok := g.systems[g.system[m.Symbol]]
+		if !ok || !SellsFuel(m) {
+			continue
+		}
+		gs.stations[m.Symbol] = true
+	}
+}
+
+// System returns a system of the galaxy.
+func (g *Galaxy) System(symbol string) (models.System, bool) {
+	gs, ok := g.systems[symbol]
+	if !ok {
+		return models.System{}, false
+	}
+	return gs.System, true
+}
+
+// Connections returns the systems the system's jump gate leads to.
+func (g *Galaxy) Connections(symbol string) []string {
+	gs, ok := g.systems[symbol]
+	if !ok {
+		return nil
+	}
+	return append([]string(nil), gs.connections...)
+}
+
+// position is a waypoint in the galaxy with its in-system coordinates.
+type position struct {
+	system   string
+	waypoint string
+	x, y     int
+}
+
+func (g *Galaxy) position(waypoint string) (position, bool) {
+	gs, ok := g.systems[g.system[waypoint]]
+	if !ok {
+		return position{}, false
+	}
+	for _, wp := range gs.Waypoints {
+		if wp.Symbol == waypoint {
+			return position{system: gs.Symbol, waypoint: wp.Symbol, x: wp.X, y: wp.Y}, true
+		}
+	}
+	return position{}, false
+}
+
+// moduleRange returns the range of the ship's first module whose symbol
+// starts with prefix, e.g. "MODULE_WARP_DRIVE".
+func moduleRange(ship models.Ship, prefix string) (int, bool) {
+	for _, m := range ship.Modules {
+		if strings.HasPrefix(m.Symbol, prefix) {
+			return m.Range, true
+		}
+	}
+	return 0, false
+}
+
+// PlanJourney finds a route for ship to a waypoint in any system of the
+// galaxy, combining in-system navigation, gate jumps and, when the ship has
+// the modules, jump drive jumps and warps. A drive reaches the systems
+// within its module's Range; one without a range reaches none. It accepts
+// the same options as PlanRoute. Fuel stations are the markets added with
+// AddMarkets that trade FUEL, plus any given with WithFuelStations or
+// WithMarkets.
+//
+// The estimated time assumes the ship waits out each jump cooldown before
+// moving on, which overstates it a little when the next leg is a flight.
+func PlanJourney(ship models.Ship, g *Galaxy, destination string, opts ...plannerOpts) (*Plan, error) {
+	p := &planner{modes: FlightModes, stations: map[string]bool{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	start, ok := g.position(ship.Nav.WaypointSymbol)
+	if !ok {
+		return nil, fmt.Errorf("%w: ship %s is at %s, which is not in the galaxy", ErrNoRoute, ship.Symbol, ship.Nav.WaypointSymbol)
+	}
+	goal, ok := g.position(destination)
+	if !ok {
+		return nil, fmt.Errorf("%w: destination %s is not in the galaxy", ErrNoRoute, destination)
+	}
+	jumpRange, jumpDrive := moduleRange(ship, "MODULE_JUMP_DRIVE")
+	warpRange, warpDrive := moduleRange(ship, "MODULE_WARP_DRIVE")
+	jumpDrive = jumpDrive && jumpRange > 0
+	warpDrive = warpDrive && warpRange > 0
+
+	// The heuristic is the least time any means of travel could need to
+	// cover the straight-line distance between systems: jumps cost at
+	// least a tenth of a second of cooldown per unit, warps their flight
+	// time at the fastest allowed mode.
+	perUnit := 0.1
+	if warpDrive {
+		for _, mode := range p.modes {
+			perUnit = math.Min(perUnit, Multiplier(mode)/float64(maxInt(ship.Engine.Speed, 1)))
+		}
+	}
+	goalSystem := g.systems[goal.system]
+	h := func(pos position) time.Duration {
+		if p.objective == Cheapest {
+			return 0
+		}
+		d := SystemDistance(g.systems[pos.system].System, goalSystem.System)
+		return time.Duration(d * perUnit * float64(time.Second))
+	}
+	stationAt := func(pos position) bool {
+		return g.systems[pos.system].stations[pos.waypoint] || p.stations[pos.waypoint]
+	}
+
+	capacity := ship.Fuel.Capacity
+	queue := &journeyQueue{objective: p.objective}
+	heap.Push(queue, &journey{at: start, fuel: ship.Fuel.Current, mode: ship.Nav.FlightMode, estimate: h(start)})
+	seen := map[string][]*journey{}
+
+	for queue.Len() > 0 {
+		j := heap.Pop(queue).(*journey)
+		key := j.at.waypoint
+		if dominatedJourney(seen[key], j) {
+			continue
+		}
+		seen[key] = append(seen[key], j)
+		if j.at.waypoint == goal.waypoint {
+			return j.plan(), nil
+		}
+		here := g.systems[j.at.system]
+
+		push := func(to position, fuel, antimatter int, t time.Duration, burn int, step Step) {
+			n := &journey{at: to, fuel: fuel, mode: j.mode, time: j.time + t, burn: j.burn + burn,
+				antimatter: j.antimatter + antimatter, steps: j.steps + 1, step: &step, prev: j}
+			if step.Mode != "" {
+				n.mode = step.Mode
+			}
+			n.estimate = n.time + h(to)
+			heap.Push(queue, n)
+		}
+
+		// Refuel.
+		if capacity > 0 && j.fuel < capacity && stationAt(j.at) {
+			push(j.at, capacity, 0, 0, 0, Step{Kind: StepRefuel, Waypoint: j.at.waypoint, Fuel: capacity - j.fuel})
+		}
+
+		// Fly within the system, to its gate, the destination or a fuel
+		// station.
+		var targets []position
+		if here.gate != "" {
+			if pos, ok := g.position(here.gate); ok {
+				targets = append(targets, pos)
+			}
+		}
+		if goal.system == here.Symbol {
+			targets = append(targets, goal)
+		}
+		for wp := range here.stations {
+			if pos, ok := g.position(wp); ok {
+				targets = append(targets, pos)
+			}
+		}
+		for wp := range p.stations {
+			if pos, ok := g.position(wp); ok && pos.system == here.Symbol && !here.stations[wp] {
+				targets = append(targets, pos)
+			}
+		}
+		for _, to := range targets {
+			if to.waypoint == j.at.waypoint {
+				continue
+			}
+			d := Distance(j.at.x, j.at.y, to.x, to.y)
+			for _, mode := range p.modes {
+				est := Leg(ship, d, mode)
+				if est.Fuel <= j.fuel {
+					push(to, j.fuel-est.Fuel, 0, est.Time, est.Fuel,
+						Step{Kind: StepNavigate, Waypoint: to.waypoint, Mode: mode, Time: est.Time, Fuel: est.Fuel})
+				}
+			}
+		}
+
+		// Jump through the gate.
+		if j.at.waypoint == here.gate {
+			for _, sym := range here.connections {
+				there, ok := g.systems[sym]
+				if !ok {
+					continue
+				}
+				if to, ok := g.position(there.gate); ok {
+					cd := JumpCooldown(SystemDistance(here.System, there.System))
+					push(to, j.fuel, 0, cd, 0, Step{Kind: StepJump, System: sym, Waypoint: to.waypoint, Time: cd})
+				}
+			}
+		}
+
+		// Jump drive and warp drive reach any system in range.
+		if (jumpDrive && j.at.waypoint != here.gate) || warpDrive {
+			for sym, there := range g.systems {
+				if sym == here.Symbol {
+					continue
+				}
+				d := SystemDistance(here.System, there.System)
+				if jumpDrive && j.at.waypoint != here.gate && d <= float64(jumpRange) {
+					if to, ok := g.position(there.gate); ok {
+						cd := JumpCooldown(d)
+						push(to, j.fuel, JumpDriveAntimatter, cd, 0, Step{Kind: StepJump, System: sym, Waypoint: to.waypoint, Time: cd})
+					}
+				}
+				if warpDrive && d <= float64(warpRange) {
+					arrival := there.gate
+					if sym == goal.system {
+						arrival = goal.waypoint
+					}
+					to, ok := g.position(arrival)
+					if !ok {
+						continue
+					}
+					for _, mode := range p.modes {
+						est := Leg(ship, d, mode)
+						if est.Fuel <= j.fuel {
+							push(to, j.fuel-est.Fuel, 0, est.Time, est.Fuel,
+								Step{Kind: StepWarp, System: sym, Waypoint: to.waypoint, Mode: mode, Time: est.Time, Fuel: est.Fuel})
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w from %s to %s", ErrNoRoute, ship.Nav.WaypointSymbol, destination)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// journey is a partial route across the galaxy, like label within a
+// system.
+type journey struct {
+	at         position
+	fuel       int
+	mode       models.ShipNavFlightMode
+	time       time.Duration
+	estimate   time.Duration
+	burn       int
+	antimatter int
+	steps      int
+	step       *Step
+	prev       *journey
+}
+
+func dominatedJourney(existing []*journey, j *journey) bool {
+	for _, e := range existing {
+		if e.fuel >= j.fuel && e.time <= j.time && e.burn <= j.burn && e.antimatter <= j.antimatter {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *journey) plan() *Plan {
+	plan := &Plan{Antimatter: j.antimatter}
+	var steps []Step
+	for ; j.step != nil; j = j.prev {
+		steps = append(steps, *j.step)
+		if (j.step.Kind == StepNavigate || j.step.Kind == StepWarp) && j.prev.mode != j.step.Mode {
+			steps = append(steps, Step{Kind: StepFlightMode, Mode: j.step.Mode})
+		}
+	}
+	plan.Steps = make([]Step, 0, len(steps))
+	for i := len(steps) - 1; i >= 0; i-- {
+		s := steps[i]
+		plan.Steps = append(plan.Steps, s)
+		plan.Time += s.Time
+		if s.Kind == StepNavigate || s.Kind == StepWarp {
+			plan.Fuel += s.Fuel
+		}
+	}
+	return plan
+}
+
+type journeyQueue struct {
+	objective Objective
+	journeys  []*journey
+}
+
+func (q *journeyQueue) Len() int { return len(q.journeys) }
+
+func (q *journeyQueue) Less(i, k int) bool {
+	a, b := q.journeys[i], q.journeys[k]
+	if q.objective == Cheapest {
+		if a.burn != b.burn {
+			return a.burn < b.burn
+		}
+		if a.antimatter != b.antimatter {
+			return a.antimatter < b.antimatter
+		}
+	}
+	if a.estimate != b.estimate {
+		return a.estimate < b.estimate
+	}
+	if a.burn != b.burn {
+		return a.burn < b.burn
+	}
+	return a.steps < b.steps
+}
+
+func (q *journeyQueue) Swap(i, k int) { q.journeys[i], q.journeys[k] = q.journeys[k], q.journeys[i] }
+
+func (q *journeyQueue) Push(x any) { q.journeys = append(q.journeys, x.(*journey)) }
+
+func (q *journeyQueue) Pop() any {
+	j := q.journeys[len(q.journeys)-1]
+	q.journeys = q.journeys[:len(q.journeys)-1]
+	return j
+}
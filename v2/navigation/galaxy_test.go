@@ -0,0 +1,146 @@
+package navigation_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+)
+
+// galaxy has X1-S1 and X1-S2, 100 apart with a gate each, and X1-S3 far
+// off without one. Gates are left unconnected unless linked.
+func galaxy(linked bool) *navigation.Galaxy {
+	g := navigation.NewGalaxy([]models.System{
+		{Symbol: "X1-S1", X: 0, Y: 0, Waypoints: []models.SystemWaypoint{
+			{Symbol: "X1-S1-A", Type: models.WaypointTypePlanet, X: 0, Y: 0},
+			{Symbol: "X1-S1-G", Type: models.WaypointTypeJumpGate, X: 10, Y: 0},
+		}},
+		{Symbol: "X1-S2", X: 100, Y: 0, Waypoints: []models.SystemWaypoint{
+			{Symbol: "X1-S2-G", Type: models.WaypointTypeJumpGate, X: 0, Y: 0},
+			{Symbol: "X1-S2-D", Type: models.WaypointTypePlanet, X: 20, Y: 0},
+		}},
+		{Symbol: "X1-S3", X: 1000, Y: 0, Waypoints: []models.SystemWaypoint{
+			{Symbol: "X1-S3-W", Type: models.WaypointTypePlanet, X: 5, Y: 5},
+		}},
+	})
+	if linked {
+		g.SetJumpGate("X1-S1-G", models.JumpGate{ConnectedSystems: []models.ConnectedSystem{{Symbol: "X1-S2", X: 100}}})
+		g.SetJumpGate("X1-S2-G", models.JumpGate{ConnectedSystems: []models.ConnectedSystem{{Symbol: "X1-S1"}}})
+	}
+	return g
+}
+
+// drive is a cruiser at X1-S1-A with a module of the given symbol and
+// range.
+func drive(fuel int, module string, reach int) models.Ship {
+	ship := cruiser("X1-S1-A", fuel, fuel)
+	if module != "" {
+		ship.Modules = []models.ShipModule{{Symbol: module, Range: reach}}
+	}
+	return ship
+}
+
+func TestPlanJourney(t *testing.T) {
+	cruise := navigation.WithFlightModes(models.ShipNavFlightModeCruise)
+	for _, tc := range []struct {
+		name       string
+		plan       func() (*navigation.Plan, error)
+		want       []string
+		time       time.Duration
+		antimatter int
+	}{
+		{
+			// 23s to the gate, a 70s cooldown for the 100 jumped and 32s on.
+			name: "through gates",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanJourney(drive(400, "", 0), galaxy(true), "X1-S2-D", cruise)
+			},
+			want: []string{"NAVIGATE X1-S1-G CRUISE 10", "JUMP X1-S2  0", "NAVIGATE X1-S2-D CRUISE 20"},
+			time: 125 * time.Second,
+		},
+		{
+			name: "refuelling at a market past the gate",
+			plan: func() (*navigation.Plan, error) {
+				g := galaxy(true)
+				g.AddMarkets(fuelMarket("X1-S2-G"))
+				return navigation.PlanJourney(cruiser("X1-S1-A", 15, 25), g, "X1-S2-D", cruise)
+			},
+			want: []string{"NAVIGATE X1-S1-G CRUISE 10", "JUMP X1-S2  0", "REFUEL X1-S2-G  20", "NAVIGATE X1-S2-D CRUISE 20"},
+		},
+		{
+			name: "with a jump drive",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanJourney(drive(400, "MODULE_JUMP_DRIVE_I", 500), galaxy(false), "X1-S2-D", cruise)
+			},
+			want:       []string{"JUMP X1-S2  0", "NAVIGATE X1-S2-D CRUISE 20"},
+			time:       102 * time.Second,
+			antimatter: 1,
+		},
+		{
+			name: "warping to a system without a gate",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanJourney(drive(1500, "MODULE_WARP_DRIVE_I", 2000), galaxy(true), "X1-S3-W", cruise)
+			},
+			want: []string{"WARP X1-S3-W CRUISE 1000"},
+		},
+		{
+			// Burning the 100 to X1-S2 takes 57s, beating the gates.
+			name: "fastest warps at burn",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanJourney(drive(400, "MODULE_WARP_DRIVE_I", 2000), galaxy(true), "X1-S2-D")
+			},
+			want: []string{"FLIGHT_MODE  BURN 0", "WARP X1-S2-D BURN 200"},
+			time: 57 * time.Second,
+		},
+		{
+			name: "cheapest warps adrift",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanJourney(drive(400, "MODULE_WARP_DRIVE_I", 2000), galaxy(true), "X1-S2-D", navigation.WithObjective(navigation.Cheapest))
+			},
+			want: []string{"FLIGHT_MODE  DRIFT 0", "WARP X1-S2-D DRIFT 1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := tc.plan()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := steps(plan); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("steps = %q, want %q", got, tc.want)
+			}
+			if tc.time != 0 && plan.Time != tc.time {
+				t.Errorf("Time = %v, want %v", plan.Time, tc.time)
+			}
+			if plan.Antimatter != tc.antimatter {
+				t.Errorf("Antimatter = %d, want %d", plan.Antimatter, tc.antimatter)
+			}
+		})
+	}
+}
+
+func TestPlanJourneyNoRoute(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		ship        models.Ship
+		galaxy      *navigation.Galaxy
+		destination string
+	}{
+		{"start not in the galaxy", cruiser("X1-S9-A", 400, 400), galaxy(true), "X1-S2-D"},
+		{"destination not in the galaxy", drive(400, "", 0), galaxy(true), "X1-S9-A"},
+		{"gates not linked", drive(400, "", 0), galaxy(false), "X1-S2-D"},
+		{"jump drive out of range", drive(400, "MODULE_JUMP_DRIVE_I", 50), galaxy(false), "X1-S2-D"},
+		{"no gate to jump to", drive(400, "MODULE_JUMP_DRIVE_I", 5000), galaxy(true), "X1-S3-W"},
+		{"warp drive out of range", drive(1500, "MODULE_WARP_DRIVE_I", 500), galaxy(true), "X1-S3-W"},
+		{"warp drive without a range", drive(1500, "MODULE_WARP_DRIVE_I", 0), galaxy(true), "X1-S3-W"},
+		{"too little fuel to warp", drive(400, "MODULE_WARP_DRIVE_I", 2000), galaxy(true), "X1-S3-W"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := navigation.PlanJourney(tc.ship, tc.galaxy, tc.destination, navigation.WithFlightModes(models.ShipNavFlightModeCruise)); !errors.Is(err, navigation.ErrNoRoute) {
+				t.Errorf("err = %v, want ErrNoRoute", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Package navigation predicts flights before they are made: distances
+// between waypoints and systems, and the travel time and fuel a ship needs
+// for each flight mode, following the game's formulas.
+//
+//	est := navigation.Flight(ship, from, to, models.ShipNavFlightModeCruise)
+//	if !est.Fits(ship.Fuel) {
+//		// refuel first, or drift
+//	}
+package navigation
+
+import (
+	"math"
+	"spacetradersgo/v2/models"
+	"time"
+)
+
+// A flight takes round(round(max(1, distance)) * multiplier / speed + 15)
+// seconds, where the multiplier depends on the flight mode.
+const (
+	CruiseMultiplier  = 25
+	DriftMultiplier   = 250
+	BurnMultiplier    = 12.5
+	StealthMultiplier = 30
+	// BaseTravelSeconds is added to every flight, however short.
+	BaseTravelSeconds = 15
+)
+
+// FuelUnit is how much ship fuel one unit of the FUEL trade good holds,
+// both when refuelling and when buying FUEL as cargo.
+const FuelUnit = 100
+
+// JumpCooldownBase is the shortest cooldown a jump triggers; longer jumps
+// add a second per ten units travelled.
+const JumpCooldownBase = 60 * time.Second
+
+// FlightModes lists every flight mode, fastest first.
+var FlightModes = []models.ShipNavFlightMode{
+	models.ShipNavFlightModeBurn,
+	models.ShipNavFlightModeCruise,
+	models.ShipNavFlightModeStealth,
+	models.ShipNavFlightModeDrift,
+}
+
+// Distance is the Euclidean distance between two coordinates.
+func Distance(x1, y1, x2, y2 int) float64 {
+	return math.Hypot(float64(x2-x1), float64(y2-y1))
+}
+
+// WaypointDistance is the distance between two waypoints of one system.
+func WaypointDistance(a, b models.Waypoint) float64 {
+	return Distance(a.X, a.Y, b.X, b.Y)
+}
+
+// SystemDistance is the distance between two systems on the galaxy map.
+func SystemDistance(a, b models.System) float64 {
+	return Distance(a.X, a.Y, b.X, b.Y)
+}
+
+// RouteDistance is the length of a route leg, e.g. a ship's
+// nav.route.departure to nav.route.destination.
+func RouteDistance(a, b models.ShipNavRouteWaypoint) float64 {
+	return Distance(a.X, a.Y, b.X, b.Y)
+}
+
+// Multiplier returns the travel time multiplier of a flight mode. Unknown
+// modes are treated as cruise, the API's default.
+func Multiplier(mode models.ShipNavFlightMode) float64 {
+	switch mode {
+	case models.ShipNavFlightModeDrift:
+		return DriftMultiplier
+	case models.ShipNavFlightModeBurn:
+		return BurnMultiplier
+	case models.ShipNavFlightModeStealth:
+		return StealthMultiplier
+	}
+	return CruiseMultiplier
+}
+
+// TravelTime is how long a flight over distance takes at the engine speed.
+func TravelTime(distance float64, speed int, mode models.ShipNavFlightMode) time.Duration {
+	if speed < 1 {
+		speed = 1
+	}
+	seconds := math.Round(math.Round(math.Max(1, distance))*Multiplier(mode)/float64(speed) + BaseTravelSeconds)
+	return time.Duration(seconds) * time.Second
+}
+
+// FuelCost is the fuel a flight over distance burns: the rounded distance
+// for cruise and stealth, double that for burn and a single unit when
+// drifting. Ships without a fuel tank burn none; see Flight.
+func FuelCost(distance float64, mode models.ShipNavFlightMode) int {
+	d := int(math.Round(distance))
+	switch mode {
+	case models.ShipNavFlightModeDrift:
+		return 1
+	case models.ShipNavFlightModeBurn:
+		return 2 * d
+	}
+	return d
+}
+
+// JumpCooldown is the cooldown a jump over distance leaves the ship with.
+func JumpCooldown(distance float64) time.Duration {
+	return JumpCooldownBase + time.Duration(int(math.Round(distance))/10)*time.Second
+}
+
+// Estimate predicts one flight.
+type Estimate struct {
+	Mode     models.ShipNavFlightMode
+	Distance float64
+	Time     time.Duration
+	Fuel     int
+}
+
+// Fits reports whether a ship with fuel can make the flight. Ships without
+// a tank always can.
+func (e Estimate) Fits(fuel models.ShipFuel) bool {
+	return fuel.Capacity == 0 || e.Fuel <= fuel.Current
+}
+
+// Flight estimates a navigation of ship between two waypoints in mode.
+func Flight(ship models.Ship, from, to models.Waypoint, mode models.ShipNavFlightMode) Estimate {
+	return Leg(ship, WaypointDistance(from, to), mode)
+}
+
+// Leg estimates a flight of ship over distance in mode, for navigation
+// within a system or warps between systems.
+func Leg(ship models.Ship, distance float64, mode models.ShipNavFlightMode) Estimate {
+	e := Estimate{Mode: mode, Distance: distance, Time: TravelTime(distance, ship.Engine.Speed, mode)}
+	if ship.Fuel.Capacity > 0 {
+		e.Fuel = FuelCost(distance, mode)
+	}
+	return e
+}
+
+// Flights estimates the flight in every mode, fastest first.
+func Flights(ship models.Ship, from, to models.Waypoint) []Estimate {
+	out := make([]Estimate, 0, len(FlightModes))
+	for _, mode := range FlightModes {
+		out = append(out, Flight(ship, from, to, mode))
+	}
+	return out
+}
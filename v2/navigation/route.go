@@ -0,0 +1,263 @@
+package navigation
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"spacetradersgo/v2/models"
+	"time"
+)
+
+// ErrNoRoute is returned, wrapped, when no sequence of flights and refuels
+// reaches the destination.
+var ErrNoRoute = errors.New("navigation: no route")
+
+type StepKind string
+
+const (
+	// StepFlightMode switches the ship's flight mode with PatchShipNav.
+	StepFlightMode StepKind = "FLIGHT_MODE"
+	// StepNavigate flies to Waypoint with NavigateShip.
+	StepNavigate StepKind = "NAVIGATE"
+	// StepRefuel docks at Waypoint and fills the tank with RefuelShip.
+	StepRefuel StepKind = "REFUEL"
+)
+
+// Step is one action of a Plan.
+type Step struct {
+	Kind StepKind `json:"kind"`
+	// System is where a jump or warp goes.
+	System string `json:"system,omitempty"`
+	// Waypoint is where a navigation, jump or warp arrives, or where a
+	// refuel happens.
+	Waypoint string                   `json:"waypoint,omitempty"`
+	Mode     models.ShipNavFlightMode `json:"mode,omitempty"`
+	// Time is the expected duration of the step, or the cooldown a jump
+	// leaves.
+	Time time.Duration `json:"time,omitempty"`
+	// Fuel is burned by a navigation, or bought by a refuel.
+	Fuel int `json:"fuel,omitempty"`
+}
+
+// Plan is a sequence of steps that takes a ship to its destination.
+type Plan struct {
+	Steps []Step `json:"steps"`
+	// Time is the expected travel time, including jump cooldowns.
+	Time time.Duration `json:"time"`
+	// Fuel is the fuel burned on the way, refuelled or not.
+	Fuel int `json:"fuel"`
+	// Antimatter is what jump drive jumps are expected to use.
+	Antimatter int `json:"antimatter,omitempty"`
+}
+
+// Objective decides what a route planner minimises.
+type Objective int
+
+const (
+	// Fastest minimises travel time, then fuel.
+	Fastest Objective = iota
+	// Cheapest minimises fuel burned, and so the cost of refuelling, then
+	// travel time.
+	Cheapest
+)
+
+type planner struct {
+	objective Objective
+	modes     []models.ShipNavFlightMode
+	stations  map[string]bool
+}
+
+type plannerOpts func(*planner)
+
+// WithObjective sets what the planner minimises. The default is Fastest.
+func WithObjective(objective Objective) plannerOpts {
+	return func(p *planner) {
+		p.objective = objective
+	}
+}
+
+// WithFlightModes limits the flight modes the planner may pick, e.g. to
+// rule out drifting.
+func WithFlightModes(modes ...models.ShipNavFlightMode) plannerOpts {
+	return func(p *planner) {
+		p.modes = modes
+	}
+}
+
+// WithFuelStations adds waypoints where the ship can refuel.
+func WithFuelStations(symbols ...string) plannerOpts {
+	return func(p *planner) {
+		for _, s := range symbols {
+			p.stations[s] = true
+		}
+	}
+}
+
+// WithMarkets adds the markets that trade FUEL as fuel stations. Markets
+// list what they trade without a ship present, so the GetMarket responses
+// of a system's marketplaces will do; many marketplaces sell no fuel.
+func WithMarkets(markets ...models.Market) plannerOpts {
+	return func(p *planner) {
+		for _, m := range markets {
+			if SellsFuel(m) {
+				p.stations[m.Symbol] = true
+			}
+		}
+	}
+}
+
+// SellsFuel reports whether market trades FUEL.
+func SellsFuel(market models.Market) bool {
+	for _, goods := range [][]models.TradeGood{market.Exports, market.Imports, market.Exchange} {
+		for _, g := range goods {
+			if g.Symbol == models.TradeSymbolFuel {
+				return true
+			}
+		}
+	}
+	for _, g := range market.TradeGoods {
+		if g.Symbol == string(models.TradeSymbolFuel) {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanRoute finds a route for ship from where it is, or is headed, to
+// destination among the waypoints of its system, refuelling at fuel
+// stations and picking a flight mode per leg. The ship only refuels at the
+// stations given with WithFuelStations or WithMarkets: the MARKETPLACE
+// trait alone doesn't say whether a market sells fuel.
+func PlanRoute(ship models.Ship, waypoints []models.Waypoint, destination string, opts ...plannerOpts) (*Plan, error) {
+	p := &planner{modes: FlightModes, stations: map[string]bool{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	byIndex := map[string]int{}
+	for i, wp := range waypoints {
+		byIndex[wp.Symbol] = i
+	}
+	start, ok := byIndex[ship.Nav.WaypointSymbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: ship %s is at %s, which is not among the waypoints", ErrNoRoute, ship.Symbol, ship.Nav.WaypointSymbol)
+	}
+	goal, ok := byIndex[destination]
+	if !ok {
+		return nil, fmt.Errorf("%w: destination %s is not among the waypoints", ErrNoRoute, destination)
+	}
+
+	capacity := ship.Fuel.Capacity
+	first := &label{at: start, fuel: ship.Fuel.Current, mode: ship.Nav.FlightMode}
+	labels := make([][]*label, len(waypoints))
+	queue := &labelQueue{objective: p.objective}
+	heap.Push(queue, first)
+
+	for queue.Len() > 0 {
+		l := heap.Pop(queue).(*label)
+		if dominated(labels[l.at], l) {
+			continue
+		}
+		labels[l.at] = append(labels[l.at], l)
+		if l.at == goal {
+			return l.plan(), nil
+		}
+
+		if capacity > 0 && l.fuel < capacity && p.stations[waypoints[l.at].Symbol] && (l.step == nil || l.step.Kind != StepRefuel) {
+			heap.Push(queue, l.next(l.at, capacity, l.mode, 0, 0,
+				Step{Kind: StepRefuel, Waypoint: waypoints[l.at].Symbol, Fuel: capacity - l.fuel}))
+		}
+		for to := range waypoints {
+			if to == l.at {
+				continue
+			}
+			for _, mode := range p.modes {
+				est := Flight(ship, waypoints[l.at], waypoints[to], mode)
+				if est.Fuel > l.fuel {
+					continue
+				}
+				heap.Push(queue, l.next(to, l.fuel-est.Fuel, mode, est.Time, est.Fuel,
+					Step{Kind: StepNavigate, Waypoint: waypoints[to].Symbol, Mode: mode, Time: est.Time, Fuel: est.Fuel}))
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w from %s to %s", ErrNoRoute, ship.Nav.WaypointSymbol, destination)
+}
+
+// label is a partial route: the ship at waypoint index at with fuel left,
+// reached by step from prev.
+type label struct {
+	at    int
+	fuel  int
+	mode  models.ShipNavFlightMode
+	time  time.Duration
+	burn  int
+	steps int
+	step  *Step
+	prev  *label
+}
+
+func (l *label) next(at, fuel int, mode models.ShipNavFlightMode, t time.Duration, burn int, step Step) *label {
+	return &label{at: at, fuel: fuel, mode: mode, time: l.time + t, burn: l.burn + burn, steps: l.steps + 1, step: &step, prev: l}
+}
+
+// dominated reports whether an existing label at the same waypoint has at
+// least as much fuel for no more time and fuel burned.
+func dominated(existing []*label, l *label) bool {
+	for _, e := range existing {
+		if e.fuel >= l.fuel && e.time <= l.time && e.burn <= l.burn {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *label) plan() *Plan {
+	var steps []Step
+	for ; l.step != nil; l = l.prev {
+		steps = append(steps, *l.step)
+		if l.step.Kind == StepNavigate && l.prev.mode != l.step.Mode {
+			steps = append(steps, Step{Kind: StepFlightMode, Mode: l.step.Mode})
+		}
+	}
+	plan := &Plan{Steps: make([]Step, 0, len(steps))}
+	for i := len(steps) - 1; i >= 0; i-- {
+		plan.Steps = append(plan.Steps, steps[i])
+		if steps[i].Kind == StepNavigate {
+			plan.Time += steps[i].Time
+			plan.Fuel += steps[i].Fuel
+		}
+	}
+	return plan
+}
+
+type labelQueue struct {
+	objective Objective
+	labels    []*label
+}
+
+func (q *labelQueue) Len() int { return len(q.labels) }
+
+func (q *labelQueue) Less(i, j int) bool {
+	a, b := q.labels[i], q.labels[j]
+	if q.objective == Cheapest && a.burn != b.burn {
+		return a.burn < b.burn
+	}
+	if a.time != b.time {
+		return a.time < b.time
+	}
+	if a.burn != b.burn {
+		return a.burn < b.burn
+	}
+	return a.steps < b.steps
+}
+
+func (q *labelQueue) Swap(i, j int) { q.labels[i], q.labels[j] = q.labels[j], q.labels[i] }
+
+func (q *labelQueue) Push(x any) { q.labels = append(q.labels, x.(*label)) }
+
+func (q *labelQueue) Pop() any {
+	l := q.labels[len(q.labels)-1]
+	q.labels = q.labels[:len(q.labels)-1]
+	return l
+}
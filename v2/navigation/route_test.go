@@ -0,0 +1,155 @@
+package navigation_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/navigation"
+)
+
+// Waypoints of X1-T on a line: S halfway from A to D.
+var line = []models.Waypoint{
+	{Symbol: "X1-T-A", X: 0, Y: 0},
+	{Symbol: "X1-T-S", X: 100, Y: 0},
+	{Symbol: "X1-T-D", X: 200, Y: 0},
+}
+
+// cruiser is a ship at waypoint cruising at speed 30 with fuel left in a
+// tank of capacity.
+func cruiser(waypoint string, fuel, capacity int) models.Ship {
+	var ship models.Ship
+	ship.Symbol = "SHIP-1"
+	ship.Nav.WaypointSymbol = waypoint
+	ship.Nav.FlightMode = models.ShipNavFlightModeCruise
+	ship.Fuel.Current = fuel
+	ship.Fuel.Capacity = capacity
+	ship.Engine.Speed = 30
+	return ship
+}
+
+func steps(plan *navigation.Plan) []string {
+	var out []string
+	for _, s := range plan.Steps {
+		where := s.Waypoint
+		if s.Kind == navigation.StepJump {
+			where = s.System
+		}
+		out = append(out, fmt.Sprintf("%s %s %s %d", s.Kind, where, s.Mode, s.Fuel))
+	}
+	return out
+}
+
+func fuelMarket(symbol string) models.Market {
+	return models.Market{Symbol: symbol, Exchange: []models.TradeGood{{Symbol: models.TradeSymbolFuel}}}
+}
+
+func TestPlanRoute(t *testing.T) {
+	cruise := navigation.WithFlightModes(models.ShipNavFlightModeCruise)
+	for _, tc := range []struct {
+		name string
+		plan func() (*navigation.Plan, error)
+		want []string
+		// time is the expected plan time, if set.
+		time time.Duration
+	}{
+		{
+			// Burning takes round(100*12.5/30+15) = 57s, cruising 98s.
+			name: "fastest burns",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanRoute(cruiser("X1-T-A", 400, 400), line, "X1-T-S")
+			},
+			want: []string{"FLIGHT_MODE  BURN 0", "NAVIGATE X1-T-S BURN 200"},
+			time: 57 * time.Second,
+		},
+		{
+			name: "fastest within the fuel left",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanRoute(cruiser("X1-T-A", 150, 400), line, "X1-T-S")
+			},
+			want: []string{"NAVIGATE X1-T-S CRUISE 100"},
+			time: 98 * time.Second,
+		},
+		{
+			name: "cheapest drifts",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanRoute(cruiser("X1-T-A", 400, 400), line, "X1-T-S", navigation.WithObjective(navigation.Cheapest))
+			},
+			want: []string{"FLIGHT_MODE  DRIFT 0", "NAVIGATE X1-T-S DRIFT 1"},
+		},
+		{
+			name: "cheapest among the modes allowed",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanRoute(cruiser("X1-T-A", 400, 400), line, "X1-T-S", navigation.WithObjective(navigation.Cheapest),
+					navigation.WithFlightModes(models.ShipNavFlightModeBurn, models.ShipNavFlightModeCruise))
+			},
+			want: []string{"NAVIGATE X1-T-S CRUISE 100"},
+		},
+		{
+			name: "refuels at a station on the way",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanRoute(cruiser("X1-T-A", 150, 150), line, "X1-T-D", cruise, navigation.WithFuelStations("X1-T-S"))
+			},
+			want: []string{"NAVIGATE X1-T-S CRUISE 100", "REFUEL X1-T-S  100", "NAVIGATE X1-T-D CRUISE 100"},
+			time: 196 * time.Second,
+		},
+		{
+			name: "refuels at a market trading fuel",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanRoute(cruiser("X1-T-A", 150, 150), line, "X1-T-D", cruise, navigation.WithMarkets(fuelMarket("X1-T-S")))
+			},
+			want: []string{"NAVIGATE X1-T-S CRUISE 100", "REFUEL X1-T-S  100", "NAVIGATE X1-T-D CRUISE 100"},
+		},
+		{
+			name: "drifts the rest with too little fuel",
+			plan: func() (*navigation.Plan, error) {
+				return navigation.PlanRoute(cruiser("X1-T-A", 150, 150), line, "X1-T-D", navigation.WithObjective(navigation.Cheapest))
+			},
+			want: []string{"FLIGHT_MODE  DRIFT 0", "NAVIGATE X1-T-D DRIFT 1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := tc.plan()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := steps(plan); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("steps = %q, want %q", got, tc.want)
+			}
+			if tc.time != 0 && plan.Time != tc.time {
+				t.Errorf("Time = %v, want %v", plan.Time, tc.time)
+			}
+		})
+	}
+}
+
+func TestPlanRouteNoRoute(t *testing.T) {
+	cruise := navigation.WithFlightModes(models.ShipNavFlightModeCruise)
+	for _, tc := range []struct {
+		name string
+		plan func() (*navigation.Plan, error)
+	}{
+		{"start not among the waypoints", func() (*navigation.Plan, error) {
+			return navigation.PlanRoute(cruiser("X1-T-Z", 400, 400), line, "X1-T-D")
+		}},
+		{"destination not among the waypoints", func() (*navigation.Plan, error) {
+			return navigation.PlanRoute(cruiser("X1-T-A", 400, 400), line, "X1-T-Z")
+		}},
+		{"out of fuel without a station", func() (*navigation.Plan, error) {
+			return navigation.PlanRoute(cruiser("X1-T-A", 150, 150), line, "X1-T-D", cruise)
+		}},
+		{"market without fuel", func() (*navigation.Plan, error) {
+			return navigation.PlanRoute(cruiser("X1-T-A", 150, 150), line, "X1-T-D", cruise,
+				navigation.WithMarkets(models.Market{Symbol: "X1-T-S", Exports: []models.TradeGood{{Symbol: models.TradeSymbolIronOre}}}))
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.plan(); !errors.Is(err, navigation.ErrNoRoute) {
+				t.Errorf("err = %v, want ErrNoRoute", err)
+			}
+		})
+	}
+}
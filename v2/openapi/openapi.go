@@ -0,0 +1,8 @@
+// Package openapi embeds the vendored copy of the SpaceTraders OpenAPI
+// document the SDK is generated from and checked against.
+package openapi
+
+import _ "embed"
+
+//go:embed spacetraders.json
+var Spec []byte
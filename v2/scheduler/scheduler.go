@@ -0,0 +1,222 @@
+// Package scheduler times ship actions around cooldowns and transit. It
+// reads readiness from a fleets.FleetState, which picks up cooldowns from
+// GetShipCooldown, CreateSurvey, ExtractResource and JumpShip and arrival
+// times from every nav update, so bots don't need their own sleep loops:
+//
+//	state := fleets.NewFleetState(fleets.NewFleets())
+//	sdk := v2.NewSpaceTradersClient(v2.WithFleetClient(state))
+//	sched := scheduler.New(state)
+//	defer sched.Close()
+//	go sched.Run(ctx)
+//
+//	done := sched.Submit("SHIP-1", func(ctx context.Context) error {
+//		_, err := sdk.Fleets.ExtractResource(ctx, &fleets.ExtractResourceRequest{Token: token, ShipID: "SHIP-1"})
+//		return err
+//	})
+//	err := <-done
+//
+// Queued actions run one at a time per ship, in submission order, as soon
+// as the ship is ready; actions for different ships run concurrently.
+package scheduler
+
+import (
+	"context"
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/models"
+	"sync"
+	"time"
+)
+
+// Action is work for one ship. It receives the context passed to Run.
+type Action func(ctx context.Context) error
+
+type job struct {
+	action Action
+	done   chan error
+}
+
+// Scheduler is safe for concurrent use.
+type Scheduler struct {
+	state   *fleets.FleetState
+	margin  time.Duration
+	unwatch func()
+
+	mu     sync.Mutex
+	queues map[string][]*job
+	busy   map[string]bool
+	// changed is closed and replaced whenever readiness may have changed.
+	changed chan struct{}
+}
+
+type schedulerOpts func(*Scheduler)
+
+var (
+	defaultOpts = []schedulerOpts{
+		WithMargin(500 * time.Millisecond),
+	}
+)
+
+// WithMargin sets how long after a cooldown expires or a ship arrives it
+// counts as ready, to absorb the difference between the local and server
+// clocks.
+func WithMargin(margin time.Duration) schedulerOpts {
+	return func(s *Scheduler) {
+		s.margin = margin
+	}
+}
+
+func New(state *fleets.FleetState, opts ...schedulerOpts) *Scheduler {
+	s := &Scheduler{
+		state:   state,
+		queues:  map[string][]*job{},
+		busy:    map[string]bool{},
+		changed: make(chan struct{}),
+	}
+
+	opts = append(defaultOpts, opts...)
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.unwatch = state.Watch(func(fleets.ShipState) { s.signal() })
+	return s
+}
+
+// Close stops the scheduler following the fleet state.
+func (s *Scheduler) Close() {
+	s.unwatch()
+}
+
+// ReadyAt returns when a ship can next act: once its cooldown has expired
+// and, if it is in transit, it has arrived. A time in the past, or the zero
+// time for a ship the fleet state doesn't hold, means it is ready now.
+func (s *Scheduler) ReadyAt(shipID string) time.Time {
+	st, ok := s.state.Ship(shipID)
+	if !ok {
+		return time.Time{}
+	}
+	var at time.Time
+	if st.Ship.Nav.Status == models.ShipNavStatusInTransit {
+		at = st.Ship.Nav.Route.Arrival
+	}
+	expires := st.Cooldown.Expiration
+	if expires.IsZero() && st.Cooldown.RemainingSeconds > 0 {
+		expires = st.Updated.Add(time.Duration(st.Cooldown.RemainingSeconds) * time.Second)
+	}
+	if expires.After(at) {
+		at = expires
+	}
+	if at.IsZero() {
+		return at
+	}
+	return at.Add(s.margin)
+}
+
+// WaitUntilReady blocks until the ship is ready or ctx is done.
+func (s *Scheduler) WaitUntilReady(ctx context.Context, shipID string) error {
+	for {
+		changed := s.watch()
+		wait := time.Until(s.ReadyAt(shipID))
+		if wait <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-changed:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// Submit queues action for the ship. The returned channel receives the
+// action's error, or nil, once it has run.
+func (s *Scheduler) Submit(shipID string, action Action) <-chan error {
+	j := &job{action: action, done: make(chan error, 1)}
+	s.mu.Lock()
+	s.queues[shipID] = append(s.queues[shipID], j)
+	s.mu.Unlock()
+	s.signal()
+	return j.done
+}
+
+// Pending returns how many actions are queued for the ship, not counting
+// one already running.
+func (s *Scheduler) Pending(shipID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queues[shipID])
+}
+
+// Run dispatches queued actions as ships become ready until ctx is done.
+// Actions still queued then stay queued for the next Run.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		var next time.Time
+		for ship, queue := range s.queues {
+			if len(queue) == 0 || s.busy[ship] {
+				continue
+			}
+			if at := s.ReadyAt(ship); at.After(time.Now()) {
+				if next.IsZero() || at.Before(next) {
+					next = at
+				}
+				continue
+			}
+			j := queue[0]
+			s.queues[ship] = queue[1:]
+			if len(s.queues[ship]) == 0 {
+				delete(s.queues, ship)
+			}
+			s.busy[ship] = true
+			go s.dispatch(ctx, ship, j)
+		}
+		s.mu.Unlock()
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-changed:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Scheduler) dispatch(ctx context.Context, ship string, j *job) {
+	err := j.action(ctx)
+	s.mu.Lock()
+	delete(s.busy, ship)
+	s.mu.Unlock()
+	j.done <- err
+	s.signal()
+}
+
+func (s *Scheduler) watch() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changed
+}
+
+func (s *Scheduler) signal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
@@ -0,0 +1,254 @@
+package scheduler_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/mocks"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/scheduler"
+)
+
+const margin = 10 * time.Millisecond
+
+// fleet holds ships in a FleetState whose cooldowns are set with coolDown.
+type fleet struct {
+	*fleets.FleetState
+
+	mu        sync.Mutex
+	cooldowns map[string]fleets.Cooldown
+}
+
+func newFleet(ships ...string) *fleet {
+	f := &fleet{cooldowns: map[string]fleets.Cooldown{}}
+	m := mocks.NewFleetsClient()
+	m.OnGetShipCooldown().Do(func(_ context.Context, r *fleets.GetShipCooldownRequest) (*fleets.GetShipCooldownResponse, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		c, ok := f.cooldowns[r.ShipID]
+		return &fleets.GetShipCooldownResponse{IsOnCooldown: ok, Cooldown: c}, nil
+	})
+	f.FleetState = fleets.NewFleetState(m)
+	for _, symbol := range ships {
+		var ship fleets.Ship
+		ship.Symbol = symbol
+		ship.Nav.Status = models.ShipNavStatusInOrbit
+		f.Put(ship)
+	}
+	return f
+}
+
+// coolDown puts the ship on a cooldown until expires, or takes it off one
+// when expires is zero.
+func (f *fleet) coolDown(ship string, expires time.Time) error {
+	f.mu.Lock()
+	if expires.IsZero() {
+		delete(f.cooldowns, ship)
+	} else {
+		f.cooldowns[ship] = fleets.Cooldown{ShipSymbol: ship, RemainingSeconds: 1, Expiration: expires}
+	}
+	f.mu.Unlock()
+	_, err := f.GetShipCooldown(context.Background(), &fleets.GetShipCooldownRequest{ShipID: ship})
+	return err
+}
+
+// travel puts the ship in transit until arrival.
+func (f *fleet) travel(ship string, arrival time.Time) {
+	st, _ := f.Ship(ship)
+	st.Ship.Nav.Status = models.ShipNavStatusInTransit
+	st.Ship.Nav.Route.Arrival = arrival
+	f.Put(st.Ship)
+}
+
+func TestReadyAt(t *testing.T) {
+	now := time.Now()
+	f := newFleet("IDLE", "COOLING", "FLYING", "BOTH", "LANDED")
+	if err := f.coolDown("COOLING", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	f.travel("FLYING", now.Add(2*time.Minute))
+	if err := f.coolDown("BOTH", now.Add(3*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	f.travel("BOTH", now.Add(time.Minute))
+	f.travel("LANDED", now.Add(-time.Second))
+	s := scheduler.New(f.FleetState, scheduler.WithMargin(margin))
+	defer s.Close()
+
+	for _, tc := range []struct {
+		ship string
+		want time.Time
+	}{
+		{"IDLE", time.Time{}},
+		{"UNKNOWN", time.Time{}},
+		{"COOLING", now.Add(time.Minute + margin)},
+		{"FLYING", now.Add(2*time.Minute + margin)},
+		// The later of the arrival and the cooldown.
+		{"BOTH", now.Add(3*time.Minute + margin)},
+		{"LANDED", time.Time{}},
+	} {
+		if got := s.ReadyAt(tc.ship); !got.Equal(tc.want) {
+			t.Errorf("ReadyAt(%s) = %v, want %v", tc.ship, got, tc.want)
+		}
+	}
+}
+
+func TestReadyAtFromRemainingSeconds(t *testing.T) {
+	m := mocks.NewFleetsClient()
+	state := fleets.NewFleetState(m)
+	var ship fleets.Ship
+	ship.Symbol = "SHIP-1"
+	state.Put(ship)
+	extract := &fleets.ExtractResourceResponse{}
+	extract.Data.Cooldown = fleets.Cooldown{ShipSymbol: "SHIP-1", RemainingSeconds: 70}
+	m.OnExtractResource().Return(extract, nil)
+	if _, err := state.ExtractResource(context.Background(), &fleets.ExtractResourceRequest{ShipID: "SHIP-1"}); err != nil {
+		t.Fatal(err)
+	}
+	s := scheduler.New(state, scheduler.WithMargin(margin))
+	defer s.Close()
+
+	st, _ := state.Ship("SHIP-1")
+	if got, want := s.ReadyAt("SHIP-1"), st.Updated.Add(70*time.Second+margin); !got.Equal(want) {
+		t.Errorf("ReadyAt = %v, want the remaining seconds counted from the update, %v", got, want)
+	}
+}
+
+func TestRunOrdersAroundCooldownsAndTransit(t *testing.T) {
+	start := time.Now()
+	f := newFleet("MINER", "HAULER", "IDLE")
+	if err := f.coolDown("MINER", start.Add(100*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	f.travel("HAULER", start.Add(50*time.Millisecond))
+	s := scheduler.New(f.FleetState, scheduler.WithMargin(margin))
+	defer s.Close()
+
+	type run struct {
+		name string
+		at   time.Time
+	}
+	var mu sync.Mutex
+	var runs []run
+	action := func(name string, then func() error) scheduler.Action {
+		return func(context.Context) error {
+			mu.Lock()
+			runs = append(runs, run{name, time.Now()})
+			mu.Unlock()
+			if then != nil {
+				return then()
+			}
+			return nil
+		}
+	}
+
+	var done []<-chan error
+	// The first extraction starts another cooldown, which the second one
+	// waits out in turn.
+	done = append(done, s.Submit("MINER", action("extract 1", func() error { return f.coolDown("MINER", time.Now().Add(100*time.Millisecond)) })))
+	done = append(done, s.Submit("MINER", action("extract 2", nil)))
+	done = append(done, s.Submit("HAULER", action("sell", nil)))
+	done = append(done, s.Submit("IDLE", action("survey", nil)))
+	if n := s.Pending("MINER"); n != 2 {
+		t.Errorf("Pending(MINER) = %d before Run, want 2", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	for _, ch := range done {
+		select {
+		case err := <-ch:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("actions never ran")
+		}
+	}
+
+	var order []string
+	at := map[string]time.Time{}
+	for _, r := range runs {
+		order = append(order, r.name)
+		at[r.name] = r.at
+	}
+	if want := []string{"survey", "sell", "extract 1", "extract 2"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("ran %v, want %v", order, want)
+	}
+	if at["sell"].Before(start.Add(50*time.Millisecond + margin)) {
+		t.Errorf("sold %v after the start, before arriving", at["sell"].Sub(start))
+	}
+	if at["extract 1"].Before(start.Add(100*time.Millisecond + margin)) {
+		t.Errorf("extracted %v after the start, before the cooldown expired", at["extract 1"].Sub(start))
+	}
+	if gap := at["extract 2"].Sub(at["extract 1"]); gap < 100*time.Millisecond {
+		t.Errorf("second extraction %v after the first, inside its cooldown", gap)
+	}
+}
+
+func TestRunLeavesQueuedActionsWhenStopped(t *testing.T) {
+	f := newFleet("SHIP-1")
+	if err := f.coolDown("SHIP-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	s := scheduler.New(f.FleetState, scheduler.WithMargin(margin))
+	defer s.Close()
+	done := s.Submit("SHIP-1", func(context.Context) error { return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Run = %v, want the context's deadline", err)
+	}
+	if n := s.Pending("SHIP-1"); n != 1 {
+		t.Errorf("Pending = %d, want the action still queued", n)
+	}
+
+	// Clearing the cooldown lets the next Run dispatch it at once.
+	if err := f.coolDown("SHIP-1", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("action never ran after the cooldown was cleared")
+	}
+}
+
+func TestWaitUntilReadyWakesOnChange(t *testing.T) {
+	f := newFleet("SHIP-1")
+	if err := f.coolDown("SHIP-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	s := scheduler.New(f.FleetState, scheduler.WithMargin(margin))
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	if err := s.WaitUntilReady(ctx, "SHIP-1"); err != context.DeadlineExceeded {
+		t.Errorf("WaitUntilReady = %v, want the context's deadline", err)
+	}
+	cancel()
+
+	waited := make(chan error, 1)
+	go func() { waited <- s.WaitUntilReady(context.Background(), "SHIP-1") }()
+	time.Sleep(10 * time.Millisecond)
+	if err := f.coolDown("SHIP-1", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Errorf("WaitUntilReady = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitUntilReady slept through the cooldown being cleared")
+	}
+}
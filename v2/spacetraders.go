@@ -1,10 +1,14 @@
 package v2
 
 import (
+	"net/http"
 	"spacetradersgo/v2/agents"
 	"spacetradersgo/v2/contracts"
 	"spacetradersgo/v2/factions"
+	"spacetradersgo/v2/faults"
 	"spacetradersgo/v2/fleets"
+	"spacetradersgo/v2/status"
+	"spacetradersgo/v2/strict"
 	"spacetradersgo/v2/systems"
 )
 
@@ -14,20 +18,16 @@ type SpcaeTradersClient struct {
 	Contracts contracts.ContractsClient
 	Fleets    fleets.FleetsClient
 	Systems   systems.SystemsClient
+	Status    status.StatusClient
+
+	// Shared settings for the clients created by NewSpaceTradersClient.
+	httpClient *http.Client
+	strict     *strict.Recorder
+	faults     *faults.Injector
 }
 
 type spaceTraderClientOpts func(*SpcaeTradersClient)
 
-var (
-	defaultOpts = []spaceTraderClientOpts{
-		WithAgentsClient(agents.NewAgents()),
-		WithFactionsClient(factions.NewFactions()),
-		WithContractsClient(contracts.NewContracts()),
-		WithFleetClient(fleets.NewFleets()),
-		WithSystemsClient(systems.NewSystems()),
-	}
-)
-
 func WithAgentsClient(agentsClient agents.AgentsClient) spaceTraderClientOpts {
 	return func(c *SpcaeTradersClient) {
 		c.Agents = agentsClient
@@ -58,12 +58,65 @@ func WithSystemsClient(systemsClient systems.SystemsClient) spaceTraderClientOpt
 	}
 }
 
+func WithStatusClient(statusClient status.StatusClient) spaceTraderClientOpts {
+	return func(c *SpcaeTradersClient) {
+		c.Status = statusClient
+	}
+}
+
+// WithHTTPClient sets the http.Client used by every client that isn't
+// supplied through its own With*Client option.
+func WithHTTPClient(httpClient *http.Client) spaceTraderClientOpts {
+	return func(c *SpcaeTradersClient) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithStrictDecoding enables strict decoding on every client that isn't
+// supplied through its own With*Client option.
+func WithStrictDecoding(recorder *strict.Recorder) spaceTraderClientOpts {
+	return func(c *SpcaeTradersClient) {
+		c.strict = recorder
+	}
+}
+
+// WithFaultInjection routes every client that isn't supplied through its
+// own With*Client option through injector, on top of the HTTP client.
+func WithFaultInjection(injector *faults.Injector) spaceTraderClientOpts {
+	return func(c *SpcaeTradersClient) {
+		c.faults = injector
+	}
+}
+
 func NewSpaceTradersClient(opts ...spaceTraderClientOpts) *SpcaeTradersClient {
-	c := &SpcaeTradersClient{}
-	opts = append(defaultOpts, opts...)
+	c := &SpcaeTradersClient{
+		httpClient: http.DefaultClient,
+	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.faults != nil {
+		c.httpClient = c.faults.Client(c.httpClient)
+	}
+
+	if c.Agents == nil {
+		c.Agents = agents.NewAgents(agents.WithHTTPClient(c.httpClient), agents.WithStrictDecoding(c.strict))
+	}
+	if c.Factions == nil {
+		c.Factions = factions.NewFactions(factions.WithHTTPClient(c.httpClient), factions.WithStrictDecoding(c.strict))
+	}
+	if c.Contracts == nil {
+		c.Contracts = contracts.NewContracts(contracts.WithHTTPClient(c.httpClient), contracts.WithStrictDecoding(c.strict))
+	}
+	if c.Fleets == nil {
+		c.Fleets = fleets.NewFleets(fleets.WithHTTPClient(c.httpClient), fleets.WithStrictDecoding(c.strict))
+	}
+	if c.Systems == nil {
+		c.Systems = systems.NewSystems(systems.WithHTTPClient(c.httpClient), systems.WithStrictDecoding(c.strict))
+	}
+	if c.Status == nil {
+		c.Status = status.NewStatus(status.WithHTTPClient(c.httpClient), status.WithStrictDecoding(c.strict))
+	}
 
 	return c
 }
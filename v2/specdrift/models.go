@@ -0,0 +1,76 @@
+package specdrift
+
+import (
+	v2 "spacetradersgo/v2"
+	"spacetradersgo/v2/models"
+)
+
+// Models maps each component schema to the SDK type that represents it.
+var Models = map[string]any{
+	"Agent":                models.Agent{},
+	"Chart":                models.Chart{},
+	"ConnectedSystem":      models.ConnectedSystem{},
+	"Contract":             models.Contract{},
+	"ContractDeliverGood":  models.ContractDeliverGood{},
+	"ContractPayment":      models.ContractPayment{},
+	"ContractTerms":        models.ContractTerms{},
+	"Cooldown":             models.Cooldown{},
+	"Extraction":           models.Extraction{},
+	"ExtractionYield":      models.ExtractionYield{},
+	"Faction":              models.Faction{},
+	"FactionTrait":         models.FactionTrait{},
+	"JumpGate":             models.JumpGate{},
+	"Market":               models.Market{},
+	"MarketTradeGood":      models.MarketTradeGood{},
+	"MarketTransaction":    models.MarketTransaction{},
+	"Meta":                 models.Meta{},
+	"Ship":                 models.Ship{},
+	"ShipCargo":            models.ShipCargo{},
+	"ShipCargoItem":        models.ShipCargoItem{},
+	"ShipCrew":             models.ShipCrew{},
+	"ShipEngine":           models.ShipEngine{},
+	"ShipFrame":            models.ShipFrame{},
+	"ShipFuel":             models.ShipFuel{},
+	"ShipModule":           models.ShipModule{},
+	"ShipMount":            models.ShipMount{},
+	"ShipNav":              models.ShipNav{},
+	"ShipNavFlightMode":    models.ShipNavFlightMode(""),
+	"ShipNavRoute":         models.ShipNavRoute{},
+	"ShipNavRouteWaypoint": models.ShipNavRouteWaypoint{},
+	"ShipNavStatus":        models.ShipNavStatus(""),
+	"ShipReactor":          models.ShipReactor{},
+	"ShipRegistration":     models.ShipRegistration{},
+	"ShipRequirements":     models.ShipRequirements{},
+	"ShipRole":             models.ShipRole(""),
+	"ShipType":             models.ShipType(""),
+	"Shipyard":             models.Shipyard{},
+	"ShipyardShip":         models.ShipyardShip{},
+	"ShipyardTransaction":  models.ShipyardTransaction{},
+	"SupplyLevel":          models.SupplyLevel(""),
+	"Survey":               models.Survey{},
+	"SurveyDeposit":        models.SurveyDeposit{},
+	"System":               models.System{},
+	"SystemFaction":        models.SystemFaction{},
+	"SystemType":           models.SystemType(""),
+	"SystemWaypoint":       models.SystemWaypoint{},
+	"TradeGood":            models.TradeGood{},
+	"TradeSymbol":          models.TradeSymbol(""),
+	"Waypoint":             models.Waypoint{},
+	"WaypointFaction":      models.WaypointFaction{},
+	"WaypointOrbital":      models.WaypointOrbital{},
+	"WaypointTrait":        models.WaypointTrait{},
+	"WaypointType":         models.WaypointType(""),
+}
+
+// Check runs CheckModels against Models and CheckClient against every client
+// held by sdk.
+func (c *Checker) Check(sdk *v2.SpcaeTradersClient) []Issue {
+	issues := c.CheckModels(Models)
+	issues = append(issues, c.CheckClient("agents", sdk.Agents)...)
+	issues = append(issues, c.CheckClient("contracts", sdk.Contracts)...)
+	issues = append(issues, c.CheckClient("factions", sdk.Factions)...)
+	issues = append(issues, c.CheckClient("fleets", sdk.Fleets)...)
+	issues = append(issues, c.CheckClient("systems", sdk.Systems)...)
+	issues = append(issues, c.CheckClient("status", sdk.Status)...)
+	return issues
+}
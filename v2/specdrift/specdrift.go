@@ -0,0 +1,359 @@
+// Package specdrift compares the SDK's Go types against the SpaceTraders
+// OpenAPI document and reports where they disagree: fields the API sends
+// that the SDK doesn't model, misspelt JSON tags, mismatched types and
+// operations no client implements.
+//
+// It is meant to be called from a test:
+//
+//	func TestSpecDrift(t *testing.T) {
+//		c, err := specdrift.New()
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		for _, issue := range c.Check(v2.NewSpaceTradersClient()) {
+//			t.Error(issue)
+//		}
+//	}
+//
+// Only fields carrying a json tag take part in the comparison; untagged
+// fields such as Token or IsOnCooldown are SDK bookkeeping and never sent
+// over the wire.
+package specdrift
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"spacetradersgo/v2/internal/apispec"
+	"spacetradersgo/v2/openapi"
+	"strings"
+	"time"
+)
+
+type Kind string
+
+const (
+	MissingModel           Kind = "missing model"
+	MissingField           Kind = "missing field"
+	UnknownField           Kind = "unknown field"
+	WrongTag               Kind = "wrong json tag"
+	WrongType              Kind = "wrong type"
+	UnimplementedOperation Kind = "unimplemented operation"
+	WrongSignature         Kind = "wrong signature"
+)
+
+type Issue struct {
+	Kind Kind
+	// Where locates the issue, e.g. "Ship.fuel" or "fleets.SellCargo".
+	Where  string
+	Detail string
+}
+
+func (i Issue) String() string {
+	if i.Detail == "" {
+		return fmt.Sprintf("%s: %s", i.Where, i.Kind)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Where, i.Kind, i.Detail)
+}
+
+type Checker struct {
+	spec *apispec.Spec
+}
+
+// New returns a Checker for the spec vendored with the SDK.
+func New() (*Checker, error) {
+	spec, err := apispec.Parse(openapi.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Checker{spec: spec}, nil
+}
+
+// NewFromFile returns a Checker for the OpenAPI document at path, e.g. a
+// freshly downloaded copy to see what a new API release would break.
+func NewFromFile(path string) (*Checker, error) {
+	spec, err := apispec.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Checker{spec: spec}, nil
+}
+
+// CheckType compares the Go type of v against the named component schema.
+func (c *Checker) CheckType(schema string, v any) []Issue {
+	s, ok := c.spec.Components.Schemas.Values[schema]
+	if !ok {
+		return []Issue{{Kind: MissingModel, Where: schema, Detail: "not in spec"}}
+	}
+	w := &walker{spec: c.spec, seen: map[seenKey]bool{}}
+	w.compare(schema, reflect.TypeOf(v), s)
+	return w.issues
+}
+
+// CheckModels checks every component schema against the Go type registered
+// for it in models, reporting schemas that have no Go type at all.
+func (c *Checker) CheckModels(models map[string]any) []Issue {
+	var issues []Issue
+	for _, name := range c.spec.Components.Schemas.Keys {
+		v, ok := models[name]
+		if !ok {
+			issues = append(issues, Issue{Kind: MissingModel, Where: name})
+			continue
+		}
+		issues = append(issues, c.CheckType(name, v)...)
+	}
+	return issues
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// CheckClient checks that client implements every operation the spec
+// assigns to pkg (e.g. "fleets") and that the request and response types
+// of each method match the operation's parameters, body and payload.
+func (c *Checker) CheckClient(pkg string, client any) []Issue {
+	var issues []Issue
+	v := reflect.ValueOf(client)
+	for _, op := range c.spec.Operations() {
+		cfg, err := apispec.Endpoint(op)
+		if err != nil || cfg.Package != pkg {
+			continue
+		}
+		where := pkg + "." + cfg.Name
+
+		m := v.MethodByName(cfg.Name)
+		if !m.IsValid() {
+			issues = append(issues, Issue{Kind: UnimplementedOperation, Where: where, Detail: op.Method + " " + op.Path})
+			continue
+		}
+		mt := m.Type()
+		if mt.NumIn() != 2 || mt.In(0) != contextType || mt.In(1).Kind() != reflect.Pointer ||
+			mt.NumOut() != 2 || mt.Out(0).Kind() != reflect.Pointer || mt.Out(1) != errorType {
+			issues = append(issues, Issue{Kind: WrongSignature, Where: where, Detail: mt.String()})
+			continue
+		}
+
+		w := &walker{spec: c.spec, seen: map[seenKey]bool{}}
+		w.request(where+" request", mt.In(1).Elem(), op)
+		if _, s := op.Success(); s != nil {
+			w.compare(where+" response", mt.Out(0).Elem(), s)
+		}
+		issues = append(issues, w.issues...)
+	}
+	return issues
+}
+
+type seenKey struct {
+	t reflect.Type
+	s *apispec.Schema
+}
+
+type walker struct {
+	spec   *apispec.Spec
+	seen   map[seenKey]bool
+	issues []Issue
+}
+
+func (w *walker) report(kind Kind, where, format string, args ...any) {
+	w.issues = append(w.issues, Issue{Kind: kind, Where: where, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (w *walker) request(where string, t reflect.Type, op *apispec.Operation) {
+	for _, p := range op.Parameters {
+		name := apispec.ParamName(p)
+		f, ok := t.FieldByName(name)
+		if !ok {
+			w.report(MissingField, where, "no field %s for %s parameter %q", name, p.In, p.Name)
+			continue
+		}
+		w.compare(where+"."+name, f.Type, p.Schema)
+	}
+	if body := op.RequestBody.Schema(); body != nil {
+		w.compare(where, t, body)
+	}
+}
+
+// compare checks that t can carry values described by s.
+func (w *walker) compare(where string, t reflect.Type, s *apispec.Schema) {
+	s = w.spec.Resolve(s)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	want, ok := kind(t, s)
+	if !ok {
+		w.report(WrongType, where, "spec has %s, SDK has %s", want, t)
+		return
+	}
+	switch s.Type {
+	case "array":
+		w.compare(where+"[]", t.Elem(), s.Items)
+	case "object":
+		if t.Kind() == reflect.Map {
+			return
+		}
+		key := seenKey{t, s}
+		if w.seen[key] {
+			return
+		}
+		w.seen[key] = true
+		w.object(where, t, s)
+	}
+}
+
+// kind reports whether t's kind matches the schema type, along with a
+// description of what the schema wants.
+func kind(t reflect.Type, s *apispec.Schema) (string, bool) {
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			return "date-time", t == timeType
+		}
+		return "string", t.Kind() == reflect.String
+	case "integer":
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return "integer", true
+		}
+		return "integer", false
+	case "number":
+		return "number", t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+	case "boolean":
+		return "boolean", t.Kind() == reflect.Bool
+	case "array":
+		return "array", t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+	case "object":
+		return "object", (t.Kind() == reflect.Struct && t != timeType) || t.Kind() == reflect.Map
+	}
+	return s.Type, true
+}
+
+func (w *walker) object(where string, t reflect.Type, s *apispec.Schema) {
+	fields := jsonFields(t)
+	// Exact tags are claimed first, so a typo can't take another
+	// property's field.
+	exact := map[string]bool{}
+	for _, prop := range s.Properties.Keys {
+		if f, ok := fields[prop]; ok {
+			delete(fields, prop)
+			exact[prop] = true
+			w.compare(where+"."+prop, f.Type, s.Properties.Values[prop])
+		}
+	}
+	for _, prop := range s.Properties.Keys {
+		ps := s.Properties.Values[prop]
+		if exact[prop] {
+			continue
+		}
+		if tag, f, ok := nearMiss(prop, fields); ok {
+			delete(fields, tag)
+			w.report(WrongTag, where+"."+prop, "field %s is tagged %q", f.Name, tag)
+			w.compare(where+"."+prop, f.Type, ps)
+			continue
+		}
+		w.report(MissingField, where+"."+prop, "")
+	}
+
+	var extra []string
+	for tag := range fields {
+		extra = append(extra, tag)
+	}
+	sort.Strings(extra)
+	for _, tag := range extra {
+		w.report(UnknownField, where+"."+tag, "field %s is not in the spec", fields[tag].Name)
+	}
+}
+
+// jsonFields returns the tagged, exported fields of t keyed by JSON name,
+// flattening embedded structs the way encoding/json does.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if _, tagged := f.Tag.Lookup("json"); !tagged {
+				for k, v := range jsonFields(f.Type) {
+					fields[k] = v
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		tag, ok := f.Tag.Lookup("json")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// nearMiss finds a field whose tag looks like a typo of prop, such as
+// "fule" for "fuel" or "expiraton" for "expiration". The edits allowed
+// grow with the length of prop, one per three letters up to two, so short
+// names such as "x" or "id" must match exactly.
+func nearMiss(prop string, fields map[string]reflect.StructField) (string, reflect.StructField, bool) {
+	tags := make([]string, 0, len(fields))
+	for tag := range fields {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	edits := len(prop) / 3
+	if edits > 2 {
+		edits = 2
+	}
+	for _, tag := range tags {
+		f := fields[tag]
+		if strings.EqualFold(tag, prop) || distance(tag, prop) <= edits || f.Name == apispec.GoName(prop) {
+			return tag, f, true
+		}
+	}
+	return "", reflect.StructField{}, false
+}
+
+// distance is the edit distance between a and b, counting a swap of two
+// adjacent letters as one edit like an insertion, deletion or
+// substitution.
+func distance(a, b string) int {
+	before := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && before[j-2]+1 < cur[j] {
+				cur[j] = before[j-2] + 1
+			}
+		}
+		before, prev, cur = prev, cur, before
+	}
+	return prev[len(b)]
+}
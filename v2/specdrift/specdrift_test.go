@@ -0,0 +1,71 @@
+package specdrift_test
+
+import (
+	"testing"
+
+	v2 "spacetradersgo/v2"
+	"spacetradersgo/v2/specdrift"
+)
+
+func TestSpecDrift(t *testing.T) {
+	c, err := specdrift.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range c.Check(v2.NewSpaceTradersClient()) {
+		t.Error(issue)
+	}
+}
+
+func TestCheckTypeReportsDrift(t *testing.T) {
+	c, err := specdrift.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	type shipFuel struct {
+		Current  string `json:"current"`
+		Capacity int    `json:"capacty"`
+	}
+	got := map[specdrift.Kind]bool{}
+	for _, issue := range c.CheckType("ShipFuel", shipFuel{}) {
+		got[issue.Kind] = true
+	}
+	for _, want := range []specdrift.Kind{specdrift.WrongType, specdrift.WrongTag, specdrift.MissingField} {
+		if !got[want] {
+			t.Errorf("CheckType of a drifted ShipFuel reported no %q", want)
+		}
+	}
+}
+
+func TestCheckTypeNearMisses(t *testing.T) {
+	c, err := specdrift.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tags may be an edit per three letters off, up to two, and a tag
+	// matching one property is never taken for a typo of another.
+	type waypoint struct {
+		Symbol       string `json:"symbl"`
+		Kind         string `json:"tpye"`
+		SystemSymbol string `json:"sytemSymbl"`
+		Pos          int    `json:"id"`
+		Y            int    `json:"y"`
+	}
+	got := map[string]specdrift.Kind{}
+	for _, issue := range c.CheckType("Waypoint", waypoint{}) {
+		if issue.Kind != specdrift.MissingField || got[issue.Where] == "" {
+			got[issue.Where] = issue.Kind
+		}
+	}
+	for where, want := range map[string]specdrift.Kind{
+		"Waypoint.symbol":       specdrift.WrongTag,
+		"Waypoint.type":         specdrift.WrongTag,
+		"Waypoint.systemSymbol": specdrift.WrongTag,
+		"Waypoint.x":            specdrift.MissingField,
+		"Waypoint.id":           specdrift.UnknownField,
+	} {
+		if got[where] != want {
+			t.Errorf("%s = %q, want %q", where, got[where], want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package status
+
+import (
+	"context"
+	"net/http"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/strict"
+)
+
+type statusClient struct {
+	httpClient *http.Client
+	strict     *strict.Recorder
+}
+
+type statusClientOpts func(*statusClient)
+
+var (
+	defaultOpts = []statusClientOpts{
+		WithHTTPClient(http.DefaultClient),
+	}
+)
+
+func WithHTTPClient(httpClient *http.Client) statusClientOpts {
+	return func(c *statusClient) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithStrictDecoding checks every response against the SDK types and
+// records unknown or missing fields in recorder.
+func WithStrictDecoding(recorder *strict.Recorder) statusClientOpts {
+	return func(c *statusClient) {
+		c.strict = recorder
+	}
+}
+
+func NewStatus(opts ...statusClientOpts) *statusClient {
+	c := &statusClient{}
+
+	opts = append(defaultOpts, opts...)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *statusClient) rest() *rest.Client {
+	return &rest.Client{HTTPClient: c.httpClient, Strict: c.strict}
+}
+
+func (c *statusClient) do(ctx context.Context, req *rest.Request, out any) error {
+	_, err := c.rest().Do(ctx, req, out)
+	return err
+}
@@ -0,0 +1,63 @@
+// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.
+
+package status
+
+import (
+	"context"
+	"net/http"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/models"
+)
+
+type StatusClient interface {
+	// Return the status of the game server.
+	GetStatus(ctx context.Context, req *GetStatusRequest) (*GetStatusResponse, error)
+}
+
+type GetStatusRequest struct{}
+type GetStatusResponse struct {
+	models.RawResponse
+
+	// Status The current status of the game server.
+	Status string `json:"status"`
+	// Version The current version of the API.
+	Version string `json:"version"`
+	// ResetDate The date and time when the game server was last reset.
+	ResetDate   string `json:"resetDate"`
+	Description string `json:"description"`
+	Stats       struct {
+		Agents    int `json:"agents"`
+		Ships     int `json:"ships"`
+		Systems   int `json:"systems"`
+		Waypoints int `json:"waypoints"`
+	} `json:"stats"`
+	ServerResets struct {
+		// Next The date and time when the game server will reset.
+		Next string `json:"next"`
+		// Frequency How often we intend to reset the game server.
+		Frequency string `json:"frequency"`
+	} `json:"serverResets"`
+	Announcements []struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	} `json:"announcements"`
+	Links []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"links"`
+}
+
+// Return the status of the game server.
+func (c *statusClient) GetStatus(ctx context.Context, req *GetStatusRequest) (*GetStatusResponse, error) {
+	resp := &GetStatusResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetStatus",
+		Method:    http.MethodGet,
+		Path:      "/",
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,259 @@
+// Package strict notices when API responses stop matching the SDK types.
+//
+// encoding/json silently drops keys a struct doesn't model and leaves
+// absent fields zeroed, so renamed or added API fields go unnoticed. A
+// Recorder passed to a client's WithStrictDecoding option inspects every
+// response body and records, per operation, the keys the SDK doesn't know
+// about and the required fields the API didn't send. By default it only
+// records; set Fail to turn drift into an error.
+package strict
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Drift lists the JSON paths that didn't line up for one operation, e.g.
+// "data.nav.route.origin".
+type Drift struct {
+	// Unknown holds keys present in the response but not in the SDK type.
+	Unknown []string
+	// Missing holds required SDK fields absent from the response.
+	Missing []string
+}
+
+func (d Drift) Empty() bool {
+	return len(d.Unknown) == 0 && len(d.Missing) == 0
+}
+
+// Error is returned from a client call when a Recorder with Fail set sees
+// drift.
+type Error struct {
+	Operation string
+	Drift     Drift
+}
+
+func (e *Error) Error() string {
+	var parts []string
+	if len(e.Drift.Unknown) > 0 {
+		parts = append(parts, "unknown fields "+strings.Join(e.Drift.Unknown, ", "))
+	}
+	if len(e.Drift.Missing) > 0 {
+		parts = append(parts, "missing fields "+strings.Join(e.Drift.Missing, ", "))
+	}
+	return fmt.Sprintf("strict: %s response has %s", e.Operation, strings.Join(parts, "; "))
+}
+
+// Recorder accumulates drift across calls. It is safe for concurrent use
+// and can be shared between clients.
+type Recorder struct {
+	// Fail makes calls return *Error instead of only recording drift.
+	Fail bool
+
+	mu  sync.Mutex
+	ops map[string]*sets
+}
+
+type sets struct {
+	unknown map[string]bool
+	missing map[string]bool
+}
+
+// Observe checks body against the type of v, which must be the value the
+// body was decoded into, and records anything that doesn't match.
+func (r *Recorder) Observe(operation string, body []byte, v any) error {
+	d, err := Check(body, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	if d.Empty() {
+		return nil
+	}
+
+	r.mu.Lock()
+	if r.ops == nil {
+		r.ops = map[string]*sets{}
+	}
+	s, ok := r.ops[operation]
+	if !ok {
+		s = &sets{unknown: map[string]bool{}, missing: map[string]bool{}}
+		r.ops[operation] = s
+	}
+	for _, p := range d.Unknown {
+		s.unknown[p] = true
+	}
+	for _, p := range d.Missing {
+		s.missing[p] = true
+	}
+	r.mu.Unlock()
+
+	if r.Fail {
+		return &Error{Operation: operation, Drift: d}
+	}
+	return nil
+}
+
+// Drift returns what has been recorded for operation, e.g. "GetShip".
+func (r *Recorder) Drift(operation string) Drift {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.ops[operation]
+	if !ok {
+		return Drift{}
+	}
+	return Drift{Unknown: keys(s.unknown), Missing: keys(s.missing)}
+}
+
+// Operations returns everything recorded so far keyed by operation.
+func (r *Recorder) Operations() map[string]Drift {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]Drift, len(r.ops))
+	for op, s := range r.ops {
+		out[op] = Drift{Unknown: keys(s.unknown), Missing: keys(s.missing)}
+	}
+	return out
+}
+
+func keys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	rawMessageType  = reflect.TypeOf(json.RawMessage{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// Check compares a JSON document with the Go type it decodes into. Keys
+// are matched the way encoding/json matches them, case-insensitively, and
+// fields without omitempty are treated as required.
+func Check(body []byte, t reflect.Type) (Drift, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return Drift{}, err
+	}
+	c := &checker{unknown: map[string]bool{}, missing: map[string]bool{}}
+	c.walk("", doc, t)
+	return Drift{Unknown: keys(c.unknown), Missing: keys(c.missing)}, nil
+}
+
+type checker struct {
+	unknown map[string]bool
+	missing map[string]bool
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func (c *checker) walk(path string, v any, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType || t == rawMessageType || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		fields := fieldsOf(t)
+		seen := map[string]bool{}
+		for key, val := range v {
+			f, ok := lookup(fields, key)
+			if !ok {
+				c.unknown[join(path, key)] = true
+				continue
+			}
+			seen[f.name] = true
+			c.walk(join(path, f.name), val, f.typ)
+		}
+		for _, f := range fields {
+			if f.required && !seen[f.name] {
+				c.missing[join(path, f.name)] = true
+			}
+		}
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for _, elem := range v {
+			c.walk(path+"[]", elem, t.Elem())
+		}
+	}
+}
+
+type field struct {
+	name     string
+	typ      reflect.Type
+	required bool
+}
+
+// fieldsOf lists the JSON-visible fields of t, flattening embedded structs.
+func fieldsOf(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if f.Anonymous && !tagged {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, fieldsOf(ft)...)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, field{
+			name: name,
+			typ:  f.Type,
+			// Untagged fields are SDK bookkeeping, never expected on the wire.
+			required: tagged && !strings.Contains(opts, "omitempty"),
+		})
+	}
+	return fields
+}
+
+func lookup(fields []field, key string) (field, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
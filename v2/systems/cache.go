@@ -0,0 +1,419 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"spacetradersgo/v2/internal/wait"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/status"
+	"sync"
+	"time"
+)
+
+// Resource names a kind of cached response, each with its own TTL.
+type Resource string
+
+const (
+	// ResourceSystems is a page of ListSystems.
+	ResourceSystems Resource = "systems"
+	ResourceSystem  Resource = "system"
+	// ResourceWaypoints is a page of ListWaypoints.
+	ResourceWaypoints Resource = "waypoints"
+	ResourceWaypoint  Resource = "waypoint"
+	ResourceJumpGate  Resource = "jumpgate"
+
+	resourceMeta Resource = "meta"
+)
+
+// DefaultTTLs are how long each resource stays cached unless WithTTL says
+// otherwise. Systems and gates only change at a reset; waypoints pick up
+// charts and traits as the game goes on.
+var DefaultTTLs = map[Resource]time.Duration{
+	ResourceSystems:   7 * 24 * time.Hour,
+	ResourceSystem:    7 * 24 * time.Hour,
+	ResourceWaypoints: 24 * time.Hour,
+	ResourceWaypoint:  24 * time.Hour,
+	ResourceJumpGate:  7 * 24 * time.Hour,
+}
+
+// CachedSystems is a SystemsClient that answers ListSystems, GetSystem,
+// ListWaypoints, GetWaypoint and GetJumpGate from a Store while the cached
+// responses are fresh. Pages of ListSystems and ListWaypoints also fill the
+// cache for the systems and waypoints on them. Markets, shipyards and
+// purchases always go to the API.
+//
+// Cached responses keep the body they were stored with, so Raw works as
+// usual. Entries filled from a list page hold just {"data": ...}.
+type CachedSystems struct {
+	SystemsClient
+	store Store
+	ttls  map[Resource]time.Duration
+	rate  float64
+
+	errors func(error)
+
+	status     status.StatusClient
+	resetEvery time.Duration
+	mu         sync.Mutex
+	checked    time.Time
+}
+
+type cachedSystemsOpts func(*CachedSystems)
+
+var (
+	defaultCachedSystemsOpts = []cachedSystemsOpts{
+		WithSyncRate(2),
+	}
+)
+
+// WithTTL sets how long responses of resource stay cached. Zero turns
+// caching off for the resource and a negative TTL keeps entries until the
+// next reset.
+func WithTTL(resource Resource, ttl time.Duration) cachedSystemsOpts {
+	return func(c *CachedSystems) {
+		c.ttls[resource] = ttl
+	}
+}
+
+// WithResetCheck asks the server's status at most once per interval and
+// clears the cache when the reset date has changed since it was filled.
+func WithResetCheck(client status.StatusClient, every time.Duration) cachedSystemsOpts {
+	return func(c *CachedSystems) {
+		c.status = client
+		c.resetEvery = every
+	}
+}
+
+// WithCacheErrors calls fn with every error reading or writing the store,
+// and with failed reset checks. These never fail a request, which is
+// answered from the API instead, so without fn they go unnoticed.
+func WithCacheErrors(fn func(error)) cachedSystemsOpts {
+	return func(c *CachedSystems) {
+		c.errors = fn
+	}
+}
+
+// WithSyncRate sets how many requests per second Sync makes. The default of
+// 2 is the API's sustained limit.
+func WithSyncRate(perSecond float64) cachedSystemsOpts {
+	return func(c *CachedSystems) {
+		c.rate = perSecond
+	}
+}
+
+// NewCachedSystems wraps client with a cache kept in store.
+func NewCachedSystems(client SystemsClient, store Store, opts ...cachedSystemsOpts) *CachedSystems {
+	c := &CachedSystems{SystemsClient: client, store: store, ttls: map[Resource]time.Duration{}}
+	for r, ttl := range DefaultTTLs {
+		c.ttls[r] = ttl
+	}
+	for _, opt := range append(defaultCachedSystemsOpts, opts...) {
+		opt(c)
+	}
+	return c
+}
+
+func (c *CachedSystems) ListSystems(ctx context.Context, req *ListSystemsRequest) (*ListSystemsResponse, error) {
+	key := fmt.Sprintf("%d-%d", pageOf(req.Page), limitOf(req.NumPerPage))
+	return lookup(ctx, c, ResourceSystems, key, func() (*ListSystemsResponse, error) {
+		resp, err := c.SystemsClient.ListSystems(ctx, req)
+		if err == nil {
+			for _, s := range resp.Systems {
+				c.putData(ResourceSystem, s.Symbol, s)
+			}
+		}
+		return resp, err
+	})
+}
+
+func (c *CachedSystems) GetSystem(ctx context.Context, req *GetSystemRequest) (*GetSystemResponse, error) {
+	return lookup(ctx, c, ResourceSystem, req.SystemID, func() (*GetSystemResponse, error) {
+		return c.SystemsClient.GetSystem(ctx, req)
+	})
+}
+
+func (c *CachedSystems) ListWaypoints(ctx context.Context, req *ListWaypointsRequest) (*ListWaypointsResponse, error) {
+	key := fmt.Sprintf("%s-%d-%d", req.SystemID, pageOf(req.Page), limitOf(req.NumPerPage))
+	return lookup(ctx, c, ResourceWaypoints, key, func() (*ListWaypointsResponse, error) {
+		resp, err := c.SystemsClient.ListWaypoints(ctx, req)
+		if err == nil {
+			for _, wp := range resp.Waypoints {
+				c.putData(ResourceWaypoint, wp.Symbol, wp)
+			}
+		}
+		return resp, err
+	})
+}
+
+func (c *CachedSystems) GetWaypoint(ctx context.Context, req *GetWaypointRequest) (*GetWaypointResponse, error) {
+	return lookup(ctx, c, ResourceWaypoint, req.WaypointID, func() (*GetWaypointResponse, error) {
+		return c.SystemsClient.GetWaypoint(ctx, req)
+	})
+}
+
+func (c *CachedSystems) GetJumpGate(ctx context.Context, req *GetJumpGateRequest) (*GetJumpGateResponse, error) {
+	return lookup(ctx, c, ResourceJumpGate, req.WaypointID, func() (*GetJumpGateResponse, error) {
+		return c.SystemsClient.GetJumpGate(ctx, req)
+	})
+}
+
+// Clear empties the cache.
+func (c *CachedSystems) Clear() error {
+	return c.store.Clear()
+}
+
+// CheckReset compares the server's reset date with the one the cache was
+// filled under and clears the cache if they differ. It reports whether the
+// cache was cleared.
+func (c *CachedSystems) CheckReset(ctx context.Context) (bool, error) {
+	if c.status == nil {
+		return false, errors.New("systems: CheckReset needs WithResetCheck")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.checkReset(ctx)
+}
+
+func (c *CachedSystems) checkReset(ctx context.Context) (bool, error) {
+	c.checked = time.Now()
+	resp, err := c.status.GetStatus(ctx, &status.GetStatusRequest{})
+	if err != nil {
+		return false, err
+	}
+	current, _ := json.Marshal(resp.ResetDate)
+	known, ok, err := c.store.Get(resourceMeta, "reset")
+	if err != nil {
+		return false, err
+	}
+	if ok && string(known.Body) == string(current) {
+		return false, nil
+	}
+	// Without a recorded reset date the cache's age is unknown, so it is
+	// cleared as well.
+	if err := c.store.Clear(); err != nil {
+		return false, err
+	}
+	return true, c.store.Put(resourceMeta, "reset", Entry{Stored: time.Now(), Body: current})
+}
+
+// maybeCheckReset runs the reset check when it is due. A check that fails
+// is reported and leaves the cache as it is until the next one.
+func (c *CachedSystems) maybeCheckReset(ctx context.Context) {
+	if c.status == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checked.IsZero() && time.Since(c.checked) < c.resetEvery {
+		return
+	}
+	if _, err := c.checkReset(ctx); err != nil {
+		c.report(fmt.Errorf("systems: reset check: %w", err))
+	}
+}
+
+// report passes err to the WithCacheErrors hook, if there is one.
+func (c *CachedSystems) report(err error) {
+	if c.errors != nil {
+		c.errors(err)
+	}
+}
+
+func (c *CachedSystems) put(resource Resource, key string, body []byte) {
+	if err := c.store.Put(resource, key, Entry{Stored: time.Now(), Body: body}); err != nil {
+		c.report(fmt.Errorf("systems: caching %s %s: %w", resource, key, err))
+	}
+}
+
+type rawResponse interface {
+	Raw() []byte
+	SetRaw([]byte)
+}
+
+// lookup answers from the cache if it holds a fresh entry, and otherwise
+// calls fetch and stores what it returns. The cache failing to read or
+// write only costs an API request, and is reported to the WithCacheErrors
+// hook.
+func lookup[R any, P interface {
+	*R
+	rawResponse
+}](ctx context.Context, c *CachedSystems, resource Resource, key string, fetch func() (P, error)) (P, error) {
+	c.maybeCheckReset(ctx)
+	ttl := c.ttls[resource]
+	if ttl != 0 {
+		e, ok, err := c.store.Get(resource, key)
+		if err != nil {
+			c.report(fmt.Errorf("systems: reading cached %s %s: %w", resource, key, err))
+		}
+		if ok && (ttl < 0 || time.Since(e.Stored) < ttl) {
+			resp := P(new(R))
+			if json.Unmarshal(e.Body, resp) == nil {
+				resp.SetRaw(e.Body)
+				return resp, nil
+			}
+		}
+	}
+
+	var resp P
+	err := paced(ctx, func() (err error) {
+		resp, err = fetch()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ttl != 0 {
+		body := resp.Raw()
+		if len(body) == 0 {
+			body, _ = json.Marshal(resp)
+		}
+		c.put(resource, key, body)
+	}
+	return resp, nil
+}
+
+func (c *CachedSystems) putData(resource Resource, key string, data any) {
+	if c.ttls[resource] == 0 {
+		return
+	}
+	body, err := json.Marshal(struct {
+		Data any `json:"data"`
+	}{data})
+	if err != nil {
+		c.report(fmt.Errorf("systems: caching %s %s: %w", resource, key, err))
+		return
+	}
+	c.put(resource, key, body)
+}
+
+func pageOf(page int) int {
+	if page < 1 {
+		return 1
+	}
+	return page
+}
+
+func limitOf(limit int) int {
+	if limit < 1 {
+		return 10
+	}
+	return limit
+}
+
+// SyncStats counts what Sync cached and the requests it made to do so.
+type SyncStats struct {
+	Systems   int
+	Waypoints int
+	JumpGates int
+	Requests  int
+}
+
+type pacerKey struct{}
+
+// pacer spaces out requests and waits out 429s during a Sync.
+type pacer struct {
+	interval time.Duration
+	next     time.Time
+	requests int
+}
+
+// paced runs fetch, through the Sync pacer if ctx carries one.
+func paced(ctx context.Context, fetch func() error) error {
+	p, ok := ctx.Value(pacerKey{}).(*pacer)
+	if !ok {
+		return fetch()
+	}
+	for {
+		if err := wait.Until(ctx, p.next); err != nil {
+			return err
+		}
+		p.next = time.Now().Add(p.interval)
+		p.requests++
+		err := fetch()
+		wait, limited := retryAfter(err)
+		if !limited {
+			return err
+		}
+		p.next = time.Now().Add(wait)
+	}
+}
+
+// retryAfter reports whether err is a 429 and how long the API asked to
+// wait.
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *models.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 429 {
+		return 0, false
+	}
+	var data struct {
+		RetryAfter float64 `json:"retryAfter"`
+	}
+	json.Unmarshal(apiErr.Data, &data)
+	if data.RetryAfter <= 0 {
+		data.RetryAfter = 1
+	}
+	return time.Duration(data.RetryAfter * float64(time.Second)), true
+}
+
+// Sync caches the whole universe: every system, the waypoints of each and
+// every jump gate, at the sync rate and waiting out any 429s. Pages still
+// fresh in the cache aren't fetched again, so an interrupted Sync carries
+// on where it stopped. Gates that can't be seen yet, because no ship has
+// charted them, are skipped; any other error ends the Sync.
+func (c *CachedSystems) Sync(ctx context.Context, token string) (SyncStats, error) {
+	p := &pacer{}
+	if c.rate > 0 {
+		p.interval = time.Duration(float64(time.Second) / c.rate)
+	}
+	stats := &SyncStats{}
+	err := c.sync(context.WithValue(ctx, pacerKey{}, p), token, stats)
+	stats.Requests = p.requests
+	return *stats, err
+}
+
+func (c *CachedSystems) sync(ctx context.Context, token string, stats *SyncStats) error {
+	for page := 1; ; page++ {
+		resp, err := c.ListSystems(ctx, &ListSystemsRequest{Token: token, Page: page, NumPerPage: 20})
+		if err != nil {
+			return err
+		}
+		for _, sys := range resp.Systems {
+			stats.Systems++
+			if err := c.syncSystem(ctx, token, sys.Symbol, stats); err != nil {
+				return err
+			}
+		}
+		if len(resp.Systems) == 0 || page*20 >= resp.Meta.Total {
+			return nil
+		}
+	}
+}
+
+func (c *CachedSystems) syncSystem(ctx context.Context, token, system string, stats *SyncStats) error {
+	for page := 1; ; page++ {
+		resp, err := c.ListWaypoints(ctx, &ListWaypointsRequest{Token: token, SystemID: system, Page: page, NumPerPage: 20})
+		if err != nil {
+			return err
+		}
+		for _, wp := range resp.Waypoints {
+			stats.Waypoints++
+			if wp.Type != models.WaypointTypeJumpGate {
+				continue
+			}
+			_, err := c.GetJumpGate(ctx, &GetJumpGateRequest{Token: token, SystemID: system, WaypointID: wp.Symbol})
+			switch {
+			case err == nil:
+				stats.JumpGates++
+			case models.IsCode(err, models.ErrCodeWaypointNoAccess):
+				// Not charted yet.
+			default:
+				return err
+			}
+		}
+		if len(resp.Waypoints) == 0 || page*20 >= resp.Meta.Total {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package systems_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"spacetradersgo/v2/mocks"
+	"spacetradersgo/v2/models"
+	"spacetradersgo/v2/status"
+	"spacetradersgo/v2/systems"
+)
+
+// memStore is a Store in memory whose reads and writes can be made to fail.
+type memStore struct {
+	mu      sync.Mutex
+	entries map[systems.Resource]map[string]systems.Entry
+	getErr  error
+	putErr  error
+}
+
+func newMemStore() *memStore {
+	return &memStore{entries: map[systems.Resource]map[string]systems.Entry{}}
+}
+
+func (m *memStore) Get(resource systems.Resource, key string) (systems.Entry, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.getErr != nil {
+		return systems.Entry{}, false, m.getErr
+	}
+	e, ok := m.entries[resource][key]
+	return e, ok, nil
+}
+
+func (m *memStore) Put(resource systems.Resource, key string, entry systems.Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.putErr != nil {
+		return m.putErr
+	}
+	if m.entries[resource] == nil {
+		m.entries[resource] = map[string]systems.Entry{}
+	}
+	m.entries[resource][key] = entry
+	return nil
+}
+
+func (m *memStore) Clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = map[systems.Resource]map[string]systems.Entry{}
+	return nil
+}
+
+// age backdates the stored entry so it looks as old as d.
+func (m *memStore) age(t *testing.T, resource systems.Resource, key string, d time.Duration) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[resource][key]
+	if !ok {
+		t.Fatalf("no cached %s %s", resource, key)
+	}
+	e.Stored = time.Now().Add(-d)
+	m.entries[resource][key] = e
+}
+
+func TestCachedSystemsTTLs(t *testing.T) {
+	ctx := context.Background()
+	day := 24 * time.Hour
+	ttl := func(d time.Duration) *time.Duration { return &d }
+	tests := []struct {
+		name     string
+		resource systems.Resource
+		// ttl, when set, overrides the resource's default TTL.
+		ttl     *time.Duration
+		age     time.Duration
+		fetches int
+	}{
+		{"system fresh", systems.ResourceSystem, nil, 6 * day, 1},
+		{"system stale", systems.ResourceSystem, nil, 8 * day, 2},
+		{"waypoint fresh", systems.ResourceWaypoint, nil, 23 * time.Hour, 1},
+		{"waypoint stale", systems.ResourceWaypoint, nil, 25 * time.Hour, 2},
+		{"waypoint with a longer TTL", systems.ResourceWaypoint, ttl(2 * day), 25 * time.Hour, 1},
+		{"waypoint kept until reset", systems.ResourceWaypoint, ttl(-1), 365 * day, 1},
+		{"waypoint not cached", systems.ResourceWaypoint, ttl(0), 0, 2},
+		{"jump gate fresh", systems.ResourceJumpGate, nil, 6 * day, 1},
+		{"jump gate stale", systems.ResourceJumpGate, nil, 8 * day, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := mocks.NewSystemsClient()
+			api.OnGetSystem().Return(&systems.GetSystemResponse{System: models.System{Symbol: "X1-A"}}, nil)
+			api.OnGetWaypoint().Return(&systems.GetWaypointResponse{Waypoint: models.Waypoint{Symbol: "X1-A-B"}}, nil)
+			api.OnGetJumpGate().Return(&systems.GetJumpGateResponse{JumpGate: models.JumpGate{JumpRange: 2000}}, nil)
+			store := newMemStore()
+			c := systems.NewCachedSystems(api, store)
+			if tt.ttl != nil {
+				c = systems.NewCachedSystems(api, store, systems.WithTTL(tt.resource, *tt.ttl))
+			}
+
+			get := func() {
+				t.Helper()
+				var err error
+				switch tt.resource {
+				case systems.ResourceSystem:
+					var resp *systems.GetSystemResponse
+					resp, err = c.GetSystem(ctx, &systems.GetSystemRequest{SystemID: "X1-A"})
+					if err == nil && resp.System.Symbol != "X1-A" {
+						t.Errorf("GetSystem = %+v", resp.System)
+					}
+				case systems.ResourceWaypoint:
+					var resp *systems.GetWaypointResponse
+					resp, err = c.GetWaypoint(ctx, &systems.GetWaypointRequest{SystemID: "X1-A", WaypointID: "X1-A-B"})
+					if err == nil && resp.Waypoint.Symbol != "X1-A-B" {
+						t.Errorf("GetWaypoint = %+v", resp.Waypoint)
+					}
+				case systems.ResourceJumpGate:
+					var resp *systems.GetJumpGateResponse
+					resp, err = c.GetJumpGate(ctx, &systems.GetJumpGateRequest{SystemID: "X1-A", WaypointID: "X1-A-B"})
+					if err == nil && resp.JumpGate.JumpRange != 2000 {
+						t.Errorf("GetJumpGate = %+v", resp.JumpGate)
+					}
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			get()
+			if tt.age > 0 {
+				key := "X1-A-B"
+				if tt.resource == systems.ResourceSystem {
+					key = "X1-A"
+				}
+				store.age(t, tt.resource, key, tt.age)
+			}
+			get()
+			if got := len(api.Calls()); got != tt.fetches {
+				t.Errorf("made %d API calls, want %d", got, tt.fetches)
+			}
+		})
+	}
+}
+
+func TestCachedSystemsListFillsEntries(t *testing.T) {
+	ctx := context.Background()
+	api := mocks.NewSystemsClient()
+	api.OnListWaypoints().Return(&systems.ListWaypointsResponse{
+		Waypoints: []models.Waypoint{{Symbol: "X1-A-B"}, {Symbol: "X1-A-C"}},
+		Meta:      models.Meta{Total: 2, Page: 1, Limit: 20},
+	}, nil)
+	c := systems.NewCachedSystems(api, newMemStore())
+
+	if _, err := c.ListWaypoints(ctx, &systems.ListWaypointsRequest{SystemID: "X1-A", NumPerPage: 20}); err != nil {
+		t.Fatal(err)
+	}
+	wp, err := c.GetWaypoint(ctx, &systems.GetWaypointRequest{SystemID: "X1-A", WaypointID: "X1-A-C"})
+	if err != nil {
+		t.Fatalf("GetWaypoint: %v", err)
+	}
+	if wp.Waypoint.Symbol != "X1-A-C" || string(wp.Raw()) == "" {
+		t.Errorf("GetWaypoint = %+v with raw %q", wp.Waypoint, wp.Raw())
+	}
+	if n := len(api.OnGetWaypoint().Calls()); n != 0 {
+		t.Errorf("GetWaypoint reached the API %d times, want it answered from the list page", n)
+	}
+}
+
+func TestCachedSystemsResetCheck(t *testing.T) {
+	ctx := context.Background()
+	api := mocks.NewSystemsClient()
+	api.OnGetSystem().Return(&systems.GetSystemResponse{System: models.System{Symbol: "X1-A"}}, nil)
+	stat := mocks.NewStatusClient()
+	stat.OnGetStatus().Times(2).Return(&status.GetStatusResponse{ResetDate: "2023-06-01"}, nil)
+	stat.OnGetStatus().Return(&status.GetStatusResponse{ResetDate: "2023-06-17"}, nil)
+	c := systems.NewCachedSystems(api, newMemStore(), systems.WithResetCheck(stat, 0))
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetSystem(ctx, &systems.GetSystemRequest{SystemID: "X1-A"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first call finds no reset date and fetches; the second is
+	// cached; the third sees a new reset date and fetches again.
+	if n := len(api.OnGetSystem().Calls()); n != 2 {
+		t.Errorf("GetSystem reached the API %d times, want 2", n)
+	}
+
+	cleared, err := c.CheckReset(ctx)
+	if err != nil || cleared {
+		t.Errorf("CheckReset with the reset date unchanged = %v, %v; want false, nil", cleared, err)
+	}
+}
+
+func TestCachedSystemsReportsStoreErrors(t *testing.T) {
+	ctx := context.Background()
+	api := mocks.NewSystemsClient()
+	api.OnGetSystem().Return(&systems.GetSystemResponse{System: models.System{Symbol: "X1-A"}}, nil)
+	stat := mocks.NewStatusClient()
+	stat.OnGetStatus().Fail(&models.APIError{StatusCode: 502, Message: "bad gateway"})
+	store := newMemStore()
+	store.getErr = errors.New("disk on fire")
+	store.putErr = errors.New("disk full")
+	var reported []error
+	c := systems.NewCachedSystems(api, store, systems.WithResetCheck(stat, time.Hour), systems.WithCacheErrors(func(err error) {
+		reported = append(reported, err)
+	}))
+
+	resp, err := c.GetSystem(ctx, &systems.GetSystemRequest{SystemID: "X1-A"})
+	if err != nil || resp.System.Symbol != "X1-A" {
+		t.Fatalf("GetSystem = %+v, %v; want the API's answer despite the store", resp, err)
+	}
+	if len(reported) != 3 {
+		t.Fatalf("reported %v, want the reset check, the read and the write", reported)
+	}
+	var apiErr *models.APIError
+	if !errors.As(reported[0], &apiErr) || !errors.Is(reported[1], store.getErr) || !errors.Is(reported[2], store.putErr) {
+		t.Errorf("reported %v", reported)
+	}
+}
+
+func TestSyncSkipsOnlyUnchartedGates(t *testing.T) {
+	uncharted := &models.APIError{StatusCode: 400, Code: models.ErrCodeWaypointNoAccess, Message: "not charted"}
+	limited := &models.APIError{StatusCode: 429, Code: models.ErrCodeRateLimited, Message: "slow down", Data: json.RawMessage(`{"retryAfter":0.001}`)}
+	tests := []struct {
+		name    string
+		gate    func(*mocks.SystemsClient)
+		wantErr bool
+		gates   int
+	}{
+		{"charted", func(m *mocks.SystemsClient) {
+			m.OnGetJumpGate().Return(&systems.GetJumpGateResponse{}, nil)
+		}, false, 1},
+		{"uncharted", func(m *mocks.SystemsClient) {
+			m.OnGetJumpGate().Fail(uncharted)
+		}, false, 0},
+		{"rate limited then charted", func(m *mocks.SystemsClient) {
+			m.OnGetJumpGate().Times(1).Return(nil, limited)
+			m.OnGetJumpGate().Return(&systems.GetJumpGateResponse{}, nil)
+		}, false, 1},
+		{"unauthorized", func(m *mocks.SystemsClient) {
+			m.OnGetJumpGate().Fail(&models.APIError{StatusCode: 401, Code: models.ErrCodeUnauthorized})
+		}, true, 0},
+		{"server error", func(m *mocks.SystemsClient) {
+			m.OnGetJumpGate().Fail(&models.APIError{StatusCode: 503, Message: "maintenance"})
+		}, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := mocks.NewSystemsClient()
+			api.OnListSystems().Return(&systems.ListSystemsResponse{
+				Systems: []models.System{{Symbol: "X1-A"}},
+				Meta:    models.Meta{Total: 1, Page: 1, Limit: 20},
+			}, nil)
+			api.OnListWaypoints().Return(&systems.ListWaypointsResponse{
+				Waypoints: []models.Waypoint{{Symbol: "X1-A-P", Type: models.WaypointTypePlanet}, {Symbol: "X1-A-G", Type: models.WaypointTypeJumpGate}},
+				Meta:      models.Meta{Total: 2, Page: 1, Limit: 20},
+			}, nil)
+			tt.gate(api)
+			c := systems.NewCachedSystems(api, newMemStore(), systems.WithSyncRate(0))
+
+			stats, err := c.Sync(context.Background(), "token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Sync err = %v, want error %v", err, tt.wantErr)
+			}
+			if stats.Systems != 1 || stats.Waypoints != 2 || stats.JumpGates != tt.gates {
+				t.Errorf("Sync stats = %+v, want 1 system, 2 waypoints and %d gates", stats, tt.gates)
+			}
+		})
+	}
+}
+
+func TestDirStoreClearLeavesOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	store := systems.DirStore{Dir: dir}
+	for _, r := range []systems.Resource{systems.ResourceSystem, systems.ResourceWaypoint} {
+		if err := store.Put(r, "X1-A", systems.Entry{Stored: time.Now(), Body: json.RawMessage(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keep := []string{
+		filepath.Join(dir, "notes.txt"),
+		filepath.Join(dir, string(systems.ResourceSystem), "README"),
+		// A file named like a resource directory isn't one.
+		filepath.Join(dir, string(systems.ResourceJumpGate)),
+	}
+	for _, path := range keep {
+		if err := os.WriteFile(path, []byte("mine"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	for _, r := range []systems.Resource{systems.ResourceSystem, systems.ResourceWaypoint} {
+		if _, ok, err := store.Get(r, "X1-A"); ok || err != nil {
+			t.Errorf("%s X1-A after Clear: ok %v, err %v", r, ok, err)
+		}
+	}
+	for _, path := range keep {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Clear removed %s: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, string(systems.ResourceWaypoint))); !os.IsNotExist(err) {
+		t.Errorf("emptied waypoint directory still there: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Entry is a cached response body and when it was stored.
+type Entry struct {
+	Stored time.Time       `json:"stored"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// Store holds cached entries by resource type and key. A Store must be safe
+// for concurrent use.
+type Store interface {
+	// Get returns the entry, or false if there is none.
+	Get(resource Resource, key string) (Entry, bool, error)
+	Put(resource Resource, key string, entry Entry) error
+	// Clear removes every entry.
+	Clear() error
+}
+
+// resources are all the resource types a CachedSystems stores.
+var resources = []Resource{
+	ResourceSystems,
+	ResourceSystem,
+	ResourceWaypoints,
+	ResourceWaypoint,
+	ResourceJumpGate,
+	resourceMeta,
+}
+
+// DirStore is a Store on disk, keeping one JSON file per entry under a
+// directory per resource type. Entries are written to a temporary file and
+// renamed into place, so a crash leaves either the old entry or the new
+// one. Dir may be shared with other files, such as the user's cache
+// directory: the store only ever touches its resource directories and the
+// entries in them. It needs nothing beyond the standard library and
+// survives restarts, which is all a cache of a mostly static universe
+// needs.
+type DirStore struct {
+	Dir string
+}
+
+func (d DirStore) path(resource Resource, key string) string {
+	return filepath.Join(d.Dir, string(resource), url.PathEscape(key)+".json")
+}
+
+func (d DirStore) Get(resource Resource, key string) (Entry, bool, error) {
+	b, err := os.ReadFile(d.path(resource, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+	var e Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		// A corrupt entry is as good as a missing one; the next Put
+		// replaces it.
+		return Entry{}, false, nil
+	}
+	return e, true, nil
+}
+
+func (d DirStore) Put(resource Resource, key string, entry Entry) error {
+	path := d.path(resource, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Clear removes the entries of every resource type, and their directories
+// once empty. Anything else in Dir, or in the resource directories, is left
+// alone.
+func (d DirStore) Clear() error {
+	for _, resource := range resources {
+		dir := filepath.Join(d.Dir, string(resource))
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			name := f.Name()
+			if f.IsDir() || !(filepath.Ext(name) == ".json" || strings.HasPrefix(name, ".entry-")) {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		// A directory still holding files that aren't entries stays.
+		os.Remove(dir)
+	}
+	return nil
+}
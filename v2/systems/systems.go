@@ -2,14 +2,14 @@ package systems
 
 import (
 	"context"
-	"encoding/json"
-	"io"
 	"net/http"
-	"strconv"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/strict"
 )
 
 type systemsClient struct {
 	httpClient *http.Client
+	strict     *strict.Recorder
 }
 
 type systemsClientOpts func(*systemsClient)
@@ -26,6 +26,14 @@ func WithHTTPClient(httpClient *http.Client) systemsClientOpts {
 	}
 }
 
+// WithStrictDecoding checks every response against the SDK types and
+// records unknown or missing fields in recorder.
+func WithStrictDecoding(recorder *strict.Recorder) systemsClientOpts {
+	return func(c *systemsClient) {
+		c.strict = recorder
+	}
+}
+
 func NewSystems(opts ...systemsClientOpts) *systemsClient {
 	c := &systemsClient{}
 
@@ -38,158 +46,11 @@ func NewSystems(opts ...systemsClientOpts) *systemsClient {
 	return c
 }
 
-func (c *systemsClient) ListSystems(ctx context.Context, req *ListSystemsRequest) (*ListSystemsResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/systems", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-	values := httpReq.URL.Query()
-	if req.NumPerPage != 0 {
-		values.Set("limit", strconv.Itoa(req.NumPerPage))
-	}
-	if req.Page != 0 {
-		values.Set("page", strconv.Itoa(req.Page))
-	}
-	httpReq.URL.RawQuery = values.Encode()
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &ListSystemsResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *systemsClient) GetSystem(ctx context.Context, req *GetSystemRequest) (*GetSystemResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/systems/"+req.SystemID, nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetSystemResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *systemsClient) ListWaypoints(ctx context.Context, req *ListWaypointsRequest) (*ListWaypointsResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/systems/"+req.SystemID+"/waypoints", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-	values := httpReq.URL.Query()
-	if req.NumPerPage != 0 {
-		values.Set("limit", strconv.Itoa(req.NumPerPage))
-	}
-	if req.Page != 0 {
-		values.Set("page", strconv.Itoa(req.Page))
-	}
-	httpReq.URL.RawQuery = values.Encode()
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &ListWaypointsResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
-
-func (c *systemsClient) GetWaypoint(ctx context.Context, req *GetWaypointRequest) (*GetWaypointResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/systems/"+req.SystemID+"/waypoints/"+req.WaypointID, nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetWaypointResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (c *systemsClient) rest() *rest.Client {
+	return &rest.Client{HTTPClient: c.httpClient, Strict: c.strict}
 }
 
-func (c *systemsClient) GetMarket(ctx context.Context, req *GetMarketRequest) (*GetMarketResponse, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, "https://api.spacetraders.io/v2/systems/"+req.SystemID+"/waypoints/"+req.WaypointID+"/market", nil)
-	if err != nil {
-		return nil, err
-	}
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+req.Token)
-
-	httpResp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResp.Body.Close()
-
-	body, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	resp := &GetMarketResponse{}
-	err = json.Unmarshal(body, resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
+func (c *systemsClient) do(ctx context.Context, req *rest.Request, out any) error {
+	_, err := c.rest().Do(ctx, req, out)
+	return err
 }
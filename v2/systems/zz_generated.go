@@ -0,0 +1,293 @@
+// Code generated by internal/gen from openapi/spacetraders.json. DO NOT EDIT.
+
+package systems
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"spacetradersgo/v2/internal/rest"
+	"spacetradersgo/v2/models"
+	"strconv"
+)
+
+type SystemsClient interface {
+	// Purchase a ship.
+	PurchaseShip(ctx context.Context, req *PurchaseShipRequest) (*PurchaseShipResponse, error)
+	// Return a list of all systems.
+	ListSystems(ctx context.Context, req *ListSystemsRequest) (*ListSystemsResponse, error)
+	// Get the details of a system.
+	GetSystem(ctx context.Context, req *GetSystemRequest) (*GetSystemResponse, error)
+	// Fetch all of the waypoints for a given system.
+	ListWaypoints(ctx context.Context, req *ListWaypointsRequest) (*ListWaypointsResponse, error)
+	// View the details of a waypoint.
+	GetWaypoint(ctx context.Context, req *GetWaypointRequest) (*GetWaypointResponse, error)
+	// Retrieve imports, exports and exchange data from a marketplace.
+	GetMarket(ctx context.Context, req *GetMarketRequest) (*GetMarketResponse, error)
+	// Get the shipyard for a waypoint.
+	GetShipyard(ctx context.Context, req *GetShipyardRequest) (*GetShipyardResponse, error)
+	// Get jump gate details for a waypoint.
+	GetJumpGate(ctx context.Context, req *GetJumpGateRequest) (*GetJumpGateResponse, error)
+}
+
+type PurchaseShipRequest struct {
+	Token    string          `json:"-"`
+	ShipType models.ShipType `json:"shipType"`
+	// WaypointSymbol The symbol of the waypoint you want to purchase the ship at.
+	WaypointSymbol string `json:"waypointSymbol"`
+}
+type PurchaseShipResponse struct {
+	models.RawResponse
+
+	Data struct {
+		Agent       models.Agent               `json:"agent"`
+		Ship        models.Ship                `json:"ship"`
+		Transaction models.ShipyardTransaction `json:"transaction"`
+	} `json:"data"`
+}
+
+type ListSystemsRequest struct {
+	Token string
+	// What entry offset to request
+	// >= 1
+	Page int
+	// How many entries to return per page
+	// >= 1 <= 20
+	NumPerPage int
+}
+type ListSystemsResponse struct {
+	models.RawResponse
+
+	Systems []models.System `json:"data"`
+	Meta    models.Meta     `json:"meta"`
+}
+
+type GetSystemRequest struct {
+	Token string
+	// The system symbol
+	SystemID string
+}
+type GetSystemResponse struct {
+	models.RawResponse
+
+	System models.System `json:"data"`
+}
+
+type ListWaypointsRequest struct {
+	Token string
+	// The system symbol
+	SystemID string
+	// What entry offset to request
+	// >= 1
+	Page int
+	// How many entries to return per page
+	// >= 1 <= 20
+	NumPerPage int
+}
+type ListWaypointsResponse struct {
+	models.RawResponse
+
+	Waypoints []models.Waypoint `json:"data"`
+	Meta      models.Meta       `json:"meta"`
+}
+
+type GetWaypointRequest struct {
+	Token string
+	// The system symbol
+	SystemID string
+	// The waypoint symbol
+	WaypointID string
+}
+type GetWaypointResponse struct {
+	models.RawResponse
+
+	Waypoint models.Waypoint `json:"data"`
+}
+
+type GetMarketRequest struct {
+	Token string
+	// The system symbol
+	SystemID string
+	// The waypoint symbol
+	WaypointID string
+}
+type GetMarketResponse struct {
+	models.RawResponse
+
+	Market models.Market `json:"data"`
+}
+
+type GetShipyardRequest struct {
+	Token string
+	// The system symbol
+	SystemID string
+	// The waypoint symbol
+	WaypointID string
+}
+type GetShipyardResponse struct {
+	models.RawResponse
+
+	Shipyard models.Shipyard `json:"data"`
+}
+
+type GetJumpGateRequest struct {
+	Token string
+	// The system symbol
+	SystemID string
+	// The waypoint symbol
+	WaypointID string
+}
+type GetJumpGateResponse struct {
+	models.RawResponse
+
+	JumpGate models.JumpGate `json:"data"`
+}
+
+// Purchase a ship.
+func (c *systemsClient) PurchaseShip(ctx context.Context, req *PurchaseShipRequest) (*PurchaseShipResponse, error) {
+	resp := &PurchaseShipResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "PurchaseShip",
+		Method:    http.MethodPost,
+		Path:      "/my/ships",
+		Token:     req.Token,
+		Body:      req,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Return a list of all systems.
+func (c *systemsClient) ListSystems(ctx context.Context, req *ListSystemsRequest) (*ListSystemsResponse, error) {
+	query := url.Values{}
+	if req.Page != 0 {
+		query.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.NumPerPage != 0 {
+		query.Set("limit", strconv.Itoa(req.NumPerPage))
+	}
+
+	resp := &ListSystemsResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "ListSystems",
+		Method:    http.MethodGet,
+		Path:      "/systems",
+		Query:     query,
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get the details of a system.
+func (c *systemsClient) GetSystem(ctx context.Context, req *GetSystemRequest) (*GetSystemResponse, error) {
+	resp := &GetSystemResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetSystem",
+		Method:    http.MethodGet,
+		Path:      "/systems/" + url.PathEscape(req.SystemID),
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Fetch all of the waypoints for a given system. System must be charted or a ship must be present to return waypoint details.
+func (c *systemsClient) ListWaypoints(ctx context.Context, req *ListWaypointsRequest) (*ListWaypointsResponse, error) {
+	query := url.Values{}
+	if req.Page != 0 {
+		query.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.NumPerPage != 0 {
+		query.Set("limit", strconv.Itoa(req.NumPerPage))
+	}
+
+	resp := &ListWaypointsResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "ListWaypoints",
+		Method:    http.MethodGet,
+		Path:      "/systems/" + url.PathEscape(req.SystemID) + "/waypoints",
+		Query:     query,
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// View the details of a waypoint.
+func (c *systemsClient) GetWaypoint(ctx context.Context, req *GetWaypointRequest) (*GetWaypointResponse, error) {
+	resp := &GetWaypointResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetWaypoint",
+		Method:    http.MethodGet,
+		Path:      "/systems/" + url.PathEscape(req.SystemID) + "/waypoints/" + url.PathEscape(req.WaypointID),
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Retrieve imports, exports and exchange data from a marketplace. Imports can be sold, exports can be purchased, and exchange goods can be purchased or sold.
+//
+// Send a ship to the waypoint to access trade good prices and recent transactions.
+func (c *systemsClient) GetMarket(ctx context.Context, req *GetMarketRequest) (*GetMarketResponse, error) {
+	resp := &GetMarketResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetMarket",
+		Method:    http.MethodGet,
+		Path:      "/systems/" + url.PathEscape(req.SystemID) + "/waypoints/" + url.PathEscape(req.WaypointID) + "/market",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get the shipyard for a waypoint. Send a ship to the waypoint to access ships that are currently available for purchase and recent transactions.
+func (c *systemsClient) GetShipyard(ctx context.Context, req *GetShipyardRequest) (*GetShipyardResponse, error) {
+	resp := &GetShipyardResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetShipyard",
+		Method:    http.MethodGet,
+		Path:      "/systems/" + url.PathEscape(req.SystemID) + "/waypoints/" + url.PathEscape(req.WaypointID) + "/shipyard",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Get jump gate details for a waypoint.
+func (c *systemsClient) GetJumpGate(ctx context.Context, req *GetJumpGateRequest) (*GetJumpGateResponse, error) {
+	resp := &GetJumpGateResponse{}
+	err := c.do(ctx, &rest.Request{
+		Operation: "GetJumpGate",
+		Method:    http.MethodGet,
+		Path:      "/systems/" + url.PathEscape(req.SystemID) + "/waypoints/" + url.PathEscape(req.WaypointID) + "/jump-gate",
+		Token:     req.Token,
+	}, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
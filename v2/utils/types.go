@@ -1,13 +1,9 @@
 package utils
 
-type Meta struct {
-	Total int `json:"total"`
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
-}
+import "spacetradersgo/v2/models"
 
-type Traits struct {
-	Symbol      string `json:"symbol"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-}
+// Deprecated: use models.Meta.
+type Meta = models.Meta
+
+// Deprecated: use models.WaypointTrait or models.FactionTrait.
+type Traits = models.WaypointTrait